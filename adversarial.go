@@ -0,0 +1,49 @@
+package main
+
+// Tricky but valid text, it stresses the encoding of the loader and the
+// parsing on the application reading the data back, the texts with the
+// --delimiter are added when they are picked
+var adversarialTexts = []string{
+	`He said "hello"`,
+	`it's a 'quoted' word`,
+	`""`,
+	"first line\nsecond line",
+	"windows\r\nline ending",
+	"\n",
+	"tab\tseparated\tvalues",
+	"  leading and trailing spaces  ",
+	" ",
+	"😀🎉🚀 emoji",
+	"ünïcödé ñ 日本語 العربية",
+	`back\slash \t \n`,
+	`\N`,
+	`\.`,
+	`NULL`,
+	"comma, separated, values",
+	`{"json": "like", "array": [1, 2]}`,
+	`<b>markup &amp; entities</b>`,
+	"\x01control\x01character\x01",
+	`'; DROP TABLE users; --`,
+}
+
+// Text data types, i.e the ones that gets the adversarial content
+func isTextDatatype(dt string) bool {
+	if isItArray, _ := isDataTypeAnArray(dt); isItArray {
+		return false
+	}
+	return StringHasPrefix(dt, []string{"character", "text", "citext"})
+}
+
+// Should the value of the column be replaced by adversarial text
+func isAdversarialText(dt string) bool {
+	if cmdOptions.AdversarialTextRate <= 0 || !isTextDatatype(dt) {
+		return false
+	}
+	return r.Float64() < cmdOptions.AdversarialTextRate
+}
+
+// Pick an adversarial text that fits the length of the column
+func adversarialText(dt string) (interface{}, error) {
+	texts := append([]string{`a` + delimiter + `b` + delimiter + `c`, delimiter}, adversarialTexts...)
+	return fitText(dt, RandomPickerFromArray(texts))
+}
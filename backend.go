@@ -0,0 +1,396 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The database the rows are loaded to. The worker reads the tables and
+// their columns and writes the rows through it, so postgres (along with
+// greenplum) and the databases reached with database/sql load the same way
+type backend interface {
+	// Connect to the database and print its version
+	connect()
+
+	// Tables of the schema, of all the schemas when its empty. The names if
+	// given are "schema.table" or the table of the default schema
+	tables(schema string, names []string) []DBTables
+
+	// Columns of the table along with the rules the catalog has on them
+	columns(t DBTables) []DBColumns
+
+	// Columns that alone make a primary or unique key of the table
+	singleColumnKeys(tab string) []string
+
+	// Highest value of the integer column, 0 when the table has no rows
+	columnMax(tab, column string) int64
+
+	// Distinct keys of the referenced column
+	referencedKeys(tab, column string) []string
+
+	// Destination of the rows of the table
+	newWriter(t TableCollection, tab string, col []string) (rowWriter, error)
+
+	// Run the statement, within the single transaction when there is one
+	exec(stmt string) error
+
+	// Name of the table as the statements refer to it
+	tableName(tab string) string
+
+	// Statement that inserts a row of only the default values
+	insertDefaults(tab string) string
+
+	// Save the constraints of the database before the tables are loaded,
+	// remove those of each table before its loaded and recreate them once
+	// all are loaded. The backends that load with the constraints in place
+	// leave them be
+	backupConstraints()
+	removeConstraints(tab string)
+	restoreConstraints()
+}
+
+// A database other than postgres and greenplum, reached with database/sql.
+// The tables are loaded with INSERT and with their constraints in place, so
+// only the columns, the keys and the rows go through the dialect. The data
+// types of the columns are named as the postgres ones the generators know
+type sqlDialect interface {
+	// Open the connection pool of the database, the sessions have the
+	// foreign key checks off
+	open() (*sql.DB, error)
+
+	// The query of the version of the database
+	versionQuery() string
+
+	// Schema of the tables given without one
+	defaultSchema(db *sql.DB) (string, error)
+
+	// Tables of the schema, of all the schemas of the database when its empty
+	tables(db *sql.DB, schema string) ([]DBTables, error)
+
+	// Columns of the table, the Sequence is the default of the column and
+	// autoIncrement on the auto increment ones
+	columns(db *sql.DB, t DBTables) ([]DBColumns, error)
+
+	// Columns that alone make a primary or unique key of the table
+	singleColumnKeys(db *sql.DB, t DBTables) ([]string, error)
+
+	// Quote the name of the table or the column
+	quote(name string) string
+
+	// Statement that inserts a row of only the default values
+	insertDefaults(tab string) string
+
+	// Most bind parameters of a statement
+	maxParams() int
+}
+
+// The Sequence of the auto increment columns, they are left to the database
+// the same as the serial columns of postgres
+const autoIncrement = "auto_increment"
+
+var (
+	backends = make(map[string]sqlDialect)
+
+	// The backend of --backend, the connection pool and the dialect are
+	// of the database/sql ones
+	activeBackend backend = postgresBackend{}
+	activeDialect sqlDialect
+	backendDB     *sql.DB
+)
+
+// Register the dialect of a backend, called from the init of the backend files
+func registerBackend(name string, d sqlDialect) {
+	backends[name] = d
+}
+
+// Names of the backends along with postgres
+func backendNames() []string {
+	names := []string{"postgres"}
+	for n := range backends {
+		names = append(names, n)
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// Is the data loaded to postgres or greenplum, for what only postgres has
+// i.e the citus tables or the enums of the catalog
+func isPostgresBackend() bool {
+	_, ok := activeBackend.(postgresBackend)
+	return ok
+}
+
+// The flags that only work on postgres, they read or change the catalog,
+// the constraints or the sessions of postgres
+var postgresOnlyFlags = []string{"connection-pool-warmup", "doc-output", "explain", "export-keys", "import-keys",
+	"inheritance", "insert-returning", "interactive", "nullability-from-sample", "override-sequences", "pooler",
+	"probe-types", "respect-fk", "single-transaction", "top-up-to", "transactional", "verify-foreign-keys",
+	"verify-stats", "violate-constraint", "create-db", "create-tables"}
+
+// Pick the backend of --backend and check the flags set work on it
+func checkBackend(changed func(flag string) bool, command string) {
+	if cmdOptions.Backend == "postgres" {
+		if command != "custom" && changed("file") {
+			Fatalf("Argument Error: --file is the database file of --backend sqlite, the postgres database " +
+				"is given with the connection flags or --uri")
+		}
+		return
+	}
+	d, ok := backends[cmdOptions.Backend]
+	if !ok {
+		Fatalf("Argument Error: --backend can only be one of %s", strings.Join(backendNames(), ", "))
+	}
+	if command == "custom" {
+		Fatalf("Argument Error: the custom command only works on postgres, not on --backend %s", cmdOptions.Backend)
+	}
+	for _, f := range postgresOnlyFlags {
+		if changed(f) {
+			Fatalf("Argument Error: --%s only works on postgres, not on --backend %s", f, cmdOptions.Backend)
+		}
+	}
+	activeBackend, activeDialect = sqlBackend{}, d
+}
+
+// The backends reached with database/sql, through the dialect of --backend
+type sqlBackend struct{}
+
+// Connect to the database of the backend and print its version
+func (sqlBackend) connect() {
+	Debugf("Connecting to the %s database", cmdOptions.Backend)
+	db, err := activeDialect.open()
+	if err == nil {
+		err = db.Ping()
+	}
+	if err != nil {
+		Fatalf("Encountered error when connecting to the %s database, err: %v", cmdOptions.Backend, err)
+	}
+	backendDB = db
+	var version string
+	if err := db.QueryRow(activeDialect.versionQuery()).Scan(&version); err != nil {
+		Fatalf("Encountered error when connecting to the %s database, err: %v", cmdOptions.Backend, err)
+	}
+	Infof("Version of the %s database: %s", cmdOptions.Backend, version)
+}
+
+// The schema of the table when its not given, the postgres default
+// public is the default schema of the backend
+func backendSchema(schema string) string {
+	if schema != "public" {
+		return schema
+	}
+	s, err := activeDialect.defaultSchema(backendDB)
+	if err != nil {
+		Fatalf("Error when extracting the default schema of the %s database, err: %v", cmdOptions.Backend, err)
+	}
+	return s
+}
+
+// Tables of the schema on the backend, all the tables when its empty. The
+// names if given are "schema.table" or the table of the default schema
+func (sqlBackend) tables(schema string, names []string) []DBTables {
+	Infof("Extracting the tables in the %s database", cmdOptions.Backend)
+	if len(names) == 0 {
+		if !IsStringEmpty(schema) {
+			schema = backendSchema(schema)
+		}
+		tables, err := activeDialect.tables(backendDB, schema)
+		if err != nil {
+			Fatalf("Error when extracting the tables of the %s database, err: %v", cmdOptions.Backend, err)
+		}
+		return tables
+	}
+	var tables []DBTables
+	schemas, found := make(map[string]bool), make(map[string]bool)
+	for _, name := range names {
+		s, t := backendSchema(cmdOptions.Tab.SchemaName), strings.TrimSpace(name)
+		if i := strings.Index(t, "."); i >= 0 {
+			s, t = t[:i], t[i+1:]
+		}
+		if !schemas[s] {
+			all, err := activeDialect.tables(backendDB, s)
+			if err != nil {
+				Fatalf("Error when extracting the tables of the %s database, err: %v", cmdOptions.Backend, err)
+			}
+			for _, a := range all {
+				found[GenerateTableName(a.Table, a.Schema)] = true
+			}
+			schemas[s] = true
+		}
+		if !found[GenerateTableName(t, s)] {
+			Warnf("Table %s.%s is not found on the %s database", s, t, cmdOptions.Backend)
+			continue
+		}
+		tables = append(tables, DBTables{Schema: s, Table: t})
+	}
+	return tables
+}
+
+// Columns of the table on the backend
+func (sqlBackend) columns(t DBTables) []DBColumns {
+	columns, err := activeDialect.columns(backendDB, t)
+	if err != nil {
+		Fatalf("Error when extracting the columns of table %s, err: %v", GenerateTableName(t.Table, t.Schema), err)
+	}
+	return columns
+}
+
+// Columns that alone make a primary or unique key of the table on the backend
+func (sqlBackend) singleColumnKeys(tab string) []string {
+	s, t := splitTableName(tab)
+	keys, err := activeDialect.singleColumnKeys(backendDB, DBTables{Schema: s, Table: t})
+	if err != nil {
+		Fatalf("Error when extracting the keys of table %s, err: %v", tab, err)
+	}
+	return keys
+}
+
+// The "schema"."table" name quoted for the backend
+func backendTableName(tab string) string {
+	s, t := splitTableName(tab)
+	return activeDialect.quote(s) + "." + activeDialect.quote(t)
+}
+
+func (sqlBackend) tableName(tab string) string {
+	return backendTableName(tab)
+}
+
+func (sqlBackend) insertDefaults(tab string) string {
+	return activeDialect.insertDefaults(tab)
+}
+
+// Highest value of the integer column on the backend, 0 when the table has no rows
+func (sqlBackend) columnMax(tab, column string) int64 {
+	var max int64
+	query := fmt.Sprintf("SELECT COALESCE(MAX(%s), 0) FROM %s", activeDialect.quote(column), backendTableName(tab))
+	if err := backendDB.QueryRow(query).Scan(&max); err != nil {
+		Debugf("query: %s", query)
+		Fatalf("Error when extracting the highest value of the column %s of table %s, err: %v", column, tab, err)
+	}
+	return max
+}
+
+// Distinct keys of the referenced column on the backend
+func (sqlBackend) referencedKeys(tab, column string) []string {
+	Debugf("Extracting the keys of the column %s from the referenced table %s", column, tab)
+	query := fmt.Sprintf("SELECT DISTINCT %[1]s FROM %[2]s WHERE %[1]s IS NOT NULL", activeDialect.quote(column),
+		backendTableName(tab))
+	rows, err := backendDB.Query(query)
+	if err == nil {
+		defer rows.Close()
+	}
+	var keys []string
+	for err == nil && rows.Next() {
+		var k string
+		if err = rows.Scan(&k); err == nil {
+			keys = append(keys, k)
+		}
+	}
+	if err == nil {
+		err = rows.Err()
+	}
+	if err != nil {
+		Debugf("query: %s", query)
+		Fatalf("Error when extracting the keys of the referenced table %s, err: %v", tab, err)
+	}
+	return keys
+}
+
+// Run the statement on the backend
+func (sqlBackend) exec(stmt string) error {
+	_, err := backendDB.Exec(stmt)
+	return err
+}
+
+// The tables are loaded with their constraints in place
+func (sqlBackend) backupConstraints()           {}
+func (sqlBackend) removeConstraints(tab string) {}
+func (sqlBackend) restoreConstraints()          {}
+
+// The rows are inserted with multi row INSERTs
+func (sqlBackend) newWriter(t TableCollection, tab string, col []string) (rowWriter, error) {
+	return newBackendWriter(tab, col)
+}
+
+// Load the rows to the table on the backend with multi row INSERTs of up
+// to --batch-size rows, fewer when the columns would go past the most bind
+// parameters of a statement
+type backendWriter struct {
+	tab     string
+	col     []string
+	conn    *sql.Conn
+	batch   [][]string
+	rows    int // per INSERT
+	flushed func(rows int)
+}
+
+// Open a connection of the pool for the writer
+func newBackendWriter(tab string, col []string) (*backendWriter, error) {
+	conn, err := backendDB.Conn(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("opening the database connection: %v", err)
+	}
+	rows := cmdOptions.BatchSize
+	if max := activeDialect.maxParams() / len(col); rows > max {
+		rows = max
+	}
+	return &backendWriter{tab: tab, col: col, conn: conn, rows: rows}, nil
+}
+
+// Buffer the row and insert the batch once its full
+func (w *backendWriter) Write(data []string) error {
+	w.batch = append(w.batch, data)
+	if len(w.batch) < w.rows {
+		return nil
+	}
+	return w.flush()
+}
+
+// Report the flushed rows to the function
+func (w *backendWriter) Flushed(f func(rows int)) {
+	w.flushed = f
+}
+
+// Insert the buffered rows, the NULLs are bound as nil
+func (w *backendWriter) flush() error {
+	if len(w.batch) == 0 {
+		return nil
+	}
+	defer func() { w.batch = w.batch[:0] }()
+	var columns []string
+	for _, c := range w.col {
+		columns = append(columns, activeDialect.quote(c))
+	}
+	row := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(w.col)), ", ") + ")"
+	rows := make([]string, len(w.batch))
+	var params []interface{}
+	for i, data := range w.batch {
+		rows[i] = row
+		for _, d := range data {
+			if d == nullValue {
+				params = append(params, nil)
+			} else {
+				params = append(params, d)
+			}
+		}
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", backendTableName(w.tab), strings.Join(columns, ", "),
+		strings.Join(rows, ", "))
+	if _, err := w.conn.ExecContext(context.Background(), query, params...); err != nil {
+		Debugf("Table: %s", w.tab)
+		Debugf("Data: batch of %d rows", len(w.batch))
+		return fmt.Errorf("inserting data: %v", err)
+	}
+	if w.flushed != nil {
+		w.flushed(len(w.batch))
+	}
+	return nil
+}
+
+// Insert the last partial batch and give the connection back to the pool
+func (w *backendWriter) Close() error {
+	defer w.conn.Close()
+	return w.flush()
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	registerGenerator("boolean",
+		"Booleans that are true on \"true_percent: <percent>\" of the rows (default 50) i.e 5 for an is_deleted, "+
+			"the integer booleans of the other backends are 1 or 0, set by the true_percent when there is no generator",
+		buildBooleanPercent)
+}
+
+// Validate the share of the true values of the boolean rule
+func validateBooleanRule(c *ColumnRule) error {
+	if c.TruePercent == nil {
+		c.TruePercent = new(float64)
+		*c.TruePercent = 50
+	}
+	if *c.TruePercent < 0 || *c.TruePercent > 100 {
+		return fmt.Errorf("true_percent should be between 0 and 100, got %v", *c.TruePercent)
+	}
+	return nil
+}
+
+// Boolean generator, true on the true_percent of the rows. The booleans of
+// mysql and sqlite are integer columns, so they get 1 or 0
+func buildBooleanPercent(ctx *generatorContext) (interface{}, error) {
+	dt := ctx.Column.Datatype
+	value := r.Float64()*100 < *ctx.Rule.TruePercent
+	switch {
+	case dt == "boolean":
+		return value, nil
+	case StringHasPrefix(dt, intKeywords) && !strings.HasSuffix(dt, "[]"):
+		if value {
+			return "1", nil
+		}
+		return "0", nil
+	}
+	return "", fmt.Errorf("boolean generator only supports the boolean columns, got %s", dt)
+}
@@ -23,7 +23,7 @@ do
         output_name+='.exe'
     fi
 
-    # Build the package using go build
+    # Build the package using go build, the cross compiled ones are without cgo and so without the sqlite backend
     env GOOS=${GOOS} GOARCH=${GOARCH} go build -o ${output_name}
     if [[ $? -ne 0 ]]; then
         echo 'An error has occurred! Aborting the script execution...'
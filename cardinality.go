@@ -0,0 +1,134 @@
+package main
+
+import (
+	"math"
+	"strings"
+)
+
+// Longest character columns whose distinct values are estimated, the
+// longer ones have more values than any table can hold
+const cardinalityMaxCharLength = 5
+
+// The most rows of the tables whose keys cannot have as many distinct
+// values as the rows asked for
+var uniqueRowLimits = make(map[string]int)
+
+// Check that the primary and unique keys of the tables can have as many
+// distinct values as the rows to mock, else the fixing of the keys would
+// retry without an end. With --on-error continue the rows of the table are
+// reduced to the distinct values, else its an error
+func checkUniqueCardinality(tables []TableCollection) {
+	if !isPostgresBackend() {
+		return
+	}
+	for _, contype := range []string{"p", "u"} {
+		for _, con := range GetPGConstraintDDL(contype) {
+			var t *TableCollection
+			for i := range tables {
+				if GenerateTableName(tables[i].Table, tables[i].Schema) == con.Tablename {
+					t = &tables[i]
+				}
+			}
+			if t == nil {
+				continue
+			}
+			cols, err := ColExtractor(con.Constraintkey, `\(([^\[\]]*)\)`)
+			if err != nil {
+				continue
+			}
+			columns := constraintColumns(cols)
+			cardinality := 1.0
+			for _, column := range columns {
+				c, ok := keyColumn(*t, column)
+				if !ok {
+					cardinality = math.Inf(1) // a column left to the database i.e a serial
+					break
+				}
+				n, ok := estimateCardinality(con.Tablename, c)
+				if !ok {
+					cardinality = math.Inf(1)
+					break
+				}
+				cardinality *= n
+			}
+			rows := rowsToMock(con.Tablename)
+			if float64(rows) <= cardinality {
+				continue
+			}
+			limit := int(cardinality)
+			if !cmdOptions.ContinueOnError {
+				Fatalf("The key %s (%s) of table %s can only have about %d distinct values, fewer than the %d rows "+
+					"to mock; lower the rows or use \"--on-error continue\" to mock %d rows",
+					con.Constraintname, strings.Join(columns, ", "), con.Tablename, limit, rows, limit)
+			}
+			Warnf("The key %s (%s) of table %s can only have about %d distinct values, mocking %d rows "+
+				"instead of %d", con.Constraintname, strings.Join(columns, ", "), con.Tablename, limit, limit, rows)
+			uniqueRowLimits[con.Tablename] = limit
+		}
+	}
+}
+
+// The generated column of the table
+func keyColumn(t TableCollection, column string) (DBColumns, bool) {
+	for _, c := range t.Columns {
+		if c.Column == column {
+			return c, true
+		}
+	}
+	return DBColumns{}, false
+}
+
+// Number of the distinct values the column is generated with, false when
+// its not known or too many to matter
+func estimateCardinality(tab string, c DBColumns) (float64, bool) {
+	dt := c.Datatype
+	if isItArray, _ := isDataTypeAnArray(dt); isItArray {
+		return 0, false
+	}
+	if isSequentialKey(tab, c.Column) {
+		return float64(intStorageRanges[dt][1]), true
+	}
+	if rule := columnRule(tab, c.Column); rule != nil {
+		if rule.MaxDistinct > 0 {
+			return float64(rule.MaxDistinct), true
+		}
+		switch rule.generatorName() {
+		case "unique":
+			if max, ok := uniqueIntMax[strings.Fields(dt)[0]]; ok {
+				return float64(max - uint64(rule.Start)), true
+			}
+		case "histogram":
+			if len(rule.histogram.Values) > 0 {
+				return float64(len(rule.histogram.Values)), true
+			}
+		case "tristate":
+			return 2, true
+		case "values":
+			return float64(len(distinctStrings(rule.Values))), true
+		case "int_range":
+			if b, err := intRangeBounds(rule, dt); err == nil {
+				return float64(b[1]) - float64(b[0]) + 1, true
+			}
+		case "enum":
+			return float64(len(checkEnumDatatype(dt))), true
+		}
+		return 0, false
+	}
+	switch {
+	case dt == "boolean":
+		return 2, true
+	case StringHasPrefix(dt, intKeywords):
+		if r, ok := intRanges[dt]; ok {
+			return float64(2 * r), true
+		}
+	case strings.HasPrefix(dt, "character"):
+		if l, err := CharLen(dt); err == nil && l <= cardinalityMaxCharLength {
+			return math.Pow(62, float64(l)), true // the letters and digits of RandomString
+		}
+	case !strings.Contains(dt, "(") && !strings.Contains(dt, " "):
+		if labels := checkEnumDatatype(dt); len(labels) > 0 {
+			return float64(len(labels)), true
+		}
+	}
+	return 0, false
+}
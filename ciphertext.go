@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+func init() {
+	registerGenerator("ciphertext",
+		"Opaque values that look like the application encrypted data, \"length: <n>\" random bytes (default 32) "+
+			"as base64 on the text columns or as is on the bytea columns",
+		buildCiphertext)
+}
+
+// Validate the options of the ciphertext rule
+func validateCiphertextRule(c *ColumnRule) error {
+	if c.Length == 0 {
+		c.Length = 32
+	}
+	if c.Length < 1 {
+		return fmt.Errorf("ciphertext length should be at least 1 byte, got %d", c.Length)
+	}
+	return nil
+}
+
+// Ciphertext generator, every value has the same length like the output
+// of a block cipher
+func buildCiphertext(ctx *generatorContext) (interface{}, error) {
+	dt := ctx.Column.Datatype
+	blob := make([]byte, ctx.Rule.Length)
+	for i := range blob {
+		blob[i] = byte(r.Intn(256))
+	}
+	if strings.EqualFold(dt, "bytea") {
+		return `\x` + hex.EncodeToString(blob), nil
+	}
+	if !isTextDatatype(dt) {
+		return "", fmt.Errorf("ciphertext generator only supports the text and bytea columns, got %s", dt)
+	}
+	value := base64.StdEncoding.EncodeToString(blob)
+	if strings.HasPrefix(dt, "character") {
+		// The cut value would no longer decode, so it has to fit
+		if l, err := CharLen(dt); err != nil || l < len(value) {
+			return "", fmt.Errorf("ciphertext of %d bytes is %d characters as base64, more than the column %s can hold",
+				ctx.Rule.Length, len(value), dt)
+		}
+	}
+	return value, nil
+}
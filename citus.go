@@ -0,0 +1,85 @@
+package main
+
+import (
+	"math"
+	"sync"
+)
+
+var (
+	// The citus tables of the database by their name, read once
+	citusTables     map[string]DBCitusTable
+	citusTablesOnce sync.Once
+)
+
+// The citus table, false when its a regular table
+func citusTable(tab string) (DBCitusTable, bool) {
+	if !isPostgresBackend() {
+		return DBCitusTable{}, false
+	}
+	citusTablesOnce.Do(func() {
+		citusTables = make(map[string]DBCitusTable)
+		for _, t := range GetCitusTables() {
+			citusTables[t.Tablename] = t
+		}
+		if len(citusTables) > 0 {
+			Infof("Found %d citus distributed and reference tables, the rows are routed to their shards "+
+				"by the coordinator", len(citusTables))
+		}
+	})
+	t, ok := citusTables[tab]
+	return t, ok
+}
+
+// Check how the citus table is loaded. The distribution column never gets
+// NULLs, since citus doesn't accept them, and it should have enough distinct
+// values to spread the rows across the shards. The reference tables are
+// copied to every node by citus itself
+func checkCitusTable(tab string, columns []DBColumns) {
+	t, ok := citusTable(tab)
+	if !ok {
+		return
+	}
+	if t.Method == "n" {
+		Debugf("Table %s is a citus reference table, its rows are replicated to all the nodes", tab)
+		return
+	}
+	Infof("Table %s is distributed by citus on the column %s across %d shards", tab, t.Column, t.Shards)
+	c, ok := keyColumn(TableCollection{Columns: columns}, t.Column)
+	if !ok {
+		return // left to the database i.e a serial
+	}
+	if n, ok := estimateCardinality(tab, c); ok && n < math.Min(float64(t.Shards), float64(rowsToMock(tab))) {
+		Warnf("The distribution column %s of table %s only has about %.0f distinct values for its %d shards, "+
+			"most of the shards stay empty", t.Column, tab, n, t.Shards)
+	}
+	rule := columnRule(tab, t.Column)
+	if rule != nil && (rule.generatorName() == "unique" || rule.generatorName() == "foreign_key") {
+		return
+	}
+	if !cmdOptions.IgnoreConstraint && !isFileOutput() && isKeyColumn(tab, t.Column) {
+		Warnf("The distribution column %s of table %s is part of its keys, citus doesn't allow updating it so "+
+			"the keys can't be fixed after the load, use a unique or references rule on the column", t.Column, tab)
+	}
+}
+
+// Is the column the distribution column of a citus table
+func isDistributionColumn(tab, column string) bool {
+	t, ok := citusTable(tab)
+	return ok && t.Method != "n" && t.Column == column
+}
+
+// Is the column part of a primary or unique key of the table
+func isKeyColumn(tab, column string) bool {
+	for _, contype := range []string{"p", "u"} {
+		for _, con := range GetPGConstraintDDL(contype) {
+			if con.Tablename != tab {
+				continue
+			}
+			cols, err := ColExtractor(con.Constraintkey, `\(([^\[\]]*)\)`)
+			if err == nil && StringContains(column, constraintColumns(cols)) {
+				return true
+			}
+		}
+	}
+	return false
+}
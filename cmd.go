@@ -2,8 +2,11 @@ package main
 
 import (
 	"fmt"
+	"github.com/icrowley/fake"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"os"
+	"strings"
 )
 
 // Global Parameter
@@ -13,20 +16,81 @@ var (
 
 // Root command line options
 type Command struct {
-	Debug            bool
-	Username         string
-	Password         string
-	Database         string
-	Hostname         string
-	Port             int
-	DB               Database
-	Tab              Tables
-	Rows             int
-	IgnoreConstraint bool
-	DontPrompt       bool
-	SchemaName       string
-	File             string
-	Uri              string
+	Debug                  bool
+	Username               string
+	Password               string
+	Database               string
+	Hostname               string
+	Port                   int
+	DB                     Database
+	Tab                    Tables
+	Rows                   int
+	RowsPerTable           []string
+	IgnoreConstraint       bool
+	DontPrompt             bool
+	SchemaName             string
+	File                   string
+	Uri                    string
+	Interactive            bool
+	Rules                  string
+	OnError                string
+	ContinueOnError        bool
+	OnSkipped              string
+	OutputParquet          string
+	OutputDir              string
+	OutputSQL              string
+	Delimiter              string
+	NullPercent            int
+	NullabilityFromSample  bool
+	AdversarialTextRate    float64
+	ListSupportedTypes     bool
+	MaxConcurrencyPerTable int
+	MaxConnections         int
+	VerifyStats            bool
+	Pooler                 string
+	RowsJitter             float64
+	Seed                   int64
+	MinCoverage            int
+	Locale                 string
+	KeyMode                string
+	TimeZone               string
+	ExportKeys             string
+	ImportKeys             string
+	Explain                bool
+	DryRun                 bool
+	ViolateConstraint      string
+	ViolationRows          int
+	ConnectionPoolWarmup   bool
+	Inheritance            string
+	DocOutput              string
+	RateLimit              int
+	FuzzyDuplicateRate     float64
+	FuzzyDuplicateStrength int
+	ByteaLength            int
+	Report                 string
+	InsertReturning        bool
+	ColumnsFromFile        string
+	Compress               string
+	OverrideSequences      bool
+	ProbeTypes             string
+	TopUpTo                int
+	VerifyForeignKeys      bool
+	Manifest               string
+	Shards                 int
+	ShardKey               string
+	BatchSize              int
+	MaxUniqueRetries       int
+	Parallel               int
+	SingleTransaction      bool
+	Transactional          bool
+	RespectFK              bool
+	Backend                string
+	Truncate               bool
+	Include                []string
+	Exclude                []string
+	SkipColumns            []string
+	OnlyColumns            []string
+	UseDefaults            bool
 }
 
 // Database command line options
@@ -58,11 +122,311 @@ var rootCmd = &cobra.Command{
 		// Before running any command setup the logger log level
 		initLogger(cmdOptions.Debug)
 
+		// Listing the supported data types doesn't need the database
+		if cmdOptions.ListSupportedTypes {
+			PrintSupportedTypes()
+			os.Exit(0)
+		}
+
+		// Same seed produces the same random values
+		if cmdOptions.Seed != 0 {
+			SeedRandom(cmdOptions.Seed)
+		}
+
+		// Language of the names, addresses and text
+		if err := fake.SetLang(cmdOptions.Locale); err != nil {
+			Fatalf("Argument Error: --locale can only be one of %s", strings.Join(fake.GetLangs(), ", "))
+		}
+
+		// Zone of the timestamptz values
+		LoadOutputZone()
+
+		// Load the column rules, any error on the rules file is reported
+		// before we start touching the database
+		if !IsStringEmpty(cmdOptions.Rules) {
+			LoadRules()
+		}
+
+		// The data types that replace the detected ones
+		if !IsStringEmpty(cmdOptions.ColumnsFromFile) {
+			LoadTypeOverrides()
+		}
+
+		// The keys of the tables frozen by a previous run
+		if !IsStringEmpty(cmdOptions.ImportKeys) {
+			LoadKeySnapshot()
+		}
+
 		// if the rows are set to below 1, then error out
 		if cmdOptions.Rows < 1 {
 			Fatalf("Argument Error: minimum row cannot be less than 1")
 		}
 
+		// The patterns picking the tables to mock
+		LoadTableFilters()
+
+		// The columns left to their defaults
+		LoadColumnSelection()
+
+		// The row counts of the tables that don't use --rows
+		if len(cmdOptions.RowsPerTable) > 0 {
+			LoadRowsPerTable()
+		}
+
+		// Jitter is a fraction of the row count
+		if cmdOptions.RowsJitter < 0 || cmdOptions.RowsJitter > 1 {
+			Fatalf("Argument Error: --rows-jitter should be between 0 and 1")
+		}
+
+		// Coverage is the number of times each value appears
+		if cmdOptions.MinCoverage < 0 {
+			Fatalf("Argument Error: --min-coverage cannot be negative")
+		}
+
+		// NULLs percentage should be between 0 and 100
+		if cmdOptions.NullPercent < 0 || cmdOptions.NullPercent > 100 {
+			Fatalf("Argument Error: --null-percent should be between 0 and 100")
+		}
+
+		// Rate of the adversarial text is a fraction of the text values
+		if cmdOptions.AdversarialTextRate < 0 || cmdOptions.AdversarialTextRate > 1 {
+			Fatalf("Argument Error: --adversarial-text-rate should be between 0 and 1")
+		}
+
+		// The target of the top up, 0 always adds --rows
+		if cmdOptions.TopUpTo < 0 {
+			Fatalf("Argument Error: --top-up-to cannot be negative")
+		}
+
+		// Rows per second of all the workers, 0 is no limit
+		if cmdOptions.RateLimit < 0 {
+			Fatalf("Argument Error: --rate-limit cannot be negative")
+		}
+
+		// Near duplicates are a fraction of the text values
+		if cmdOptions.FuzzyDuplicateRate < 0 || cmdOptions.FuzzyDuplicateRate > 1 {
+			Fatalf("Argument Error: --fuzzy-duplicate-rate should be between 0 and 1")
+		}
+		if cmdOptions.FuzzyDuplicateStrength < 1 {
+			Fatalf("Argument Error: --fuzzy-duplicate-strength cannot be less than 1")
+		}
+
+		// Every bytea value has at least a byte
+		if cmdOptions.ByteaLength < 1 {
+			Fatalf("Argument Error: --bytea-length cannot be less than 1")
+		}
+
+		// The duplicate keys are regenerated at least once
+		if cmdOptions.MaxUniqueRetries < 1 {
+			Fatalf("Argument Error: --max-unique-retries cannot be less than 1")
+		}
+
+		// Rows of each COPY to the database
+		if cmdOptions.BatchSize < 1 {
+			Fatalf("Argument Error: --batch-size cannot be less than 1")
+		}
+
+		// Tables loaded at the same time
+		if cmdOptions.Parallel < 1 {
+			Fatalf("Argument Error: --parallel cannot be less than 1")
+		}
+
+		// At least one worker is needed to load a table
+		if cmdOptions.MaxConcurrencyPerTable < 1 {
+			Fatalf("Argument Error: --max-concurrency-per-table cannot be less than 1")
+		}
+
+		// The workers of all the tables draw their connections from this many
+		if cmdOptions.MaxConnections < 0 {
+			Fatalf("Argument Error: --max-connections cannot be negative")
+		}
+
+		// The workers share the random generator, the order they draw from it changes every run
+		if cmdOptions.Seed != 0 && (cmdOptions.Parallel > 1 || cmdOptions.MaxConcurrencyPerTable > 1) {
+			Warnf("--seed only reproduces the same data with --parallel 1 and --max-concurrency-per-table 1")
+		}
+
+		// Only one kind of file output at a time
+		outputs := 0
+		for _, o := range []string{cmdOptions.OutputParquet, cmdOptions.OutputDir, cmdOptions.OutputSQL} {
+			if !IsStringEmpty(o) {
+				outputs++
+			}
+		}
+		if outputs > 1 {
+			Fatalf("Argument Error: --output-dir, --output-parquet and --output-sql cannot be used together, choose one")
+		}
+
+		// The manifest describes the files of the file output
+		if !IsStringEmpty(cmdOptions.Manifest) && !isFileOutput() {
+			Fatalf("Argument Error: --manifest can only be used along with --output-dir, --output-parquet or --output-sql")
+		}
+
+		// The shards are files of the file output
+		if cmdOptions.Shards < 1 {
+			Fatalf("Argument Error: --shards cannot be less than 1")
+		}
+		if cmdOptions.Shards > 1 && !isFileOutput() {
+			Fatalf("Argument Error: --shards can only be used along with --output-dir, --output-parquet or --output-sql")
+		}
+		if !IsStringEmpty(cmdOptions.ShardKey) && cmdOptions.Shards < 2 {
+			Fatalf("Argument Error: --shard-key can only be used along with --shards of 2 or more")
+		}
+
+		// Compression of the csv files
+		switch cmdOptions.Compress {
+		case "none":
+		case "gzip":
+			if IsStringEmpty(cmdOptions.OutputDir) {
+				Fatalf("Argument Error: --compress can only be used along with --output-dir")
+			}
+		default:
+			Fatalf("Argument Error: --compress can only be \"none\" or \"gzip\"")
+		}
+
+		// The existing rows are only known on the database
+		if cmdOptions.TopUpTo > 0 && isFileOutput() {
+			Fatalf("Argument Error: --top-up-to cannot be used when writing the data to files")
+		}
+
+		// The foreign keys are only removed on the database
+		if cmdOptions.VerifyForeignKeys && (isFileOutput() || cmdOptions.IgnoreConstraint) {
+			Fatalf("Argument Error: --verify-foreign-keys cannot be used along with --ignore or when writing the data to files")
+		}
+
+		// The statistics are only available on the database
+		if cmdOptions.VerifyStats && isFileOutput() {
+			Fatalf("Argument Error: --verify-stats cannot be used when writing the data to files")
+		}
+
+		// Explaining the plan shouldn't create anything either
+		if cmdOptions.Explain && (cmdOptions.DB.FakeDB || cmdOptions.Tab.FakeNewTables) {
+			Fatalf("Argument Error: --explain cannot be used along with creating the fake tables")
+		}
+
+		// The dry run only checks the tables, it doesn't create or load any
+		if cmdOptions.DryRun && (cmdOptions.DB.FakeDB || cmdOptions.Tab.FakeNewTables) {
+			Fatalf("Argument Error: --dry-run cannot be used along with creating the fake tables")
+		}
+		if cmdOptions.DryRun && cmdOptions.Explain {
+			Fatalf("Argument Error: --dry-run and --explain cannot be used together, choose one")
+		}
+
+		// There are no rows to probe on the fake tables
+		if !IsStringEmpty(cmdOptions.ProbeTypes) && (cmdOptions.DB.FakeDB || cmdOptions.Tab.FakeNewTables) {
+			Fatalf("Argument Error: --probe-types cannot be used along with creating the fake tables")
+		}
+
+		// The violating rows are checked against the keys on the database
+		if !IsStringEmpty(cmdOptions.ViolateConstraint) && isFileOutput() {
+			Fatalf("Argument Error: --violate-constraint cannot be used when writing the data to files")
+		}
+		if cmdOptions.ViolationRows < 1 {
+			Fatalf("Argument Error: --violation-rows cannot be less than 1")
+		}
+
+		// There are no connections to warm up when writing to files
+		if cmdOptions.ConnectionPoolWarmup && isFileOutput() {
+			Fatalf("Argument Error: --connection-pool-warmup cannot be used when writing the data to files")
+		}
+
+		// The delimiter of the COPY and the csv files, the values holding it are quoted
+		if err := validateDelimiter(cmdOptions.Delimiter); err != nil {
+			Fatalf("Argument Error: --delimiter %v", err)
+		}
+		delimiter = cmdOptions.Delimiter
+
+		// The keys are returned by the database
+		if cmdOptions.InsertReturning && isFileOutput() {
+			Fatalf("Argument Error: --insert-returning cannot be used when writing the data to files")
+		}
+
+		// The keys of the referenced tables are read from the database
+		if cmdOptions.RespectFK && isFileOutput() {
+			Fatalf("Argument Error: --respect-fk cannot be used when writing the data to files")
+		}
+
+		// The keys are read back from the database
+		if !IsStringEmpty(cmdOptions.ExportKeys) && isFileOutput() {
+			Fatalf("Argument Error: --export-keys cannot be used when writing the data to files")
+		}
+
+		// Pooling mode of the pooler in front of the database
+		if cmdOptions.Pooler != "session" && cmdOptions.Pooler != "transaction" {
+			Fatalf("Argument Error: --pooler can only be \"session\" or \"transaction\"")
+		}
+
+		// How the single column integer keys are generated
+		if cmdOptions.KeyMode != "sequential" && cmdOptions.KeyMode != "random" {
+			Fatalf("Argument Error: --key-mode can only be \"sequential\" or \"random\"")
+		}
+
+		// Level of the inheritance hierarchies to load
+		if cmdOptions.Inheritance != "all" && cmdOptions.Inheritance != "parent" && cmdOptions.Inheritance != "children" {
+			Fatalf("Argument Error: --inheritance can only be \"all\", \"parent\" or \"children\"")
+		}
+
+		// What to do when we hit an error on a table
+		switch cmdOptions.OnError {
+		case "abort":
+			cmdOptions.ContinueOnError = false
+		case "continue":
+			cmdOptions.ContinueOnError = true
+		default:
+			Fatalf("Argument Error: --on-error can only be \"abort\" or \"continue\"")
+		}
+
+		// The skipped and failed tables only change the exit code when asked to
+		if cmdOptions.OnSkipped != "continue" && cmdOptions.OnSkipped != "fail-at-end" {
+			Fatalf("Argument Error: --on-skipped can only be \"continue\" or \"fail-at-end\"")
+		}
+
+		// The report is of the commands that load the tables they find
+		if cmd.Name() == "custom" && !IsStringEmpty(cmdOptions.Report) {
+			Fatalf("Argument Error: --report only works on the database, schema and tables commands")
+		}
+
+		// The database the data is loaded to, the postgres only flags are rejected on the others
+		checkBackend(cmd.Flags().Changed, cmd.Name())
+
+		// A failed statement aborts the whole transaction, so its loaded by a single worker and
+		// there is no continuing after an error
+		if cmdOptions.SingleTransaction {
+			switch {
+			case isFileOutput():
+				Fatalf("Argument Error: --single-transaction cannot be used when writing the data to files")
+			case cmdOptions.ContinueOnError:
+				Fatalf("Argument Error: --single-transaction cannot be used along with --on-error continue")
+			case cmdOptions.Parallel > 1 || cmdOptions.MaxConcurrencyPerTable > 1 || cmdOptions.ConnectionPoolWarmup:
+				Fatalf("Argument Error: --single-transaction loads the tables on a single connection, it cannot " +
+					"be used along with --parallel, --max-concurrency-per-table or --connection-pool-warmup")
+			}
+		}
+
+		// Each table is loaded in a transaction on a single connection
+		if cmdOptions.Transactional {
+			switch {
+			case isFileOutput():
+				Fatalf("Argument Error: --transactional cannot be used when writing the data to files")
+			case cmdOptions.SingleTransaction:
+				Fatalf("Argument Error: --transactional cannot be used along with --single-transaction, " +
+					"which already loads all the tables in one transaction")
+			case cmdOptions.MaxConcurrencyPerTable > 1:
+				Fatalf("Argument Error: --transactional loads each table on a single connection, it cannot " +
+					"be used along with --max-concurrency-per-table")
+			}
+		}
+
+		// The tables are emptied before loading, so there are no rows to top up
+		if cmdOptions.Truncate {
+			switch {
+			case isFileOutput():
+				Fatalf("Argument Error: --truncate cannot be used when writing the data to files")
+			case cmdOptions.TopUpTo > 0:
+				Fatalf("Argument Error: --truncate cannot be used along with --top-up-to, which adds to the existing rows")
+			}
+		}
+
 		// There can only be one option either uri or database connection values
 		isDatabaseArgumentsSet := !IsStringEmpty(cmdOptions.Database) ||
 			!IsStringEmpty(cmdOptions.Hostname) || !IsStringEmpty(cmdOptions.Username) ||
@@ -75,11 +439,15 @@ var rootCmd = &cobra.Command{
 
 		// Ensure we can make a successful connection to the database
 		// by printing the version of the database we are going to mock
-		dbVersion()
+		activeBackend.connect()
 
 		// The database that we will be working on
 		Infof("The database that will be used by %s program is: %s", programName, cmdOptions.Database)
 	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		// The tables loaded, skipped and failed along with the exit code
+		runSummary()
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		Fatalf("No sub commands used, please run \"%s --help\" for all the options", programName)
 	},
@@ -125,7 +493,7 @@ var tablesCmd = &cobra.Command{
 		}
 		// either create fake tables or insert mock table rows are allowed, not together
 		if cmdOptions.Tab.FakeNewTables && !IsStringEmpty(cmdOptions.Tab.FakeTablesRows) {
-			Fatalf("Cannot perform create table & mock tables together, choose one", programName)
+			Fatalf("Cannot perform create table & mock tables together, choose one")
 		}
 		// if there is request for new tables and no of tables parameter is below 1 then error out
 		if cmdOptions.Tab.FakeNewTables && cmdOptions.Tab.TotalTables < 1 {
@@ -194,7 +562,7 @@ var customCmd = &cobra.Command{
 		}
 		// If both is set
 		if !IsStringEmpty(cmdOptions.Tab.FakeTablesRows) && !IsStringEmpty(cmdOptions.File) {
-			Fatalf("Cannot run the table and loading of data via file together, choose one", programName)
+			Fatalf("Cannot run the table and loading of data via file together, choose one")
 		}
 	},
 	Run: func(cmd *cobra.Command, args []string) {
@@ -208,6 +576,22 @@ var customCmd = &cobra.Command{
 	},
 }
 
+// The preview diff sub commands
+var previewDiffCmd = &cobra.Command{
+	Use:   "preview-diff <old-dir> <new-dir>",
+	Short: "Compare the csv files of two runs",
+	Long: "Summarize the tables, rows and columns that changed between the csv files of two runs " +
+		"written with --output-dir, i.e to review the effect of a rules or seed change",
+	Args: cobra.ExactArgs(2),
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// Its only the files, so no database connection is needed
+		initLogger(cmdOptions.Debug)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		PreviewDiff(args[0], args[1])
+	},
+}
+
 // Initialize the cobra command line
 func init() {
 	// Load the environment variable using viper
@@ -234,18 +618,184 @@ func init() {
 		false, "Ignore checking and fixing constraints")
 	rootCmd.PersistentFlags().BoolVarP(&cmdOptions.DontPrompt, "dont-prompt", "q",
 		false, "Run without asking for confirmation")
+	rootCmd.PersistentFlags().StringVar(&cmdOptions.Rules, "rules",
+		"", "YAML file with the rules that control the data generated for specific columns")
+	rootCmd.PersistentFlags().StringVar(&cmdOptions.Delimiter, "delimiter",
+		"$", "Delimiter of the COPY and of the csv files of --output-dir, the values holding it are quoted")
+	rootCmd.PersistentFlags().StringVar(&cmdOptions.OnError, "on-error",
+		"abort", "What to do when a table hits an error, either \"abort\" the run or \"continue\" with the next table")
+	rootCmd.PersistentFlags().StringVar(&cmdOptions.OnSkipped, "on-skipped",
+		"continue", "What the skipped tables do to the exit code, \"continue\" ignores them or \"fail-at-end\" "+
+			"loads the rest of the tables and exits with 3 (skipped) or 5 (skipped and failed), the failed tables "+
+			"always exit with 4")
+	rootCmd.PersistentFlags().BoolVar(&cmdOptions.RespectFK, "respect-fk",
+		false, "Pick the values of the foreign key columns from the keys of the tables they refer to, the referenced "+
+			"tables are loaded first and the tables whose referenced table has no rows are skipped")
+	rootCmd.PersistentFlags().BoolVar(&cmdOptions.SingleTransaction, "single-transaction",
+		false, "Load all the tables, from the removal of the constraints to their restore, in a single transaction "+
+			"that is rolled back on any failure, the tables are loaded one at a time")
+	rootCmd.PersistentFlags().BoolVar(&cmdOptions.Transactional, "transactional",
+		false, "Load each table in a transaction of its own, a table that fails is rolled back to its rows before "+
+			"the load instead of being left half loaded (the server keeps the whole table in the transaction)")
+	rootCmd.PersistentFlags().StringVar(&cmdOptions.ColumnsFromFile, "columns-from-file",
+		"", "File of \"<schema>.<table>.<column> <data type>\" lines, the columns are generated as these data types "+
+			"instead of the detected ones")
+	rootCmd.PersistentFlags().StringVar(&cmdOptions.Compress, "compress",
+		"none", "Compression of the csv files of --output-dir, \"none\" or \"gzip\" for <schema>.<table>.csv.gz files")
+	rootCmd.PersistentFlags().BoolVar(&cmdOptions.ConnectionPoolWarmup, "connection-pool-warmup",
+		false, "Open and validate a connection for each of the --max-concurrency-per-table workers of the "+
+			"--parallel tables before loading, so the connection errors fail fast")
+	rootCmd.PersistentFlags().StringVar(&cmdOptions.DocOutput, "doc-output",
+		"", "After loading, write the data dictionary of the tables (columns, generators, rows, NULLs and "+
+			"sample values) to this markdown file, or html if it ends with .html")
+	rootCmd.PersistentFlags().BoolVar(&cmdOptions.DryRun, "dry-run",
+		false, "Check that the data types of the columns are supported and list the tables that would be skipped "+
+			"or fail, without loading any data, exits with 3 when any table would be skipped, 4 when any would "+
+			"fail or 5 for both")
+	rootCmd.PersistentFlags().BoolVar(&cmdOptions.Explain, "explain",
+		false, "Print the order of the tables, their dependencies, rows and how each column is generated "+
+			"and the constraints likely to fail being restored, without loading any data")
+	rootCmd.PersistentFlags().StringVar(&cmdOptions.ProbeTypes, "probe-types",
+		"", "Sample the rows of the tables, classify the values of their text columns (emails, UUIDs, paths ...) "+
+			"and write the suggested generators to this starter rules file, without loading any data")
+	rootCmd.PersistentFlags().StringVar(&cmdOptions.ExportKeys, "export-keys",
+		"", "After loading, save the primary keys of the mocked tables to this snapshot file")
+	rootCmd.PersistentFlags().StringVar(&cmdOptions.ImportKeys, "import-keys",
+		"", "Use the primary keys of the snapshot file saved by --export-keys, the references to them stay valid")
+	rootCmd.PersistentFlags().IntVar(&cmdOptions.Shards, "shards",
+		1, "Split the file of each table of --output-dir, --output-parquet or --output-sql to this many files "+
+			"<schema>.<table>.part-<n>, the rows are split round robin or by --shard-key")
+	rootCmd.PersistentFlags().StringVar(&cmdOptions.ShardKey, "shard-key",
+		"", "Split the rows of the tables with this column to the --shards by the hash of its value, "+
+			"so the rows with the same value are on the same shard")
+	rootCmd.PersistentFlags().StringVar(&cmdOptions.Manifest, "manifest",
+		"", "After writing the files of --output-dir, --output-parquet or --output-sql, describe each file (table, columns, "+
+			"format, delimiter, quote, NULL and rows) on this json manifest for the bulk loaders")
+	rootCmd.PersistentFlags().StringVar(&cmdOptions.Report, "report",
+		"", "After loading, write the tables loaded with their rows, the tables skipped and failed with the reason "+
+			"(the failed ones with the rows they kept), the tables of only a serial column, the duration, whether the constraints are restored and the success "+
+			"of the run to this json file")
+	rootCmd.PersistentFlags().StringVar(&cmdOptions.OutputDir, "output-dir",
+		"", "Write the mock data of each table as a csv file on this directory instead of the database")
+	rootCmd.PersistentFlags().StringVar(&cmdOptions.OutputParquet, "output-parquet",
+		"", "Write the mock data of each table as a parquet file on this directory instead of the database")
+	rootCmd.PersistentFlags().StringVar(&cmdOptions.OutputSQL, "output-sql",
+		"", "Write the mock data of each table as a sql file of INSERT statements of --batch-size rows on this "+
+			"directory instead of the database")
+	rootCmd.PersistentFlags().IntVar(&cmdOptions.NullPercent, "null-percent",
+		0, "Percentage of NULLs on the nullable columns")
+	rootCmd.PersistentFlags().BoolVar(&cmdOptions.NullabilityFromSample, "nullability-from-sample",
+		false, "Derive the percentage of NULLs of each nullable column from its existing data, "+
+			"the tables with no rows use --null-percent")
+	rootCmd.PersistentFlags().Float64Var(&cmdOptions.AdversarialTextRate, "adversarial-text-rate",
+		0, "Fraction (0 to 1) of the text values replaced by tricky but valid content i.e quotes, "+
+			"delimiters, newlines, emoji and leading / trailing spaces")
+	rootCmd.PersistentFlags().Float64Var(&cmdOptions.FuzzyDuplicateRate, "fuzzy-duplicate-rate",
+		0, "Fraction (0 to 1) of the text values replaced by a near duplicate of an earlier value of the column, "+
+			"i.e \"Jon Smith\" or \"John Smyth\" for \"John Smith\"")
+	rootCmd.PersistentFlags().IntVar(&cmdOptions.FuzzyDuplicateStrength, "fuzzy-duplicate-strength",
+		1, "Number of the letters dropped, doubled, swapped or replaced on each near duplicate")
+	rootCmd.PersistentFlags().IntVar(&cmdOptions.ByteaLength, "bytea-length",
+		1024, "Most random bytes of each bytea value, the values are 1 to this many bytes long")
+	rootCmd.PersistentFlags().BoolVar(&cmdOptions.ListSupportedTypes, "list-supported-types",
+		false, "Print the supported data types and the named generators of the rules file, then exit")
+	rootCmd.PersistentFlags().IntVar(&cmdOptions.BatchSize, "batch-size",
+		10000, "Number of the rows buffered and loaded to the database with a single COPY")
+	rootCmd.PersistentFlags().IntVar(&cmdOptions.Parallel, "parallel",
+		1, "Number of the tables loaded at the same time, each with its own database connections and the "+
+			"tables referred to by the references rules loaded first")
+	rootCmd.PersistentFlags().IntVar(&cmdOptions.MaxUniqueRetries, "max-unique-retries",
+		10, "Rounds of regenerating the duplicate values of the primary and unique keys, the duplicates left "+
+			"are deleted")
+	rootCmd.PersistentFlags().IntVar(&cmdOptions.MaxConcurrencyPerTable, "max-concurrency-per-table",
+		1, "Number of concurrent workers (each with its own database connection) loading a single table")
+	rootCmd.PersistentFlags().IntVar(&cmdOptions.MaxConnections, "max-connections",
+		0, "Most database connections the workers of all the tables have open at the same time, the workers "+
+			"wait for a free one (0 is one for each of the --max-concurrency-per-table workers of the --parallel tables)")
+	rootCmd.PersistentFlags().BoolVar(&cmdOptions.VerifyStats, "verify-stats",
+		false, "After loading, ANALYZE the tables and compare the column statistics (NULLs, distinct values, "+
+			"ranges) with what the generators were configured to produce")
+	rootCmd.PersistentFlags().BoolVar(&cmdOptions.VerifyForeignKeys, "verify-foreign-keys",
+		false, "After loading, count the rows of each foreign key with no referenced row before the constraints "+
+			"are fixed and restored")
+	rootCmd.PersistentFlags().IntVar(&cmdOptions.MinCoverage, "min-coverage",
+		0, "Each value of the low cardinality columns (enums, booleans, categorical histograms, list partitions) "+
+			"appears at least this many times before the rest of the rows are filled randomly")
+	rootCmd.PersistentFlags().StringVar(&cmdOptions.Locale, "locale",
+		"en", "Locale of the generated names, addresses and text, the person columns of a row are consistent with it")
+	rootCmd.PersistentFlags().StringVar(&cmdOptions.TimeZone, "time-zone",
+		"", "Time zone of the timestamptz values i.e Europe/Berlin, they are written with its offset instead of "+
+			"being read on the zone of the database session")
+	rootCmd.PersistentFlags().Int64Var(&cmdOptions.Seed, "seed",
+		0, "Seed of the random generator, the same seed produces the same values (0 seeds from the current time)")
+	rootCmd.PersistentFlags().StringArrayVar(&cmdOptions.RowsPerTable, "rows-per-table",
+		nil, "Rows of a table instead of --rows as \"<schema>.<table>=<rows>\", or a file of these pairs one per "+
+			"line, can be repeated")
+	rootCmd.PersistentFlags().Float64Var(&cmdOptions.RowsJitter, "rows-jitter",
+		0, "Vary the row count of each table randomly by this fraction around the target, i.e 0.1 for ±10%")
+	rootCmd.PersistentFlags().IntVar(&cmdOptions.TopUpTo, "top-up-to",
+		0, "Keep the existing rows and only add the rows missing to reach this count per table, the per table "+
+			"row counts are the targets too and the tables already at the target are skipped")
+	rootCmd.PersistentFlags().IntVar(&cmdOptions.RateLimit, "rate-limit",
+		0, "Throttle the loading to at most this many rows per second, shared by the workers of all the tables (0 is no limit)")
+	rootCmd.PersistentFlags().BoolVar(&cmdOptions.OverrideSequences, "override-sequences",
+		false, "Generate the values of the columns defaulting to a sequence (i.e serial) instead of leaving them "+
+			"to the database, the sequences are moved past the generated values after loading")
+	rootCmd.PersistentFlags().StringVar(&cmdOptions.Pooler, "pooler",
+		"session", "Pooling mode of the connection pooler (i.e PgBouncer) in front of the database, "+
+			"\"session\" or \"transaction\" where the session level settings are applied per transaction")
+	rootCmd.PersistentFlags().StringVar(&cmdOptions.ViolateConstraint, "violate-constraint",
+		"", "Negative testing, after loading generate rows that violate this <schema>.<table>.<constraint> "+
+			"(primary, unique or foreign key) into the side table <table>_mock_violation")
+	rootCmd.PersistentFlags().IntVar(&cmdOptions.ViolationRows, "violation-rows",
+		10, "Number of the rows generated by --violate-constraint")
+	rootCmd.PersistentFlags().StringVar(&cmdOptions.KeyMode, "key-mode",
+		"sequential", "How the integer columns that are alone a primary or unique key (and not a sequence) are "+
+			"generated, \"sequential\" counts up from the highest existing value or \"random\" like the rest of the columns")
+	rootCmd.PersistentFlags().StringVar(&cmdOptions.Inheritance, "inheritance",
+		"all", "Tables of the inheritance (INHERITS) hierarchies to load, \"all\", only the \"parent\" tables "+
+			"or only the \"children\" whose rows also show on the parent")
+	rootCmd.PersistentFlags().BoolVar(&cmdOptions.InsertReturning, "insert-returning",
+		false, "Load the tables whose database assigned keys (i.e serial) are referenced by the rules with "+
+			"INSERT ... RETURNING, so the references pick the keys of this run (slower than the COPY)")
+	rootCmd.PersistentFlags().BoolVar(&cmdOptions.Interactive, "interactive",
+		false, "Pick the tables and the rows of each table interactively before loading")
+	rootCmd.PersistentFlags().StringArrayVar(&cmdOptions.Include, "include",
+		nil, "Only mock the tables whose <schema>.<table> matches this regular expression, can be repeated")
+	rootCmd.PersistentFlags().StringArrayVar(&cmdOptions.Exclude, "exclude",
+		nil, "Skip the tables whose <schema>.<table> matches this regular expression i.e \"\\.audit_\", can be "+
+			"repeated, it wins over --include")
+	rootCmd.PersistentFlags().StringArrayVar(&cmdOptions.SkipColumns, "skip-columns",
+		nil, "Leave this <schema>.<table>.<column> to its default instead of generating it i.e a created_at "+
+			"DEFAULT now(), can be repeated")
+	rootCmd.PersistentFlags().StringArrayVar(&cmdOptions.OnlyColumns, "only-columns",
+		nil, "Only generate this <schema>.<table>.<column> of its table and leave the rest of the columns of the "+
+			"table to their defaults, can be repeated")
+	rootCmd.PersistentFlags().BoolVar(&cmdOptions.UseDefaults, "use-defaults",
+		false, "Leave the columns with a DEFAULT (i.e status text DEFAULT 'new') to the database instead of "+
+			"generating them, unless they have a rule")
+	rootCmd.PersistentFlags().BoolVar(&cmdOptions.Truncate, "truncate",
+		false, "Remove the existing rows of each table before loading it, so the tables have the rows of this run "+
+			"(TRUNCATE, or DELETE when the table is referenced by the foreign keys of the tables not mocked)")
+	rootCmd.PersistentFlags().StringVar(&cmdOptions.Backend, "backend",
+		"postgres", "Database the data is loaded to, \"postgres\" (and greenplum), \"mysql\" or \"sqlite\", the "+
+			"connection flags and the --uri (i.e user:pass@tcp(host:3306)/db on mysql) are of that database and "+
+			"the --file is the database file of sqlite")
 
 	// Attach the sub commands
 	rootCmd.AddCommand(databaseCmd)
 	rootCmd.AddCommand(tablesCmd)
 	rootCmd.AddCommand(schemaCmd)
 	rootCmd.AddCommand(customCmd)
+	rootCmd.AddCommand(previewDiffCmd)
 
 	// Database command flags
 	databaseCmd.Flags().BoolVarP(&cmdOptions.DB.FakeDB, "create-db", "c", false,
 		"Create fake tables mimicking a real life database")
 	databaseCmd.Flags().BoolVarP(&cmdOptions.DB.FakeDBTableRows, "full-database", "f", false,
 		"Fake all the tables in the database with fake data")
+	databaseCmd.Flags().StringVar(&cmdOptions.File, "file", "",
+		"Database file of --backend sqlite")
 
 	// Table command flags
 	tablesCmd.Flags().BoolVarP(&cmdOptions.Tab.FakeNewTables, "create-tables", "c", false,
@@ -264,11 +814,15 @@ func init() {
 		"public", "Under which schema do these fake tables need to be created or mocked?")
 	tablesCmd.Flags().StringVarP(&cmdOptions.Tab.FakeTablesRows, "mock-tables", "t", "",
 		"Fake selected list of tables with fake data, to add in multiple tables use \",\" b/w table names ")
+	tablesCmd.Flags().StringVar(&cmdOptions.File, "file", "",
+		"Database file of --backend sqlite")
 
 	// Schema command flags
 	schemaCmd.Flags().StringVarP(&cmdOptions.SchemaName, "schema-name", "n", "",
 		"Provide the schema name whose tables need to be mocked")
 	schemaCmd.MarkFlagRequired("schema-name")
+	schemaCmd.Flags().StringVar(&cmdOptions.File, "file", "",
+		"Database file of --backend sqlite")
 
 	// Custom command flags
 	customCmd.Flags().StringVarP(&cmdOptions.File, "file", "f", "",
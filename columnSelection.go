@@ -0,0 +1,82 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+var (
+	// The columns of --skip-columns and --only-columns by rule key
+	skippedColumns = make(map[string]bool)
+	onlyColumns    = make(map[string]bool)
+
+	// The tables of --only-columns, the rest of their columns are skipped
+	onlyColumnsTables = make(map[string]bool)
+)
+
+// Read the "<schema>.<table>.<column>" values of --skip-columns and
+// --only-columns, the tables without a schema are on public
+func LoadColumnSelection() {
+	for _, f := range []struct {
+		flag    string
+		values  []string
+		columns map[string]bool
+	}{{"skip-columns", cmdOptions.SkipColumns, skippedColumns}, {"only-columns", cmdOptions.OnlyColumns, onlyColumns}} {
+		for _, value := range f.values {
+			i := strings.LastIndex(value, ".")
+			if i < 1 || i == len(value)-1 {
+				Fatalf("Argument Error: invalid --%s \"%s\", expected <schema>.<table>.<column>", f.flag, value)
+			}
+			tab := qualifiedTableName(value[:i])
+			f.columns[ruleKey(tab, strings.Trim(value[i+1:], `"`))] = true
+			if f.flag == "only-columns" {
+				onlyColumnsTables[tab] = true
+			}
+		}
+	}
+}
+
+// Leave out the columns of --skip-columns and the columns of the tables of
+// --only-columns that are not named, they are not on the COPY so the
+// database fills in their defaults. A NOT NULL column without a default
+// would fail the whole table, so its reported before anything is loaded
+func selectColumns(tab string, columns []DBColumns) []DBColumns {
+	if len(skippedColumns) == 0 && len(onlyColumns) == 0 && !cmdOptions.UseDefaults {
+		return columns
+	}
+	var selected []DBColumns
+	found := make(map[string]bool)
+	for _, c := range columns {
+		key := ruleKey(tab, c.Column)
+		found[key] = true
+		if !skippedColumns[key] && (!onlyColumnsTables[tab] || onlyColumns[key]) && !leftToDefault(tab, c) {
+			selected = append(selected, c)
+			continue
+		}
+		Debugf("Column %s of table %s is left to its default", c.Column, tab)
+		if !c.IsNullable && IsStringEmpty(c.Sequence) {
+			Warnf("Column %s of table %s is NOT NULL and has no default, leaving it out of the load with "+
+				"--skip-columns or --only-columns makes the database reject the rows of the table", c.Column, tab)
+		}
+	}
+	var missing []string
+	for _, set := range []map[string]bool{skippedColumns, onlyColumns} {
+		for key := range set {
+			if strings.HasPrefix(key, tab+".") && !found[key] {
+				missing = append(missing, key)
+			}
+		}
+	}
+	sort.Strings(missing)
+	for _, key := range missing {
+		Warnf("Column %s of --skip-columns or --only-columns is not a column of the table, its ignored", key)
+	}
+	return selected
+}
+
+// Is the column left to its default with --use-defaults, the serial columns
+// follow --override-sequences and the columns with a rule are generated
+func leftToDefault(tab string, c DBColumns) bool {
+	return cmdOptions.UseDefaults && !IsStringEmpty(c.Sequence) && !isItSerialDatatype(c) &&
+		columnRule(tab, c.Column) == nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Capture the log of the test
+func testLog(t *testing.T) *bytes.Buffer {
+	var log bytes.Buffer
+	out := logger.Out
+	logger.SetOutput(&log)
+	t.Cleanup(func() { logger.SetOutput(out) })
+	return &log
+}
+
+func TestSelectColumnsUseDefaults(t *testing.T) {
+	log := testLog(t)
+	defer func(useDefaults bool) { cmdOptions.UseDefaults = useDefaults }(cmdOptions.UseDefaults)
+	cmdOptions.UseDefaults = true
+	tab := GenerateTableName("orders", "public")
+	skippedColumns[ruleKey(tab, "code")] = true
+	defer delete(skippedColumns, ruleKey(tab, "code"))
+
+	columns := []DBColumns{
+		{Column: "id", Datatype: "integer", Sequence: "nextval('orders_id_seq'::regclass)"},
+		{Column: "status", Datatype: "text", Sequence: "'new'::text", IsNullable: true},
+		{Column: "created", Datatype: "timestamp without time zone", Sequence: "now()"},
+		{Column: "name", Datatype: "text"},
+		{Column: "code", Datatype: "text"},
+		{Column: "note", Datatype: "text", IsNullable: true},
+	}
+	got := selectColumns(tab, columns)
+	want := []DBColumns{columns[0], columns[3], columns[5]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("selectColumns = %+v, want %+v", got, want)
+	}
+	if warnings := strings.Count(log.String(), "level=warning"); warnings != 1 ||
+		!strings.Contains(log.String(), "Column code of table") ||
+		!strings.Contains(log.String(), "is NOT NULL and has no default") {
+		t.Errorf("selectColumns warned %d times, want once of the NOT NULL column code: %s", warnings, log)
+	}
+}
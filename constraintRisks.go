@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// A constraint that is likely to fail being restored after the load
+type constraintRisk struct {
+	Table, Constraint string
+	Fails             bool // else the fix up is only slow or might not converge
+	Reason            string
+}
+
+// Predict which constraints of the tables are likely to fail being restored,
+// from their generated values and the rules of their columns
+func constraintRisks(tables []TableCollection) []constraintRisk {
+	var risks []constraintRisk
+	mocked := make(map[string]TableCollection)
+	for _, t := range tables {
+		mocked[GenerateTableName(t.Table, t.Schema)] = t
+	}
+
+	// The keys whose values repeat, the duplicates are regenerated
+	for _, contype := range []string{"p", "u"} {
+		for _, con := range GetPGConstraintDDL(contype) {
+			t, ok := mocked[con.Tablename]
+			if !ok {
+				continue
+			}
+			cols, err := ColExtractor(con.Constraintkey, `\(([^\[\]]*)\)`)
+			if err != nil {
+				continue
+			}
+			if r, ok := uniqueKeyRisk(t, con.Tablename, constraintColumns(cols)); ok {
+				r.Constraint = con.Constraintname
+				risks = append(risks, r)
+			}
+		}
+	}
+
+	// The foreign keys, their values are replaced by keys of the referenced table
+	for _, con := range GetPGConstraintDDL("f") {
+		t, ok := mocked[con.Tablename]
+		if !ok {
+			continue
+		}
+		fk := getForeignKeyColumns(constraint{con.Tablename, con.Constraintkey})
+		if _, ok := keyColumn(t, fk.Column); !ok {
+			continue // left to the database i.e a serial
+		}
+		reftab := qualifiedTableName(fk.Reftable)
+		risk := constraintRisk{Table: con.Tablename, Constraint: con.Constraintname}
+		rule := columnRule(con.Tablename, fk.Column)
+		_, refMocked := mocked[reftab]
+		switch {
+		case refMocked && rowsToMock(reftab) == 0, !refMocked && TotalRows(reftab) == 0:
+			risk.Fails = true
+			risk.Reason = fmt.Sprintf("%s has no rows for %s to refer to", reftab, fk.Column)
+		case rule == nil || IsStringEmpty(rule.References):
+			risk.Reason = fmt.Sprintf("%s has no references rule, each of its %d values is replaced by a key of %s "+
+				"after the load", fk.Column, rowsToMock(con.Tablename), reftab)
+		default:
+			continue
+		}
+		risks = append(risks, risk)
+	}
+
+	// The check constraints aren't fixed, the values have to satisfy them
+	for _, con := range GetPGConstraintDDL("c") {
+		t, ok := mocked[con.Tablename]
+		if !ok {
+			continue
+		}
+		var columns []string
+		ruled := false
+		for _, c := range t.Columns {
+			if !strings.Contains(con.Constraintkey, c.Column) {
+				continue
+			}
+			columns = append(columns, c.Column)
+			ruled = ruled || columnRule(con.Tablename, c.Column) != nil
+		}
+		if len(columns) == 0 || ruled {
+			continue
+		}
+		risks = append(risks, constraintRisk{Table: con.Tablename, Constraint: con.Constraintname,
+			Reason: fmt.Sprintf("%s is not fixed and the random values of %s have no rule to satisfy it",
+				con.Constraintkey, strings.Join(columns, ", "))})
+	}
+	return risks
+}
+
+// The risk of the primary or unique key, the keys with fewer distinct values
+// than rows fail and the ones without a unique rule get duplicates when
+// the rows are more than the square root of the distinct values
+func uniqueKeyRisk(t TableCollection, tab string, columns []string) (constraintRisk, bool) {
+	risk := constraintRisk{Table: tab}
+	cardinality, unique := 1.0, false
+	for _, column := range columns {
+		c, ok := keyColumn(t, column)
+		if !ok {
+			return risk, false // left to the database i.e a serial
+		}
+		if rule := columnRule(tab, column); rule != nil && rule.generatorName() == "unique" || isSequentialKey(tab, column) {
+			unique = true
+		}
+		n, ok := estimateCardinality(tab, c)
+		if !ok {
+			n = math.Inf(1)
+		}
+		cardinality *= n
+	}
+	rows := float64(rowsToMock(tab))
+	switch {
+	case rows > cardinality:
+		risk.Fails = true
+		risk.Reason = fmt.Sprintf("(%s) can only have about %.0f distinct values for the %.0f rows",
+			strings.Join(columns, ", "), cardinality, rows)
+	case !unique && rows > math.Sqrt(cardinality):
+		risk.Reason = fmt.Sprintf("(%s) has no unique rule and about %.0f distinct values, the %.0f rows get "+
+			"duplicates that are regenerated up to %d times", strings.Join(columns, ", "), cardinality, rows,
+			cmdOptions.MaxUniqueRetries)
+	default:
+		return risk, false
+	}
+	return risk, true
+}
+
+// Print the constraints likely to fail being restored
+func printConstraintRisks(tables []TableCollection) {
+	fmt.Println("Constraint risks:")
+	risks := constraintRisks(tables)
+	if len(risks) == 0 {
+		fmt.Println("  none")
+	}
+	for _, r := range risks {
+		level := "might fail"
+		if r.Fails {
+			level = "FAILS"
+		}
+		fmt.Printf("  %s %s %s: %s\n", r.Table, r.Constraint, level, r.Reason)
+	}
+}
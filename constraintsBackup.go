@@ -87,7 +87,6 @@ func constraintFinder(contype string) (string, error) {
 	default:
 		return "", fmt.Errorf("cannot understand the type of constraints")
 	}
-	return "", nil
 }
 
 // Remove the constraints before loading to ease the pain of any
@@ -110,6 +109,18 @@ func RemoveConstraints(table string) {
 
 		// Execute the statement
 		_, err := ExecuteDB(statement)
+		if err != nil && isPermissionDenied(err) {
+			// The user cannot alter this table, either load the table with the
+			// constraints in place or stop here and ask for the right privileges
+			if cmdOptions.ContinueOnError {
+				Warnf("Insufficient privileges to remove the constraints of table %s, loading the data "+
+					"with the constraints in place, err: %v", table, err)
+				return
+			}
+			Fatalf("Elevated privileges (table owner or superuser) are required to remove the constraints "+
+				"of table %s, use \"--on-error continue\" to load it with the constraints in place, err: %v",
+				table, err)
+		}
 		if err != nil {
 			// Ignore does not exist error eg.s the primary key is dropped
 			// then the index also goes along with it , so no need to panic here
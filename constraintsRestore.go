@@ -17,8 +17,9 @@ type ForeignKey struct {
 	Table, Column, Reftable, Refcolumn string
 }
 
-// Ty to recreate all the constraints where ever we can
-func FixConstraints() {
+// Ty to recreate all the constraints where ever we can, false if any of
+// them is left for the manual cleanup
+func FixConstraints() bool {
 	//Fix the constraints in this order
 	//var constr = []string{"PRIMARY", "UNIQUE", "CHECK", "FOREIGN"}
 	var constr = []string{"PRIMARY", "UNIQUE", "FOREIGN"}
@@ -43,7 +44,7 @@ func FixConstraints() {
 	}
 
 	// Recreate constraints
-	recreateAllConstraints()
+	return recreateAllConstraints()
 }
 
 // Fix the primary key
@@ -59,7 +60,7 @@ func fixPKey(pk constraint) {
 	}
 	cols := TrimPrefixNSuffix(RemoveEverySuffixAfterADelimiter(keys, " where "), "(", ")")
 
-	for totalViolators > 0 && totalLoop <= maxLoop { // Loop till we get a 0 value (i.e 0 violation ) or max 10 loops
+	for totalViolators > 0 && totalLoop < cmdOptions.MaxUniqueRetries { // Loop till we get a 0 value (i.e 0 violation ) or the max retries
 		// How many violations are we having, if zero then loop breaks
 		totalViolators = getTotalPKViolator(pk.table, cols)
 		if totalViolators > 0 { // Found violation, time to fix it
@@ -80,6 +81,11 @@ func fixPKey(pk constraint) {
 		// care of it
 		totalLoop++
 	}
+	if totalViolators > 0 {
+		Warnf("The key (%s) of table %s still has %d duplicate rows after %d retries (--max-unique-retries), "+
+			"the column may not have enough distinct values, the duplicates are deleted", cols, pk.table,
+			totalViolators, cmdOptions.MaxUniqueRetries)
+	}
 }
 
 // Fix Primary Key string violators.
@@ -178,11 +184,13 @@ func IgnoreErrorString(errmsg string) bool {
 	return false
 }
 
-// Recreate all the constraints of the database ( in case we have dropped any )
-func recreateAllConstraints() {
+// Recreate all the constraints of the database ( in case we have dropped any ),
+// false if any of them couldn't be recreated
+func recreateAllConstraints() bool {
 	Infof("Attempting to recreating all the constraints")
 	failedConstraintsFile := fmt.Sprintf("%s/failed_constraint_creations.sql", Path)
 	var AnyError bool = false
+	var AnyPermissionError bool = false
 
 	// list the backup files collected.
 	for _, con := range constraints {
@@ -210,8 +218,14 @@ func recreateAllConstraints() {
 				if err != nil && !IgnoreErrorString(fmt.Sprintf("%s", err)) {
 					Debugf("Error creating constraint %s, err: %v", content, err)
 					// Try an attempt to recreate constraint again after deleting the
-					// violating row
-					successOrFailure := deleteViolatingPkOrUkConstraints(content)
+					// violating row, there is no point in trying if the user is not
+					// allowed to alter the table
+					successOrFailure := false
+					if isPermissionDenied(err) {
+						AnyPermissionError = true
+					} else {
+						successOrFailure = deleteViolatingPkOrUkConstraints(content)
+					}
 					if !successOrFailure { // didn't succeed, ask the user to fix it manually
 						err = WriteToFile(failedConstraintsFile, content+"\n")
 						if err != nil {
@@ -230,6 +244,12 @@ func recreateAllConstraints() {
 		Warnf("There have been issue creating few constraints and would need manual cleanup at your end, "+
 			"all the constraints that failed has been saved on to file: %s", failedConstraintsFile)
 	}
+	if AnyPermissionError {
+		Warnf("Few constraints could not be recreated since the user %s lacks the privileges to alter "+
+			"the tables, elevated privileges (table owner or superuser) are required to run the "+
+			"constraints saved on to file: %s", cmdOptions.Username, failedConstraintsFile)
+	}
+	return !AnyError && !AnyPermissionError
 }
 
 // we tried to fix the primary key violation, but due to the nature
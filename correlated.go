@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+func init() {
+	registerRowGenerator("correlated",
+		"Value of the column set or NULL depending on a boolean column of the row i.e deleted_at on is_deleted "+
+			"(source_columns: [<boolean column>]), \"when_true: <percent>\" of the true rows get a value "+
+			"(default 100) and \"when_false: <percent>\" of the false or NULL rows (default 0)",
+		buildCorrelated)
+}
+
+// Validate the options of the correlated rule
+func validateCorrelatedRule(c *ColumnRule) error {
+	if len(c.SourceColumns) != 1 {
+		return fmt.Errorf("correlated generator needs the source_columns with the boolean column it depends on")
+	}
+	if c.SourceColumns[0] == c.Column {
+		return fmt.Errorf("column %s cannot depend on itself", c.Column)
+	}
+	if c.WhenTrue == nil {
+		c.WhenTrue = new(float64)
+		*c.WhenTrue = 100
+	}
+	if c.WhenFalse == nil {
+		c.WhenFalse = new(float64)
+	}
+	for _, p := range []float64{*c.WhenTrue, *c.WhenFalse} {
+		if p < 0 || p > 100 {
+			return fmt.Errorf("when_true and when_false should be between 0 and 100, got %v", p)
+		}
+	}
+	return nil
+}
+
+// Correlated generator, the value is generated from the data type of the
+// column on the share of the rows of the boolean and NULL on the rest
+func buildCorrelated(ctx *generatorContext) (interface{}, error) {
+	s := ctx.Rule.SourceColumns[0]
+	v, ok := ctx.Row.Values[s]
+	if !ok {
+		return "", fmt.Errorf("source column %s is not a column of table %s or is not generated yet",
+			s, ctx.Table)
+	}
+	percent := *ctx.Rule.WhenFalse
+	if v != nullValue {
+		flag, err := strconv.ParseBool(v)
+		if err != nil {
+			return "", fmt.Errorf("source column %s has the non boolean value \"%s\"", s, v)
+		}
+		if flag {
+			percent = *ctx.Rule.WhenTrue
+		}
+	}
+	if r.Float64()*100 < percent {
+		return BuildData(ctx.Column.Datatype)
+	}
+	if !ctx.Column.IsNullable {
+		return "", fmt.Errorf("correlated generator leaves the column NULL on some rows, but %s is NOT NULL",
+			ctx.Column.Column)
+	}
+	return nullValue, nil
+}
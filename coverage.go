@@ -0,0 +1,87 @@
+package main
+
+import (
+	"strings"
+	"sync"
+)
+
+// Values of a low cardinality column that each needs to appear at least
+// --min-coverage times, the first rows cycle through them
+type coverage struct {
+	Values []string
+	Next   int
+}
+
+var (
+	coverages     = make(map[string]*coverage)
+	coverageMutex sync.Mutex
+
+	// Data types that BuildData generates without the enum lookup, keep it
+	// in sync with the builders of BuildData
+	builtinDatatypes = []string{"smallint", "integer", "bigint", "oid", "character", "date", "timestamp",
+		"interval", "time", "inet", "cidr", "boolean", "text", "citext", "bytea", "double precision", "real",
+		"money", "numeric", "bit", "uuid", "macaddr", "json", "xml", "tsquery", "tsvector", "pg_lsn",
+		"txid_snapshot", "pg_snapshot", "path", "polygon", "line", "lseg", "box", "circle", "point", "xid", "cid"}
+)
+
+// The next value of the column needed for the coverage, false once all the
+// values appeared --min-coverage times or if the column is not low cardinality
+func coverageValue(tab string, c DBColumns) (string, bool) {
+	if cmdOptions.MinCoverage <= 0 {
+		return "", false
+	}
+	coverageMutex.Lock()
+	defer coverageMutex.Unlock()
+
+	key := ruleKey(tab, c.Column)
+	cv, ok := coverages[key]
+	if !ok {
+		cv = &coverage{Values: coverageValues(tab, c)}
+		coverages[key] = cv
+		if needed := len(cv.Values) * cmdOptions.MinCoverage; needed > rowsToMock(tab) {
+			Warnf("Column %s of table %s needs %d rows to have each of its %d values %d times, "+
+				"only %d rows are mocked", c.Column, tab, needed, len(cv.Values), cmdOptions.MinCoverage, rowsToMock(tab))
+		}
+	}
+	if cv.Next >= len(cv.Values)*cmdOptions.MinCoverage {
+		return "", false
+	}
+	v := cv.Values[cv.Next%len(cv.Values)]
+	cv.Next++
+	return v, true
+}
+
+// All the values of the low cardinality column, i.e the labels of the enum,
+// the categories of the histogram or the values rule, the values of the list partition or booleans
+func coverageValues(tab string, c DBColumns) []string {
+	dt := c.Datatype
+	if isItArray, _ := isDataTypeAnArray(dt); isItArray {
+		return nil
+	}
+	if rule := columnRule(tab, c.Column); rule != nil && !IsStringEmpty(rule.generatorName()) {
+		if rule.histogram != nil {
+			return rule.histogram.Values
+		}
+		if rule.generatorName() == "values" {
+			return distinctStrings(rule.Values)
+		}
+		return nil // the rest of the generators decide their own values
+	}
+	if b := partitionKeyBound(tab, c.Column); b != nil {
+		if b.Strategy == "list" {
+			return b.Values
+		}
+		return nil
+	}
+	if strings.HasPrefix(dt, "boolean") {
+		return []string{"true", "false"}
+	}
+	if StringHasPrefix(dt, builtinDatatypes) {
+		return nil
+	}
+	var labels []string
+	for _, e := range checkEnumDatatype(dt) {
+		labels = append(labels, e.EnumValue)
+	}
+	return labels
+}
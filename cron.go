@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A field of the cron expression and its allowed range
+type cronField struct {
+	Name     string
+	Min, Max int
+}
+
+var (
+	// Fields of the cron expression, the seconds is only on the 6 field format
+	cronSecond = cronField{"second", 0, 59}
+	cronFields = []cronField{{"minute", 0, 59}, {"hour", 0, 23}, {"day of month", 1, 31},
+		{"month", 1, 12}, {"day of week", 0, 6}}
+)
+
+func init() {
+	registerGenerator("cron",
+		"Valid cron expressions, 5 fields or 6 fields with seconds (cron_fields: 6)",
+		buildCron)
+	registerNameHint(`^(.*_)?(cron|crontab|cron_?expr(ession)?|schedule)$`, "cron", 32)
+}
+
+// Cron generator
+func buildCron(ctx *generatorContext) (interface{}, error) {
+	fields := cronFields
+	if ctx.Rule != nil && ctx.Rule.CronFields == 6 {
+		fields = append([]cronField{cronSecond}, cronFields...)
+	}
+	var expr []string
+	for i, f := range fields {
+		expr = append(expr, randomCronField(f, i < len(fields)-3))
+	}
+	cron := strings.Join(expr, " ")
+	if err := validateCron(cron); err != nil {
+		return "", fmt.Errorf("generated an invalid cron expression \"%s\": %v", cron, err)
+	}
+	return cron, nil
+}
+
+// Random value of the cron field, the time of the day fields are mostly
+// fixed while the calendar fields are mostly left as "*" like the usual schedules.
+// RandomInt leaves out its max, hence the f.Max+1 for the last value
+func randomCronField(f cronField, timeOfDay bool) string {
+	n := RandomInt(0, 100)
+	if !timeOfDay && n < 70 {
+		return "*"
+	}
+	if timeOfDay && n < 60 {
+		return strconv.Itoa(RandomInt(f.Min, f.Max+1))
+	}
+	switch n % 4 {
+	case 0: // step
+		return fmt.Sprintf("*/%d", RandomInt(2, (f.Max-f.Min+1)/2+1))
+	case 1: // range
+		from := RandomInt(f.Min, f.Max)
+		return fmt.Sprintf("%d-%d", from, RandomInt(from+1, f.Max+1))
+	case 2: // list
+		a := RandomInt(f.Min, (f.Min+f.Max)/2+1)
+		return fmt.Sprintf("%d,%d", a, RandomInt(a+1, f.Max+1))
+	}
+	return strconv.Itoa(RandomInt(f.Min, f.Max+1))
+}
+
+// Validate the cron expression, each field is either "*", a value, a range
+// or a list of them with an optional step
+func validateCron(cron string) error {
+	expr := strings.Fields(cron)
+	fields := cronFields
+	switch len(expr) {
+	case 5:
+	case 6:
+		fields = append([]cronField{cronSecond}, cronFields...)
+	default:
+		return fmt.Errorf("expected 5 or 6 fields, got %d", len(expr))
+	}
+	for i, f := range fields {
+		for _, part := range strings.Split(expr[i], ",") {
+			if err := validateCronPart(part, f); err != nil {
+				return fmt.Errorf("%s field \"%s\": %v", f.Name, expr[i], err)
+			}
+		}
+	}
+	return nil
+}
+
+// Validate a single item of the cron field list i.e "*", "5", "1-5", "*/15" or "0-30/5"
+func validateCronPart(part string, f cronField) error {
+	s := strings.SplitN(part, "/", 2)
+	if len(s) == 2 {
+		step, err := strconv.Atoi(s[1])
+		if err != nil || step < 1 {
+			return fmt.Errorf("invalid step %s", s[1])
+		}
+	}
+	if s[0] == "*" {
+		return nil
+	}
+	bounds := strings.SplitN(s[0], "-", 2)
+	var values []int
+	for _, b := range bounds {
+		v, err := strconv.Atoi(b)
+		if err != nil {
+			return fmt.Errorf("invalid value %s", b)
+		}
+		if v < f.Min || v > f.Max {
+			return fmt.Errorf("value %d is outside %d-%d", v, f.Min, f.Max)
+		}
+		values = append(values, v)
+	}
+	if len(values) == 2 && values[0] > values[1] {
+		return fmt.Errorf("range %s is reversed", s[0])
+	}
+	return nil
+}
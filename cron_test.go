@@ -0,0 +1,53 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRandomCronField(t *testing.T) {
+	for _, f := range append([]cronField{cronSecond}, cronFields...) {
+		seen := make(map[int]bool)
+		for _, timeOfDay := range []bool{true, false} {
+			for i := 0; i < 3000; i++ {
+				field := randomCronField(f, timeOfDay)
+				for _, part := range strings.Split(field, ",") {
+					if err := validateCronPart(part, f); err != nil {
+						t.Fatalf("%s field %q: %v", f.Name, field, err)
+					}
+				}
+				for _, part := range strings.FieldsFunc(field, func(c rune) bool { return c == ',' || c == '-' }) {
+					if strings.HasPrefix(part, "*") {
+						continue
+					}
+					v, err := strconv.Atoi(part)
+					if err != nil || v < f.Min || v > f.Max {
+						t.Fatalf("%s field %q has %q outside %d-%d", f.Name, field, part, f.Min, f.Max)
+					}
+					seen[v] = true
+				}
+			}
+		}
+		for v := f.Min; v <= f.Max; v++ {
+			if !seen[v] {
+				t.Errorf("%s field never had the value %d of %d-%d", f.Name, v, f.Min, f.Max)
+			}
+		}
+	}
+}
+
+func TestBuildCron(t *testing.T) {
+	for _, fields := range []int{5, 6} {
+		rule := &ColumnRule{CronFields: fields}
+		for i := 0; i < 500; i++ {
+			v, err := buildCron(&generatorContext{Rule: rule})
+			if err != nil {
+				t.Fatalf("buildCron: %v", err)
+			}
+			if n := len(strings.Fields(v.(string))); n != fields {
+				t.Fatalf("buildCron = %q has %d fields, want %d", v, n, fields)
+			}
+		}
+	}
+}
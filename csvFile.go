@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Extension of the csv files of the tables, the compressed ones have the
+// extension of the compression after it
+const (
+	csvExtension  = ".csv"
+	gzipExtension = ".gz"
+)
+
+// Write the rows of the table to a csv file, its the same format as the
+// COPY to the database so the file can be loaded with
+// COPY ... FROM '<file>' WITH CSV HEADER DELIMITER '$' QUOTE e'\x01' NULL E'\\N'
+type csvWriter struct {
+	file *os.File
+	gz   *gzip.Writer // nil unless --compress gzip
+	w    *bufio.Writer
+
+	// The file as its listed on the manifest
+	manifest manifestFile
+}
+
+// Create the csv file <dir>/<schema>.<table><part>.csv, or .csv.gz when its
+// compressed, the first line is the header. The part is empty unless the
+// table is split to shards
+func newCSVWriter(t TableCollection, col []string, part string) (*csvWriter, error) {
+	err := os.MkdirAll(cmdOptions.OutputDir, os.ModePerm)
+	if err != nil {
+		return nil, fmt.Errorf("creating the output directory: %v", err)
+	}
+	filename := filepath.Join(cmdOptions.OutputDir, fmt.Sprintf("%s.%s%s%s", t.Schema, t.Table, part, csvExtension))
+	if cmdOptions.Compress == "gzip" {
+		filename += gzipExtension
+	}
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, fmt.Errorf("creating the csv file: %v", err)
+	}
+	w := &csvWriter{file: file, w: bufio.NewWriter(file), manifest: manifestFile{File: filename,
+		Schema: t.Schema, Table: t.Table, Format: "csv", Columns: col}}
+	if cmdOptions.Compress == "gzip" {
+		w.gz = gzip.NewWriter(file)
+		w.w = bufio.NewWriter(w.gz)
+	}
+	if err := w.Write(col); err != nil {
+		file.Close()
+		return nil, err
+	}
+	w.manifest.Rows = 0 // the header isn't a row
+	Debugf("Writing the mock data of table %s.%s to the csv file %s", t.Schema, t.Table, filename)
+	return w, nil
+}
+
+// Write the row to the csv file
+func (w *csvWriter) Write(data []string) error {
+	if _, err := w.w.WriteString(copyRow(data) + "\n"); err != nil {
+		return fmt.Errorf("writing to the csv file: %v", err)
+	}
+	w.manifest.Rows++
+	return nil
+}
+
+// Flush the buffered rows and close the file
+func (w *csvWriter) Close() error {
+	if err := w.w.Flush(); err != nil {
+		w.file.Close()
+		return fmt.Errorf("completing the csv file: %v", err)
+	}
+	if w.gz != nil {
+		if err := w.gz.Close(); err != nil {
+			w.file.Close()
+			return fmt.Errorf("completing the compressed csv file: %v", err)
+		}
+	}
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	addManifestFile(w.manifest)
+	return nil
+}
+
+// Read the rows of the csv files written by the csvWriter
+type csvReader struct {
+	r *bufio.Reader
+}
+
+// Open the csv reader on the reader
+func newCSVReader(r io.Reader) *csvReader {
+	return &csvReader{r: bufio.NewReader(r)}
+}
+
+// Read the next row, the NULLs are returned as nullValue and its io.EOF
+// when there are no more rows
+func (c *csvReader) Read() ([]string, error) {
+	var row []string
+	var field strings.Builder
+	quoted, wasQuoted := false, false
+	read := false
+	for {
+		ch, _, err := c.r.ReadRune()
+		if err == io.EOF {
+			if quoted {
+				return nil, fmt.Errorf("unterminated quoted value")
+			}
+			if !read {
+				return nil, io.EOF
+			}
+			return append(row, csvField(field.String(), wasQuoted)), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		read = true
+		switch {
+		case ch == '\x01' && quoted:
+			if next, _, err := c.r.ReadRune(); err == nil && next == '\x01' {
+				field.WriteRune(ch) // doubled quote is a literal quote
+				continue
+			} else if err == nil {
+				c.r.UnreadRune()
+			}
+			quoted = false
+		case ch == '\x01':
+			quoted, wasQuoted = true, true
+		case quoted:
+			field.WriteRune(ch)
+		case string(ch) == delimiter:
+			row = append(row, csvField(field.String(), wasQuoted))
+			field.Reset()
+			wasQuoted = false
+		case ch == '\n':
+			return append(row, csvField(field.String(), wasQuoted)), nil
+		default:
+			field.WriteRune(ch)
+		}
+	}
+}
+
+// Unquoted \N is a NULL
+func csvField(value string, quoted bool) string {
+	if !quoted && value == `\N` {
+		return nullValue
+	}
+	return value
+}
@@ -30,7 +30,7 @@ func GenerateMockPlan() {
 	Infof("Generating a skeleton YAML for the list of table provided")
 
 	// Check if the tables provided exists
-	whereClause := generateWhereClause()
+	whereClause := generateWhereClause(strings.Split(cmdOptions.Tab.FakeTablesRows, ","))
 	tableList := dbExtractTables(whereClause)
 
 	// If there is any then extract the column and data type
@@ -129,13 +129,21 @@ func (c *Skeleton) LoadDataByConfiguration() {
 	for _, s := range c.Custom {
 		// Initialize the mocking process
 		tab := GenerateTableName(s.Table, s.Schema)
+		mockedTables = append(mockedTables, tab)
 		msg := fmt.Sprintf("Mocking Table %s", tab)
-		bar := StartProgressBar(msg, cmdOptions.Rows)
+		rows := rowsToMock(tab)
+		bar := StartProgressBar(msg, rows)
+
+		// The rows go to the files of --output-dir or --output-parquet instead of the table
+		var w rowWriter
+		if isFileOutput() {
+			w = newCustomFileWriter(s, tab)
+		}
 
 		// Name the for loop to break when we encounter error
 	DataTypePickerLoop:
 		// Loop through the row count and start loading the data
-		for i := 0; i < cmdOptions.Rows; i++ {
+		for i := 0; i < rows; i++ {
 			var data []string
 			var col []string
 
@@ -148,8 +156,8 @@ func (c *Skeleton) LoadDataByConfiguration() {
 					if err != nil {
 						if strings.HasPrefix(fmt.Sprint(err), "unsupported datatypes found") {
 							Debugf("Table %s skipped: %v", tab, err)
-							skippedTab = append(skippedTab, tab)
-							bar.Add(cmdOptions.Rows)
+							addSkippedTable(tab, err.Error())
+							bar.Add(rows)
 							break DataTypePickerLoop
 						} else {
 							Fatalf("Error when building data for table %s: %v", tab, err)
@@ -169,8 +177,37 @@ func (c *Skeleton) LoadDataByConfiguration() {
 			}
 
 			// Copy the data to the table
-			CopyData(tab, col, data, db)
+			if w != nil {
+				if err := w.Write(data); err != nil {
+					Fatalf("Error when writing the data of table %s: %v", tab, err)
+				}
+			} else {
+				if err := CopyData(tab, col, [][]string{data}, db); err != nil {
+					Fatalf("Error when loading the data of table %s: %v", tab, err)
+				}
+			}
 			bar.Add(1)
 		}
+		if w != nil {
+			if err := w.Close(); err != nil {
+				Fatalf("Error when completing the data of table %s: %v", tab, err)
+			}
+		}
+	}
+}
+
+// Open the file the rows of the custom table are written to, the columns
+// have the data types of the configuration
+func newCustomFileWriter(s TableModel, tab string) rowWriter {
+	t := TableCollection{DBTables: DBTables{Schema: s.Schema, Table: s.Table}}
+	var col []string
+	for _, v := range s.Column {
+		t.Columns = append(t.Columns, DBColumns{Column: v.Name, Datatype: v.Type})
+		col = append(col, v.Name)
+	}
+	w, err := newRowWriter(t, tab, col)
+	if err != nil {
+		Fatalf("Error when opening the destination of the data of table %s: %v", tab, err)
 	}
+	return w
 }
@@ -29,8 +29,7 @@ func ExecuteDemoDatabase() {
 func MockDatabase() {
 	// Get the table list that we have to mock the data
 	Infof("Starting the program to mock full database")
-	tableList := dbExtractTables("")
-	MockTable(tableList)
+	MockTable(activeBackend.tables("", nil))
 }
 
 // Extract all the tables in the database
@@ -1,5 +1,69 @@
 package main
 
+import (
+	"fmt"
+	"strings"
+)
+
+// A data type family that BuildData generates data for
+type supportedType struct {
+	Family string
+	Types  string
+	Notes  string
+}
+
+// The data types that BuildData supports, grouped the same way as the demo table
+var supportedTypes = []supportedType{
+	{"Integer", "smallint, integer, bigint, oid, serial, smallserial, bigserial",
+		"serial columns are left to their sequence"},
+	{"Float", "real, double precision, numeric, numeric(p,s)", ""},
+	{"Bit String", "bit, bit(n), bit varying(n)", ""},
+	{"Boolean", "boolean", ""},
+	{"Character", "character(n), character varying(n), text, citext", "citext needs the citext extension"},
+	{"Network Address", "inet, cidr, macaddr", ""},
+	{"Date / Time", "date, time, time with time zone, timestamp, timestamp with time zone, interval", ""},
+	{"Monetary", "money", ""},
+	{"JSON", "json, jsonb", "jsonb needs postgres 9.4+ or greenplum 6+"},
+	{"XML", "xml", ""},
+	{"Text Search", "tsquery, tsvector", ""},
+	{"Geometric", "box, circle, line, lseg, path, polygon, point", ""},
+	{"Binary", "bytea", ""},
+	{"Log Sequence Number", "pg_lsn", "needs postgres 9.4+ or greenplum 6+"},
+	{"Transaction Snapshot", "txid_snapshot, pg_snapshot", "pg_snapshot needs postgres 13+"},
+	{"UUID", "uuid", ""},
+	{"Enum", "user defined enums", "the labels are read from the database"},
+	{"System", "xid, xid8, cid", "best effort, random unsigned 32 bit values"},
+	{"Arrays", "<any of the above>[]", "generated as single dimension arrays"},
+}
+
+// Print the supported data types and the named generators of the rules file
+func PrintSupportedTypes() {
+	fmt.Println("Data types:")
+	for _, t := range supportedTypes {
+		fmt.Printf("  %-22s %s\n", t.Family, t.Types)
+		if !IsStringEmpty(t.Notes) {
+			fmt.Printf("  %-22s (%s)\n", "", t.Notes)
+		}
+	}
+	if len(extensionTypes) > 0 {
+		types, notes := extensionTypeList()
+		fmt.Printf("  %-22s %s\n  %-22s (%s)\n", "Extensions", types, "", notes)
+	}
+	fmt.Println()
+	fmt.Println("Named generators (rules file):")
+	for _, n := range generatorNames() {
+		fmt.Printf("  %-22s %s\n", n, generators[n].Description)
+	}
+	fmt.Println()
+	fmt.Println("Notes:")
+	fmt.Println("  " + strings.Join([]string{
+		"Nullable columns of any other data type, i.e custom types or domains, are loaded with NULLs",
+		"and the NOT NULL ones get the table skipped, use a rule or the custom sub command to control their data.",
+		"On greenplum partition tables are not supported due to the check constraints,",
+		"on postgres the partition key values are kept within the partition bounds.",
+	}, "\n  "))
+}
+
 func SupportedDataTypes() []string {
 	return []string{
 		"int8,",
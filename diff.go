@@ -0,0 +1,213 @@
+package main
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Difference of a table between the two output directories
+type tableDiff struct {
+	Table          string
+	OldRows        int
+	NewRows        int
+	ChangedRows    int
+	AddedColumns   []string
+	RemovedColumns []string
+	ChangedColumns map[string]int // column and the rows where its value changed
+}
+
+// Compare the csv files of the two output directories and print a summary
+// of the tables, rows and columns that changed
+func PreviewDiff(oldDir, newDir string) {
+	Infof("Comparing the mock data of the directory %s with %s", oldDir, newDir)
+	oldTables, newTables := csvTables(oldDir), csvTables(newDir)
+
+	var names []string
+	for t := range oldTables {
+		names = append(names, t)
+	}
+	for t := range newTables {
+		if _, ok := oldTables[t]; !ok {
+			names = append(names, t)
+		}
+	}
+	sort.Strings(names)
+
+	var changed int
+	for _, t := range names {
+		oldFile, inOld := oldTables[t]
+		newFile, inNew := newTables[t]
+		switch {
+		case !inOld:
+			fmt.Printf("+ %s: added\n", t)
+			changed++
+		case !inNew:
+			fmt.Printf("- %s: removed\n", t)
+			changed++
+		default:
+			d, err := diffCSVFiles(t, oldFile, newFile)
+			if err != nil {
+				Fatalf("Error when comparing the table %s: %v", t, err)
+			}
+			if d.printSummary() {
+				changed++
+			}
+		}
+	}
+	Infof("Compared %d tables, %d of them changed", len(names), changed)
+}
+
+// The csv files of the output directory by the table name, either plain
+// or compressed
+func csvTables(dir string) map[string]string {
+	tables := make(map[string]string)
+	for _, ext := range []string{csvExtension, csvExtension + gzipExtension} {
+		files, err := filepath.Glob(filepath.Join(dir, "*"+ext))
+		if err != nil {
+			Fatalf("Error when listing the csv files of the directory %s: %v", dir, err)
+		}
+		for _, f := range files {
+			tables[strings.TrimSuffix(filepath.Base(f), ext)] = f
+		}
+	}
+	if len(tables) == 0 {
+		Warnf("No csv files found in the directory %s", dir)
+	}
+	return tables
+}
+
+// Compare the rows of the two csv files of the table, the rows are matched
+// by their position since the same seed generates the rows in the same order
+func diffCSVFiles(table, oldFile, newFile string) (*tableDiff, error) {
+	oldReader, oldClose, err := openCSV(oldFile)
+	if err != nil {
+		return nil, err
+	}
+	defer oldClose()
+	newReader, newClose, err := openCSV(newFile)
+	if err != nil {
+		return nil, err
+	}
+	defer newClose()
+
+	oldHeader, err := oldReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading the header of %s: %v", oldFile, err)
+	}
+	newHeader, err := newReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading the header of %s: %v", newFile, err)
+	}
+
+	// Match the columns by their name
+	d := &tableDiff{Table: table, ChangedColumns: make(map[string]int)}
+	oldIndex := make(map[string]int)
+	for i, c := range oldHeader {
+		oldIndex[c] = i
+	}
+	newIndex := make(map[string]int)
+	for i, c := range newHeader {
+		newIndex[c] = i
+		if _, ok := oldIndex[c]; !ok {
+			d.AddedColumns = append(d.AddedColumns, c)
+		}
+	}
+	for _, c := range oldHeader {
+		if _, ok := newIndex[c]; !ok {
+			d.RemovedColumns = append(d.RemovedColumns, c)
+		}
+	}
+
+	for {
+		oldRow, oldErr := oldReader.Read()
+		newRow, newErr := newReader.Read()
+		if oldErr != nil && oldErr != io.EOF {
+			return nil, fmt.Errorf("reading %s: %v", oldFile, oldErr)
+		}
+		if newErr != nil && newErr != io.EOF {
+			return nil, fmt.Errorf("reading %s: %v", newFile, newErr)
+		}
+		if oldErr == io.EOF && newErr == io.EOF {
+			break
+		}
+		if oldErr == nil {
+			d.OldRows++
+		}
+		if newErr == nil {
+			d.NewRows++
+		}
+		if oldErr != nil || newErr != nil {
+			continue // the extra rows are counted as added or removed
+		}
+		rowChanged := false
+		for _, c := range newHeader {
+			i, ok := oldIndex[c]
+			if !ok || i >= len(oldRow) || newIndex[c] >= len(newRow) {
+				continue
+			}
+			if oldRow[i] != newRow[newIndex[c]] {
+				d.ChangedColumns[c]++
+				rowChanged = true
+			}
+		}
+		if rowChanged {
+			d.ChangedRows++
+		}
+	}
+	return d, nil
+}
+
+// Open the csv file, the gzip files are decompressed on the fly
+func openCSV(filename string) (*csvReader, func() error, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening the csv file: %v", err)
+	}
+	if !strings.HasSuffix(filename, gzipExtension) {
+		return newCSVReader(f), f.Close, nil
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("opening the compressed csv file: %v", err)
+	}
+	return newCSVReader(gz), func() error {
+		gz.Close()
+		return f.Close()
+	}, nil
+}
+
+// Print the summary of the table, false if nothing changed
+func (d *tableDiff) printSummary() bool {
+	if d.OldRows == d.NewRows && d.ChangedRows == 0 && len(d.AddedColumns) == 0 && len(d.RemovedColumns) == 0 {
+		fmt.Printf("  %s: unchanged (%d rows)\n", d.Table, d.NewRows)
+		return false
+	}
+	fmt.Printf("~ %s: %d -> %d rows", d.Table, d.OldRows, d.NewRows)
+	if d.NewRows > d.OldRows {
+		fmt.Printf(", %d added", d.NewRows-d.OldRows)
+	} else if d.NewRows < d.OldRows {
+		fmt.Printf(", %d removed", d.OldRows-d.NewRows)
+	}
+	fmt.Printf(", %d changed\n", d.ChangedRows)
+	if len(d.AddedColumns) > 0 {
+		fmt.Printf("    added columns: %s\n", strings.Join(d.AddedColumns, ", "))
+	}
+	if len(d.RemovedColumns) > 0 {
+		fmt.Printf("    removed columns: %s\n", strings.Join(d.RemovedColumns, ", "))
+	}
+	var columns []string
+	for c := range d.ChangedColumns {
+		columns = append(columns, c)
+	}
+	sort.Strings(columns)
+	for _, c := range columns {
+		fmt.Printf("    %s: changed on %d rows\n", c, d.ChangedColumns[c])
+	}
+	return true
+}
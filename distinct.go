@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	// Values of the columns with max_distinct, the rows pick from them
+	distinctPools     = make(map[string][]interface{})
+	distinctPoolMutex sync.Mutex
+)
+
+// Validate the max_distinct of the rule, the values derived from the
+// row or unique on every row cannot be pooled
+func validateMaxDistinct(c *ColumnRule) error {
+	if c.MaxDistinct < 0 {
+		return fmt.Errorf("max_distinct cannot be negative, got %d", c.MaxDistinct)
+	}
+	name := c.generatorName()
+	switch {
+	case c.MaxDistinct == 0:
+	case name == "unique":
+		return fmt.Errorf("max_distinct cannot be used along with the unique generator")
+	case generators[name].RowScoped:
+		return fmt.Errorf("max_distinct cannot be used along with the %s generator, its values depend on the row", name)
+	}
+	return nil
+}
+
+// A value of the pool of the column, the pool is filled with max_distinct
+// values of the column on its first value. The columns whose values repeat
+// more than that end up with a smaller pool
+func distinctValue(tab string, c DBColumns, row *rowContext) (interface{}, error) {
+	distinctPoolMutex.Lock()
+	defer distinctPoolMutex.Unlock()
+	key := ruleKey(tab, c.Column)
+	pool, ok := distinctPools[key]
+	if !ok {
+		size := columnRule(tab, c.Column).MaxDistinct
+		seen := make(map[string]bool)
+		for attempts := 0; len(pool) < size && attempts < size*maxLoop; attempts++ {
+			v, err := buildColumnValue(tab, c, row)
+			if err != nil {
+				return v, err
+			}
+			if s := fmt.Sprint(v); !seen[s] {
+				seen[s] = true
+				pool = append(pool, v)
+			}
+		}
+		if len(pool) < size {
+			Debugf("Column %s of table %s only has %d of the %d max_distinct values", c.Column, tab, len(pool), size)
+		}
+		distinctPools[key] = pool
+	}
+	return pool[r.Intn(len(pool))], nil
+}
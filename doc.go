@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Rows of each table kept as the sample values of the data dictionary
+const docSampleRows = 3
+
+var (
+	docSamples     = make(map[string][][]string)
+	docSampleMutex sync.Mutex
+)
+
+// Keep the first few rows of the table for the data dictionary
+func recordSample(tab string, data []string) {
+	if IsStringEmpty(cmdOptions.DocOutput) {
+		return
+	}
+	docSampleMutex.Lock()
+	defer docSampleMutex.Unlock()
+	if len(docSamples[tab]) < docSampleRows {
+		docSamples[tab] = append(docSamples[tab], append([]string(nil), data...))
+	}
+}
+
+// Write the data dictionary of the mocked tables, the columns with how
+// they are generated, the NULLs and a few sample values. Its markdown
+// unless the file ends with .html
+func WriteDataDictionary(tables []TableCollection) {
+	Infof("Writing the data dictionary of the mock data to the file: %s", cmdOptions.DocOutput)
+	var doc strings.Builder
+	asHTML := strings.EqualFold(filepath.Ext(cmdOptions.DocOutput), ".html")
+	title := "Mock data dictionary"
+	generated := fmt.Sprintf("Generated by %s %s on %s", programName, programVersion, ExecutionTimestamp)
+	if cmdOptions.Seed != 0 {
+		generated += fmt.Sprintf(" with --seed %d", cmdOptions.Seed)
+	}
+	if asHTML {
+		fmt.Fprintf(&doc, "<!DOCTYPE html>\n<html>\n<head><meta charset=\"utf-8\"><title>%s</title></head>\n<body>\n", title)
+		fmt.Fprintf(&doc, "<h1>%s</h1>\n<p>%s</p>\n", title, html.EscapeString(generated))
+	} else {
+		fmt.Fprintf(&doc, "# %s\n\n%s\n", title, generated)
+	}
+
+	for _, t := range tables {
+		tab := GenerateTableName(t.Table, t.Schema)
+		rows := fmt.Sprintf("%d rows", rowsToMock(tab))
+		if reason, ok := skipReasons[tab]; ok {
+			rows = "skipped, " + reason
+		} else if StringContains(tab, skippedTab) {
+			rows = "skipped, it has unsupported data types"
+		}
+		header := []string{"Column", "Data type", "Generated with", "NULLs", "Samples"}
+		var lines [][]string
+		for i, c := range t.Columns {
+			var samples []string
+			for _, s := range docSamples[tab] {
+				samples = append(samples, docValue(s[i]))
+			}
+			lines = append(lines, []string{c.Column, c.Datatype, describeColumn(tab, c),
+				fmt.Sprintf("%.0f%%", nullProbability(tab, c)*100), strings.Join(samples, ", ")})
+		}
+
+		if asHTML {
+			fmt.Fprintf(&doc, "<h2>%s</h2>\n<p>%s</p>\n<table>\n", html.EscapeString(tab), rows)
+			for i, l := range append([][]string{header}, lines...) {
+				cell := "td"
+				if i == 0 {
+					cell = "th"
+				}
+				doc.WriteString("<tr>")
+				for _, v := range l {
+					fmt.Fprintf(&doc, "<%[1]s>%[2]s</%[1]s>", cell, html.EscapeString(v))
+				}
+				doc.WriteString("</tr>\n")
+			}
+			doc.WriteString("</table>\n")
+			continue
+		}
+		fmt.Fprintf(&doc, "\n## %s\n\n%s\n\n", tab, rows)
+		fmt.Fprintf(&doc, "| %s |\n|%s\n", strings.Join(header, " | "), strings.Repeat("---|", len(header)))
+		for _, l := range lines {
+			for i, v := range l {
+				l[i] = strings.NewReplacer("|", `\|`, "\n", " ", "\r", " ").Replace(v)
+			}
+			fmt.Fprintf(&doc, "| %s |\n", strings.Join(l, " | "))
+		}
+	}
+	if asHTML {
+		doc.WriteString("</body>\n</html>\n")
+	}
+
+	if err := ioutil.WriteFile(cmdOptions.DocOutput, []byte(doc.String()), 0644); err != nil {
+		Fatalf("Error when writing the data dictionary %s, err: %v", cmdOptions.DocOutput, err)
+	}
+}
+
+// Sample value as shown on the data dictionary, the long values are cut
+func docValue(v string) string {
+	if v == nullValue {
+		return "NULL"
+	}
+	if runes := []rune(v); len(runes) > 40 {
+		return string(runes[:40]) + "…"
+	}
+	return v
+}
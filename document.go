@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/icrowley/fake"
+	"strings"
+)
+
+const (
+	// Deepest nesting of the documents, deeper documents grow too large
+	documentMaxDepth = 8
+
+	// The most values of a document, once reached the rest of the
+	// document is filled with scalars so the size stays bounded
+	documentMaxNodes = 1000
+)
+
+// Keys of the objects of the documents, unless the rule has its own keys
+var documentKeys = []string{"id", "name", "type", "status", "created_at", "updated_at", "owner", "tags",
+	"items", "price", "quantity", "total", "currency", "address", "city", "country", "email", "phone",
+	"notes", "metadata", "settings", "enabled", "version", "score", "children", "attributes", "source"}
+
+func init() {
+	registerGenerator("document",
+		"Nested JSON documents of objects and arrays of objects for the json / jsonb columns, \"depth: <n>\" "+
+			"levels (default 3, at most 8), min_items / max_items keys or elements each (default 1 to 4) "+
+			"and the object keys (keys: [<key>, ...])",
+		buildDocument)
+}
+
+// Validate the options of the document rule
+func validateDocumentRule(c *ColumnRule) error {
+	if c.Depth == 0 {
+		c.Depth = 3
+	}
+	if c.Depth < 1 || c.Depth > documentMaxDepth {
+		return fmt.Errorf("document depth should be between 1 and %d, got %d", documentMaxDepth, c.Depth)
+	}
+	if c.MinItems == 0 && c.MaxItems == 0 {
+		c.MinItems, c.MaxItems = 1, 4
+	}
+	if c.MinItems < 0 || c.MaxItems < c.MinItems {
+		return fmt.Errorf("document min_items %d and max_items %d should be 0 <= min_items <= max_items",
+			c.MinItems, c.MaxItems)
+	}
+	keys := documentKeys
+	if len(c.Keys) > 0 {
+		keys = c.Keys
+	}
+	if c.MaxItems > len(keys) {
+		return fmt.Errorf("document max_items %d is more than the %d keys of the objects", c.MaxItems, len(keys))
+	}
+	return nil
+}
+
+// Document generator, the value is encoded by the json package so its
+// always valid and escaped, the array columns get a few documents
+func buildDocument(ctx *generatorContext) (interface{}, error) {
+	dt := ctx.Column.Datatype
+	if !strings.HasPrefix(dt, "json") {
+		return "", fmt.Errorf("document generator only supports the json and jsonb columns, got %s", dt)
+	}
+	if isItArray, _ := isDataTypeAnArray(dt); isItArray {
+		var docs []string
+		for i := RandomInt(1, 4); i > 0; i-- {
+			d, err := newDocument(ctx.Rule)
+			if err != nil {
+				return "", err
+			}
+			docs = append(docs, `"`+strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(d)+`"`)
+		}
+		return fmt.Sprintf("{%s}", strings.Join(docs, ",")), nil
+	}
+	return newDocument(ctx.Rule)
+}
+
+// A random document, an object at the top level
+func newDocument(rule *ColumnRule) (string, error) {
+	nodes := 0
+	doc, err := json.Marshal(documentObject(rule, 1, &nodes))
+	if err != nil {
+		return "", fmt.Errorf("encoding the document: %v", err)
+	}
+	return string(doc), nil
+}
+
+// An object whose values nest down to the depth of the rule
+func documentObject(rule *ColumnRule, depth int, nodes *int) map[string]interface{} {
+	keys := documentKeys
+	if len(rule.Keys) > 0 {
+		keys = rule.Keys
+	}
+	object := make(map[string]interface{})
+	for _, i := range r.Perm(len(keys))[:RandomInt(rule.MinItems, rule.MaxItems+1)] {
+		object[keys[i]] = documentValue(rule, depth, nodes)
+	}
+	return object
+}
+
+// A value of the object, the nested ones only below the depth of the rule
+func documentValue(rule *ColumnRule, depth int, nodes *int) interface{} {
+	*nodes++
+	if depth < rule.Depth && *nodes < documentMaxNodes {
+		switch RandomInt(0, 4) {
+		case 0:
+			return documentObject(rule, depth+1, nodes)
+		case 1:
+			array := []interface{}{}
+			for i := RandomInt(rule.MinItems, rule.MaxItems+1); i > 0; i-- {
+				array = append(array, documentObject(rule, depth+1, nodes))
+			}
+			return array
+		}
+	}
+	switch RandomInt(0, 5) {
+	case 0:
+		return RandomInt(0, 10000)
+	case 1:
+		return RandomBoolean()
+	case 2:
+		return nil
+	case 3:
+		return fake.Sentence()
+	}
+	return fake.Word()
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"strings"
+)
+
+// Most levels of the domains over domains that are resolved
+const maxDomainDepth = 10
+
+// The domains already resolved, by their data type. The data types that are
+// not domains resolve to themselves
+var resolvedDomains = make(map[string]DBDomain)
+
+// Lookup of the domain in the database, replaced by the tests
+var getDomain = GetDomain
+
+// Replace the domains of the columns by their base type, the columns of the
+// domains with NOT NULL don't get NULLs. The CHECK constraints of the domains
+// are not known to the generators, so the values are only checked by the
+// database
+func resolveDomains(tab string, columns []DBColumns) {
+	for i, c := range columns {
+		isItArray, dt := isDataTypeAnArray(c.Datatype)
+		d := resolveDomain(dt)
+		if d.Basetype == dt {
+			continue
+		}
+		Debugf("Column %s of table %s is generated as %s, the base type of its domain %s",
+			c.Column, tab, d.Basetype, c.Datatype)
+		if isItArray {
+			d.Basetype += "[]"
+		}
+		columns[i].Datatype = d.Basetype
+		columns[i].MaxLength = declaredLength(d.Basetype)
+		if d.NotNull && !isItArray {
+			columns[i].IsNullable = false
+		}
+		if len(d.Checks) > 0 && columnRule(tab, c.Column) == nil {
+			Warnf("Column %s of table %s has the domain %s with %s, its random %s values might not satisfy "+
+				"it, use a rule to generate the values that do", c.Column, tab, dt, strings.Join(d.Checks, " and "),
+				d.Basetype)
+		}
+	}
+}
+
+// The base type of the data type along with the NOT NULL and CHECK
+// constraints of all the domains on the way to it
+func resolveDomain(dt string) DBDomain {
+	if d, ok := resolvedDomains[dt]; ok {
+		return d
+	}
+	resolved := DBDomain{Basetype: dt}
+	for depth := 0; depth < maxDomainDepth; depth++ {
+		d, ok := getDomain(resolved.Basetype)
+		if !ok {
+			break
+		}
+		resolved.Basetype = d.Basetype
+		resolved.NotNull = resolved.NotNull || d.NotNull
+		resolved.Checks = append(resolved.Checks, d.Checks...)
+	}
+	resolvedDomains[dt] = resolved
+	return resolved
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Replace the domains of the database by the given ones for the test
+func testDomains(t *testing.T, domains map[string]DBDomain) {
+	lookup, resolved := getDomain, resolvedDomains
+	getDomain = func(dt string) (DBDomain, bool) {
+		d, ok := domains[dt]
+		return d, ok
+	}
+	resolvedDomains = make(map[string]DBDomain)
+	t.Cleanup(func() { getDomain, resolvedDomains = lookup, resolved })
+}
+
+func TestResolveDomain(t *testing.T) {
+	testDomains(t, map[string]DBDomain{
+		"price":          {Basetype: "numeric(10,2)", Checks: []string{"CHECK (VALUE >= 0)"}},
+		"positive_price": {Basetype: "price", NotNull: true, Checks: []string{"CHECK (VALUE > 0)"}},
+		"code":           {Basetype: "character varying(8)"},
+	})
+	for _, tc := range []struct {
+		dt   string
+		want DBDomain
+	}{
+		{"price", DBDomain{Basetype: "numeric(10,2)", Checks: []string{"CHECK (VALUE >= 0)"}}},
+		{"positive_price", DBDomain{Basetype: "numeric(10,2)", NotNull: true,
+			Checks: []string{"CHECK (VALUE > 0)", "CHECK (VALUE >= 0)"}}},
+		{"integer", DBDomain{Basetype: "integer"}},
+	} {
+		if got := resolveDomain(tc.dt); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("resolveDomain(%s) = %+v, want %+v", tc.dt, got, tc.want)
+		}
+	}
+
+	columns := []DBColumns{
+		{Column: "amount", Datatype: "positive_price", IsNullable: true},
+		{Column: "amounts", Datatype: "positive_price[]", IsNullable: true},
+		{Column: "code", Datatype: "code", IsNullable: true},
+		{Column: "id", Datatype: "integer"},
+	}
+	resolveDomains("public.t", columns)
+	want := []DBColumns{
+		{Column: "amount", Datatype: "numeric(10,2)"},
+		{Column: "amounts", Datatype: "numeric(10,2)[]", IsNullable: true},
+		{Column: "code", Datatype: "character varying(8)", MaxLength: 8, IsNullable: true},
+		{Column: "id", Datatype: "integer"},
+	}
+	if !reflect.DeepEqual(columns, want) {
+		t.Errorf("resolveDomains = %+v, want %+v", columns, want)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Check the data types of the tables without writing anything, each data
+// type is built once and the tables that would be skipped or fail for it
+// are listed. The columns with a rule generator aren't built from their data
+// type, the nullable columns of an unsupported data type are loaded as NULLs
+func DryRun(tables []TableCollection) {
+	Info("Dry run, checking the data types of the tables, no data is loaded")
+	built := make(map[string]error)
+	var failing []string
+	fmt.Println("Tables:")
+	for _, t := range tables {
+		tab := GenerateTableName(t.Table, t.Schema)
+		var unsupported, nulls, failed []string
+		for _, c := range t.Columns {
+			if rule := columnRule(tab, c.Column); rule != nil && !IsStringEmpty(rule.generatorName()) {
+				continue
+			}
+			err, ok := built[c.Datatype]
+			if !ok {
+				_, err = BuildData(c.Datatype)
+				built[c.Datatype] = err
+			}
+			switch {
+			case err == nil:
+			case !strings.Contains(fmt.Sprint(err), "unsupported datatypes found"):
+				failed = append(failed, fmt.Sprintf("%s (%v)", c.Column, err))
+			case c.IsNullable:
+				nulls = append(nulls, c.Column+" "+c.Datatype)
+			default:
+				unsupported = append(unsupported, c.Column+" "+c.Datatype)
+			}
+		}
+
+		switch {
+		case len(unsupported) > 0:
+			addSkippedTable(tab, "unsupported "+strings.Join(unsupported, ", "))
+			fmt.Printf("  %s: SKIPPED, unsupported %s\n", tab, strings.Join(unsupported, ", "))
+		case len(failed) > 0:
+			failing = append(failing, tab)
+			fmt.Printf("  %s: FAILS, %s\n", tab, strings.Join(failed, ", "))
+		default:
+			fmt.Printf("  %s: %d rows of %d columns\n", tab, rowsToMock(tab), len(t.Columns))
+		}
+		if len(nulls) > 0 {
+			fmt.Printf("    loaded as NULLs: %s\n", strings.Join(nulls, ", "))
+		}
+	}
+
+	if len(skippedTab) > 0 {
+		Errorf("The dry run would skip these tables since their data types are not supported by %s: %s",
+			programName, strings.Join(skippedTab, ","))
+	}
+	if len(failing) > 0 {
+		Errorf("The dry run found these tables whose data fails to build, their load would fail: %s",
+			strings.Join(failing, ","))
+	}
+	switch {
+	case len(skippedTab) > 0 && len(failing) > 0:
+		os.Exit(exitSkippedAndFailed)
+	case len(skippedTab) > 0:
+		os.Exit(exitSkipped)
+	case len(failing) > 0:
+		os.Exit(exitFailed)
+	}
+	Infof("The dry run found no tables to skip or fail out of %d tables", len(tables))
+}
@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 var (
@@ -28,13 +29,20 @@ var (
 
 	// Geometry data types
 	geoDataTypekeywords = []string{"path", "polygon", "line", "lseg", "box", "circle", "point"}
+
+	// System data types that are generated on a best effort basis
+	bestEffortKeywords = []string{"xid", "cid"}
+	snapshotKeywords   = []string{"txid_snapshot", "pg_snapshot"}
+	bestEffortNoted    = make(map[string]bool)
+	bestEffortMutex    sync.Mutex
 )
 
 // Data Generator
 // It provided random data based on data types.
 func BuildData(dt string) (interface{}, error) {
-	var value interface{}
-	if StringHasPrefix(dt, intKeywords) { // Integer builder
+	if strings.HasSuffix(dt, "[][]") { // The multi dimensional arrays are not generated
+		return "", fmt.Errorf("unsupported datatypes found in array %v, only the one dimensional arrays are generated", dt)
+	} else if StringHasPrefix(dt, intKeywords) { // Integer builder
 		return buildInteger(dt)
 	} else if strings.HasPrefix(dt, "character") { // String builder
 		return buildCharacter(dt)
@@ -54,7 +62,7 @@ func BuildData(dt string) (interface{}, error) {
 		return buildText(dt)
 	} else if strings.HasPrefix(dt, "citext") { // Generate CiText text
 		return buildCiText(dt)
-	} else if strings.EqualFold(dt, "bytea") { // Generate Random bytea
+	} else if strings.HasPrefix(dt, "bytea") { // Generate Random bytea
 		return buildBytea(dt)
 	} else if StringHasPrefix(dt, floatKeywords) { // Generate Random float values
 		return buildFloat(dt)
@@ -76,14 +84,17 @@ func BuildData(dt string) (interface{}, error) {
 		return buildTsVector(dt)
 	} else if strings.HasPrefix(dt, "pg_lsn") { // Random Log Sequence number
 		return buildLseg(dt)
-	} else if strings.HasPrefix(dt, "txid_snapshot") { // Random transaction XID snapshot
+	} else if StringHasPrefix(dt, snapshotKeywords) { // Random transaction XID snapshot
 		return buildTxidSnapShot(dt)
 	} else if StringHasPrefix(dt, geoDataTypekeywords) { // Random GeoMetric data
 		return buildGeometry(dt)
+	} else if StringHasPrefix(dt, bestEffortKeywords) { // Random transaction / command ids
+		return buildBestEffort(dt)
+	} else if isExtensionType(dt) { // Random data of the extension types i.e postgis
+		return buildExtensionType(dt)
 	} else { // if these are not the defaults, the ony custom we allow is enum data type, check if its them
 		return buildEnumDatatypes(dt)
 	}
-	return value, nil
 }
 
 // Build Integer
@@ -109,6 +120,35 @@ func buildCharacter(dt string) (interface{}, error) {
 	return RandomString(l), nil
 }
 
+// Length of the character(n) and character varying(n) data types, 0 for the
+// rest and the arrays
+func declaredLength(dt string) int {
+	if !strings.HasPrefix(dt, "character") || !strings.HasSuffix(dt, ")") {
+		return 0
+	}
+	l, err := CharLen(dt)
+	if err != nil {
+		return 0
+	}
+	return l
+}
+
+// Cut the value to the length of the column, the character(n) values are
+// padded with spaces to exactly n characters the way postgres stores them
+func fitLength(c DBColumns, value string) string {
+	if value == nullValue {
+		return value
+	}
+	runes := []rune(value)
+	if len(runes) > c.MaxLength {
+		runes = runes[:c.MaxLength]
+	}
+	if !strings.HasPrefix(c.Datatype, "character varying") && len(runes) < c.MaxLength {
+		runes = append(runes, []rune(strings.Repeat(" ", c.MaxLength-len(runes)))...)
+	}
+	return string(runes)
+}
+
 // Date Builder
 func buildDate(dt string) (interface{}, error) {
 	isItArray, _ := isDataTypeAnArray(dt)
@@ -130,9 +170,15 @@ func buildTimeWithTz(dt string) (interface{}, error) {
 // Ip's builder
 func buildIps(dt string) (interface{}, error) {
 	isItArray, _ := isDataTypeAnArray(dt)
+	if isItArray && strings.HasPrefix(dt, "cidr") {
+		return ArrayGenerator("cidr", dt, 0, 0)
+	}
 	if isItArray {
 		return ArrayGenerator("IP", dt, 0, 0)
 	}
+	if strings.HasPrefix(dt, "cidr") {
+		return RandomCIDR(), nil
+	}
 	return RandomIP(), nil
 }
 
@@ -163,9 +209,16 @@ func buildCiText(dt string) (interface{}, error) {
 	return RandomCiText(), nil
 }
 
-// Bytea builder
+// Bytea builder, the hex format \x... of postgres. The COPY is CSV where the
+// backslash is not an escape, and the hex digits never hold the delimiter
+// or the quote, so the value goes through as is. The elements of the arrays
+// are quoted, so their backslash is escaped
 func buildBytea(dt string) (interface{}, error) {
-	return RandomBytea(1024), nil
+	isItArray, _ := isDataTypeAnArray(dt)
+	if isItArray {
+		return ArrayGenerator("bytea", dt, 0, 0)
+	}
+	return RandomByteaHex(), nil
 }
 
 // Float builder
@@ -179,13 +232,68 @@ func buildFloat(dt string) (interface{}, error) {
 
 // Numeric values with precision builder
 func buildNumeric(dt string) (interface{}, error) {
-	max, precision, err := findNumberPrecision(dt)
-	value := RandomFloat(1, max, precision)
-	isItArray, _ := isDataTypeAnArray(dt)
+	isItArray, t := isDataTypeAnArray(dt)
 	if isItArray {
-		return ArrayGenerator("numericFloat", dt, 0, max)
+		return ArrayGenerator("numericFloat", t, 0, 0)
+	}
+	return randomNumeric(t)
+}
+
+// Random numeric within the precision and scale of the data type, at most
+// precision - scale digits before the decimal point and exactly scale digits
+// after it, the negative scale rounds to the tens, hundreds ... The unbounded
+// numeric gets small values of 3 decimals. Half of the values are negative
+func randomNumeric(dt string) (interface{}, error) {
+	negative := r.Intn(2) == 0
+	precision, scale, ok := numericTypmod(dt)
+	if !ok {
+		max, precision, err := findNumberPrecision(dt)
+		value := TruncateFloat(RandomFloat(1, max, precision), max, precision)
+		if negative {
+			value = -value
+		}
+		return value, err
+	}
+
+	// The significant digits, from the left. The columns whose scale is
+	// above the precision only have the last precision digits of the scale
+	digits := precision
+	switch {
+	case scale > 0 && scale < precision:
+		digits = RandomInt(scale, precision+1)
+	case scale <= 0 && precision > 1:
+		digits = RandomInt(1, precision+1)
+	}
+	var b strings.Builder
+	b.WriteByte(byte('1' + r.Intn(9)))
+	for i := 1; i < digits; i++ {
+		b.WriteByte(byte('0' + r.Intn(10)))
+	}
+	value := b.String()
+	switch {
+	case scale < 0:
+		value += strings.Repeat("0", -scale)
+	case scale >= len(value):
+		value = "0." + strings.Repeat("0", scale-len(value)) + value
+	case scale > 0:
+		value = value[:len(value)-scale] + "." + value[len(value)-scale:]
+	}
+	if negative {
+		value = "-" + value
+	}
+	return value, nil
+}
+
+// Precision and scale of the numeric(p) and numeric(p,s) data types, false
+// for the unbounded numeric
+func numericTypmod(dt string) (int, int, bool) {
+	rs := regexp.MustCompile(`^numeric\((\d+)(?:,\s*(-?\d+))?\)$`).FindStringSubmatch(dt)
+	if len(rs) == 0 {
+		return 0, 0, false
 	}
-	return TruncateFloat(value, max, precision), err
+	precision, _ := strconv.Atoi(rs[1])
+	scale, _ := strconv.Atoi(rs[2]) // 0 when its not given
+	return precision, scale, true
 }
 
 // Bit builder
@@ -300,13 +408,18 @@ func buildTimeStamp(dt string) (interface{}, error) {
 		if isItArray {
 			return ArrayGenerator("timestamptz", dt, fromYear, toYear)
 		}
-		return RandomTimeStampTz(fromYear, toYear)
+		v, err := RandomTimeStampTz(fromYear, toYear)
+		return withOutputZone(v), err
 	} else if regexp.MustCompile(`timestamp\([0-6]\) without time zone`).MatchString(dt) ||
 		regexp.MustCompile(`timestamp\([0-6]\) with time zone`).MatchString(dt) { // time zone with precision
 		if isItArray {
 			return ArrayGenerator("timestamptzWithDecimals", dt, fromYear, toYear)
 		}
-		return RandomTimeStampTzWithDecimals(fromYear, toYear, findTimeStampDecimal(dt))
+		v, err := RandomTimeStampTzWithDecimals(fromYear, toYear, findTimeStampDecimal(dt))
+		if strings.HasSuffix(dt, "with time zone") {
+			v = withOutputZone(v)
+		}
+		return v, err
 	}
 	return "", nil
 }
@@ -359,13 +472,23 @@ func ArrayGenerator(dt, originalDt string, min, max int) (string, error) {
 			if err != nil {
 				return "", fmt.Errorf("error when generating array for datatype %s, err: %v", dt, err)
 			}
-			resultArray = append(resultArray, value)
+			resultArray = append(resultArray, arrayElement(dt, value))
 		}
 		resultArrayCollector = append(resultArrayCollector, strings.Join(resultArray, ","))
 	}
 	return fmt.Sprintf("{%s}", strings.Join(resultArrayCollector, ",")), nil
 }
 
+// Quote the element of the array literal, the values with a space, a comma
+// or a quote i.e tsvector, tsquery or text break the literal unquoted
+func arrayElement(dt, value string) string {
+	switch dt {
+	case "int", "float", "numericFloat":
+		return value
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
 // Send in appropriate random value based on data type
 func randomDataByDataTypeForArray(dt, originalDt string, min, max int) (string, error) {
 	if dt == "int" {
@@ -386,8 +509,7 @@ func randomDataByDataTypeForArray(dt, originalDt string, min, max int) (string,
 		value := RandomFloat(0, max, 3)
 		return fmt.Sprintf("%v", TruncateFloat(value, max, 3)), nil
 	} else if dt == "numericFloat" {
-		m, precision, err := FloatPrecision(dt)
-		value := RandomFloat(1, m, precision)
+		value, err := randomNumeric(originalDt)
 		return fmt.Sprintf("%v", value), err
 	} else if dt == "bit" {
 		return RandomBit(max), nil
@@ -405,10 +527,14 @@ func randomDataByDataTypeForArray(dt, originalDt string, min, max int) (string,
 		}
 	} else if dt == "IP" {
 		return RandomIP(), nil
+	} else if dt == "cidr" {
+		return RandomCIDR(), nil
 	} else if dt == "macaddr" {
 		return RandomMacAddress(), nil
 	} else if dt == "uuid" {
 		return RandomUUID(), nil
+	} else if dt == "bytea" {
+		return RandomByteaHex(), nil
 	} else if dt == "txid_snapshot" {
 		return RandomTXID(), nil
 	} else if dt == "pg_lsn" {
@@ -461,10 +587,37 @@ func JsonXmlArrayGenerator(dt string) string {
 	return fmt.Sprintf("{%s}", strings.Join(resultArray, ","))
 }
 
+// Best effort builder of the system data types, they are plausible unsigned
+// 32 bit values that doesn't mean anything to the database
+func buildBestEffort(dt string) (interface{}, error) {
+	bestEffortMutex.Lock()
+	if !bestEffortNoted[dt] {
+		Debugf("Data type %s is generated on a best effort basis as random unsigned 32 bit values", dt)
+		bestEffortNoted[dt] = true
+	}
+	bestEffortMutex.Unlock()
+
+	isItArray, _ := isDataTypeAnArray(dt)
+	if isItArray {
+		var values []string
+		for i := RandomInt(1, 6); i > 0; i-- {
+			values = append(values, strconv.FormatInt(randomUint32(), 10))
+		}
+		return fmt.Sprintf("{%s}", strings.Join(values, ",")), nil
+	}
+	return randomUint32(), nil
+}
+
+// Random unsigned 32 bit value, the first few ids are reserved by postgres
+func randomUint32() int64 {
+	return 3 + r.Int63n(1<<32-3)
+}
+
 // Enum datatypes
 func buildEnumDatatypes(dt string) (string, error) {
-	// Check if the data type is ENUM
-	enumOutput := checkEnumDatatype(dt)
+	// Check if the data type is ENUM, the arrays get a few labels
+	isItArray, t := isDataTypeAnArray(dt)
+	enumOutput := checkEnumDatatype(t)
 
 	// if there are none then pass in the error back to user
 	if len(enumOutput) <= 0 {
@@ -472,6 +625,14 @@ func buildEnumDatatypes(dt string) (string, error) {
 	}
 
 	// found some output, lets pick some random value
+	if isItArray {
+		var labels []string
+		for i := RandomInt(1, 4); i > 0; i-- {
+			label := enumOutput[RandomValueFromLength(len(enumOutput))].EnumValue
+			labels = append(labels, `"`+strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(label)+`"`)
+		}
+		return fmt.Sprintf("{%s}", strings.Join(labels, ",")), nil
+	}
 	n := RandomValueFromLength(len(enumOutput))
 	return enumOutput[n].EnumValue, nil
 }
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDeclaredLength(t *testing.T) {
+	for _, tc := range []struct {
+		dt   string
+		want int
+	}{
+		{"character varying(5)", 5},
+		{"character(3)", 3},
+		{"character varying", 0},
+		{"character varying(5)[]", 0},
+		{"text", 0},
+		{"numeric(10,2)", 0},
+	} {
+		if got := declaredLength(tc.dt); got != tc.want {
+			t.Errorf("declaredLength(%q) = %d, want %d", tc.dt, got, tc.want)
+		}
+	}
+}
+
+func TestFitLength(t *testing.T) {
+	for _, tc := range []struct {
+		dt    string
+		value string
+		want  string
+	}{
+		{"character varying(5)", "abcdefgh", "abcde"},
+		{"character varying(5)", "abc", "abc"},
+		{"character varying(5)", "héllo wörld", "héllo"},
+		{"character varying(3)", "日本語テキスト", "日本語"},
+		{"character(5)", "ab", "ab   "},
+		{"character(5)", "abcdefgh", "abcde"},
+		{"character(4)", "ñ", "ñ   "},
+		{"character varying(5)", nullValue, nullValue},
+		{"character(5)", nullValue, nullValue},
+	} {
+		c := DBColumns{Column: "c", Datatype: tc.dt, MaxLength: declaredLength(tc.dt)}
+		if got := fitLength(c, tc.value); got != tc.want {
+			t.Errorf("fitLength(%s, %q) = %q, want %q", tc.dt, tc.value, got, tc.want)
+		}
+	}
+}
+
+func TestRandomNumeric(t *testing.T) {
+	for _, tc := range []struct {
+		dt               string
+		precision, scale int
+	}{
+		{"numeric(5,0)", 5, 0},
+		{"numeric(5)", 5, 0},
+		{"numeric(7,2)", 7, 2},
+		{"numeric(3,5)", 3, 5},
+		{"numeric(4,-2)", 4, -2},
+	} {
+		var negative, positive int
+		for i := 0; i < 500; i++ {
+			v, err := randomNumeric(tc.dt)
+			if err != nil {
+				t.Fatalf("randomNumeric(%s): %v", tc.dt, err)
+			}
+			value := v.(string)
+			if strings.HasPrefix(value, "-") {
+				negative++
+			} else {
+				positive++
+			}
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				t.Fatalf("randomNumeric(%s) = %q is not a number", tc.dt, value)
+			}
+			if limit := math.Pow10(tc.precision - tc.scale); math.Abs(f) >= limit {
+				t.Errorf("randomNumeric(%s) = %s, want below %v", tc.dt, value, limit)
+			}
+			decimals := 0
+			if dot := strings.Index(value, "."); dot >= 0 {
+				decimals = len(value) - dot - 1
+			}
+			want := tc.scale
+			if want < 0 {
+				want = 0
+			}
+			if decimals != want {
+				t.Errorf("randomNumeric(%s) = %s, want %d decimals", tc.dt, value, want)
+			}
+			if tc.scale < 0 && !strings.HasSuffix(value, strings.Repeat("0", -tc.scale)) {
+				t.Errorf("randomNumeric(%s) = %s, want a multiple of %v", tc.dt, value, math.Pow10(-tc.scale))
+			}
+		}
+		if negative == 0 || positive == 0 {
+			t.Errorf("randomNumeric(%s) gave %d negative and %d positive values, want both", tc.dt, negative, positive)
+		}
+	}
+}
+
+// Elements of the one dimension array literal, unquoted and unescaped
+func parseArrayLiteral(t *testing.T, literal string) (elements []string, quoted []bool) {
+	if !strings.HasPrefix(literal, "{") || !strings.HasSuffix(literal, "}") {
+		t.Fatalf("array literal %q is not in braces", literal)
+	}
+	body := literal[1 : len(literal)-1]
+	for i := 0; i < len(body); i++ {
+		var element strings.Builder
+		isQuoted := body[i] == '"'
+		if isQuoted {
+			for i++; i < len(body) && body[i] != '"'; i++ {
+				if body[i] == '\\' {
+					i++
+				}
+				element.WriteByte(body[i])
+			}
+			if i++; i < len(body) && body[i] != ',' {
+				t.Fatalf("array literal %q has text after the quoted element %q", literal, element.String())
+			}
+		} else {
+			for ; i < len(body) && body[i] != ','; i++ {
+				if strings.ContainsRune(`"\{} `, rune(body[i])) {
+					t.Fatalf("array literal %q has %q in the unquoted element", literal, body[i])
+				}
+				element.WriteByte(body[i])
+			}
+		}
+		elements, quoted = append(elements, element.String()), append(quoted, isQuoted)
+	}
+	return elements, quoted
+}
+
+func TestArrayGenerator(t *testing.T) {
+	for _, tc := range []struct {
+		dt     string
+		quoted bool
+	}{
+		{"integer[]", false},
+		{"numeric(5,2)[]", false},
+		{"text[]", true},
+		{"character varying(10)[]", true},
+		{"citext[]", true},
+		{"uuid[]", true},
+		{"tsvector[]", true},
+		{"tsquery[]", true},
+		{"timestamp without time zone[]", true},
+		{"bytea[]", true},
+	} {
+		for i := 0; i < 20; i++ {
+			v, err := BuildData(tc.dt)
+			if err != nil {
+				t.Fatalf("BuildData(%s): %v", tc.dt, err)
+			}
+			elements, quoted := parseArrayLiteral(t, v.(string))
+			for j, e := range elements {
+				if e == "" || quoted[j] != tc.quoted {
+					t.Fatalf("BuildData(%s) = %q, element %q quoted %v, want quoted %v",
+						tc.dt, v, e, quoted[j], tc.quoted)
+				}
+			}
+		}
+	}
+	for _, tc := range []struct{ value, want string }{
+		{"a b", `"a b"`},
+		{`it's "x"`, `"it's \"x\""`},
+		{`c:\tmp`, `"c:\\tmp"`},
+		{"x,y", `"x,y"`},
+	} {
+		if got := arrayElement("text", tc.value); got != tc.want {
+			t.Errorf("arrayElement(text, %q) = %s, want %s", tc.value, got, tc.want)
+		}
+	}
+	if value, want := `\x0aff`, `"\\x0aff"`; arrayElement("bytea", value) != want {
+		t.Errorf("arrayElement(bytea, %s) = %s, want %s", value, arrayElement("bytea", value), want)
+	}
+	if got := arrayElement("int", "-5"); got != "-5" {
+		t.Errorf("arrayElement(int, -5) = %s, want -5", got)
+	}
+}
+
+// Check the inet, cidr or macaddr literal is one postgres reads back
+func checkNetworkLiteral(t *testing.T, dt, value string) {
+	switch dt {
+	case "inet":
+		if net.ParseIP(value) == nil {
+			t.Fatalf("inet %q does not parse", value)
+		}
+	case "cidr":
+		ip, network, err := net.ParseCIDR(value)
+		if err != nil {
+			t.Fatalf("cidr %q does not parse: %v", value, err)
+		}
+		if !ip.Equal(network.IP) {
+			t.Fatalf("cidr %q has bits set right of the mask", value)
+		}
+		if ones, bits := network.Mask.Size(); bits != 32 || ones < 8 || ones > 30 {
+			t.Fatalf("cidr %q prefix is /%d of %d bits, want /8 to /30 of an IPv4 address", value, ones, bits)
+		}
+	case "macaddr":
+		if mac, err := net.ParseMAC(value); err != nil || len(mac) != 6 {
+			t.Fatalf("macaddr %q does not parse as 6 bytes: %v", value, err)
+		}
+	}
+}
+
+func TestBuildNetworkTypes(t *testing.T) {
+	for _, dt := range []string{"inet", "cidr", "macaddr"} {
+		for i := 0; i < 200; i++ {
+			v, err := BuildData(dt)
+			if err != nil {
+				t.Fatalf("BuildData(%s): %v", dt, err)
+			}
+			checkNetworkLiteral(t, dt, v.(string))
+			if line := assertCopyRoundTrip(t, []string{"1", v.(string)}); line != "1"+delimiter+v.(string) {
+				t.Errorf("copyRow quoted the %s %q: %q", dt, v, line)
+			}
+
+			v, err = BuildData(dt + "[]")
+			if err != nil {
+				t.Fatalf("BuildData(%s[]): %v", dt, err)
+			}
+			elements, _ := parseArrayLiteral(t, v.(string))
+			for _, e := range elements {
+				checkNetworkLiteral(t, dt, e)
+			}
+			assertCopyRoundTrip(t, []string{"1", v.(string)})
+		}
+	}
+}
+
+func TestBuildEnumDatatypes(t *testing.T) {
+	labels := []string{"happy", "so so", `say "hi"`, `back\slash`, "a,b", "{braced}", ""}
+	var enum []EnumDataType
+	for _, l := range labels {
+		enum = append(enum, EnumDataType{EnumName: "mood", EnumValue: l})
+	}
+	enumLabels[`"My Schema".mood`] = enum
+	defer delete(enumLabels, `"My Schema".mood`)
+	isLabel := make(map[string]bool)
+	for _, l := range labels {
+		isLabel[l] = true
+	}
+	for i := 0; i < 100; i++ {
+		v, err := buildEnumDatatypes(`"My Schema".mood`)
+		if err != nil {
+			t.Fatalf("buildEnumDatatypes: %v", err)
+		}
+		if !isLabel[v] {
+			t.Fatalf("buildEnumDatatypes = %q, want one of the labels", v)
+		}
+
+		v, err = buildEnumDatatypes(`"My Schema".mood[]`)
+		if err != nil {
+			t.Fatalf("buildEnumDatatypes([]): %v", err)
+		}
+		elements, quoted := parseArrayLiteral(t, v)
+		for j, e := range elements {
+			if !isLabel[e] || !quoted[j] {
+				t.Fatalf("buildEnumDatatypes([]) = %q, element %q quoted %v, want a quoted label", v, e, quoted[j])
+			}
+		}
+		assertCopyRoundTrip(t, []string{"1", v})
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Labels of the enum of a column and their running total of the weights
+type weightedEnum struct {
+	Labels     []string
+	Cumulative []float64
+}
+
+var (
+	weightedEnums     = make(map[string]*weightedEnum)
+	weightedEnumMutex sync.Mutex
+)
+
+func init() {
+	registerGenerator("enum",
+		"Labels of the native enum with the percentage of the weighted labels (label_weights: {<label>: <percent>, ...}), "+
+			"the rest of the percentage is split evenly between the other labels",
+		buildWeightedEnum)
+}
+
+// Validate the options of the enum rule, the labels are checked against the
+// enum once the column is generated
+func validateEnumRule(c *ColumnRule) error {
+	var total float64
+	for label, w := range c.LabelWeights {
+		if w < 0 {
+			return fmt.Errorf("weight of the label \"%s\" cannot be negative, got %v", label, w)
+		}
+		total += w
+	}
+	if len(c.LabelWeights) > 0 && total <= 0 {
+		return fmt.Errorf("label_weights cannot all be 0")
+	}
+	return nil
+}
+
+// Enum generator, the labels of the enum are read and weighted on the first
+// value of the column
+func buildWeightedEnum(ctx *generatorContext) (interface{}, error) {
+	key := ruleKey(ctx.Table, ctx.Column.Column)
+	weightedEnumMutex.Lock()
+	e, ok := weightedEnums[key]
+	if !ok {
+		var err error
+		e, err = newWeightedEnum(ctx.Column.Datatype, ctx.Rule.LabelWeights)
+		if err != nil {
+			weightedEnumMutex.Unlock()
+			return "", err
+		}
+		weightedEnums[key] = e
+	}
+	weightedEnumMutex.Unlock()
+	return e.Labels[RandomWeightedIndex(e.Cumulative)], nil
+}
+
+// Weigh the labels of the enum, the weighted labels take their percentage
+// and the others share the rest evenly. When all the labels are weighted
+// the weights are relative to each other
+func newWeightedEnum(dt string, weights map[string]float64) (*weightedEnum, error) {
+	if isItArray, _ := isDataTypeAnArray(dt); isItArray {
+		return nil, fmt.Errorf("enum generator doesn't support the enum arrays, got %s", dt)
+	}
+	e := &weightedEnum{}
+	for _, v := range checkEnumDatatype(dt) {
+		e.Labels = append(e.Labels, v.EnumValue)
+	}
+	if len(e.Labels) == 0 {
+		return nil, fmt.Errorf("enum generator only supports the enum columns, got %s", dt)
+	}
+
+	var unknown []string
+	var weighted float64
+	for label, w := range weights {
+		if !StringContains(label, e.Labels) {
+			unknown = append(unknown, label)
+		}
+		weighted += w
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("labels %s of the label_weights are not labels of the enum %s (%s)",
+			strings.Join(unknown, ", "), dt, strings.Join(e.Labels, ", "))
+	}
+	rest := len(e.Labels) - len(weights)
+	if rest > 0 && weighted > 100 {
+		return nil, fmt.Errorf("label_weights add up to %v%%, more than 100%% leaves nothing for the other %d labels",
+			weighted, rest)
+	}
+
+	var w []float64
+	for _, label := range e.Labels {
+		if v, ok := weights[label]; ok {
+			w = append(w, v)
+		} else {
+			w = append(w, (100-weighted)/float64(rest))
+		}
+	}
+	e.Cumulative = CumulativeWeights(w)
+	return e, nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// Timestamp of the last event of each entity
+	lastEvents     = make(map[string]time.Time)
+	lastEventMutex sync.Mutex
+)
+
+func init() {
+	registerRowGenerator("event_time",
+		"Timestamps increasing per entity i.e the events of an aggregate (source_columns: [<entity key>, ...]), "+
+			"\"gap: <duration>\" between the events (default 1h) on a \"distribution: exponential|uniform|fixed\"",
+		buildEventTime)
+}
+
+// Validate the options of the event time rule
+func validateEventTimeRule(c *ColumnRule) error {
+	if len(c.SourceColumns) == 0 {
+		return fmt.Errorf("event_time generator needs the source_columns of the entity key")
+	}
+	for _, s := range c.SourceColumns {
+		if s == c.Column {
+			return fmt.Errorf("entity key of the column %s cannot include itself", c.Column)
+		}
+	}
+	if IsStringEmpty(c.Gap) {
+		c.Gap = "1h"
+	}
+	gap, err := time.ParseDuration(c.Gap)
+	if err != nil {
+		return fmt.Errorf("invalid gap \"%s\": %v", c.Gap, err)
+	}
+	if gap <= 0 {
+		return fmt.Errorf("gap should be greater than 0, got %s", c.Gap)
+	}
+	c.gap = gap
+	switch c.Distribution {
+	case "":
+		c.Distribution = "exponential"
+	case "exponential", "uniform", "fixed":
+	default:
+		return fmt.Errorf("event_time distribution can only be exponential, uniform or fixed, got \"%s\"", c.Distribution)
+	}
+	return nil
+}
+
+// Gap till the next event, exponential gaps are the arrivals of a poisson
+// process and the uniform gaps are within 0 and twice the gap
+func (c *ColumnRule) nextGap() time.Duration {
+	switch c.Distribution {
+	case "uniform":
+		return time.Duration(r.Float64() * 2 * float64(c.gap))
+	case "fixed":
+		return c.gap
+	}
+	return time.Duration(r.ExpFloat64() * float64(c.gap))
+}
+
+// Event time generator, the first event of an entity is within the last
+// year and each of the next events is a gap after the previous one
+func buildEventTime(ctx *generatorContext) (interface{}, error) {
+	dt := ctx.Column.Datatype
+	if !strings.HasPrefix(dt, "timestamp") || strings.HasSuffix(dt, "[]") {
+		return "", fmt.Errorf("event_time generator only supports the timestamp columns, got %s", dt)
+	}
+	var entity []string
+	for _, s := range ctx.Rule.SourceColumns {
+		v, ok := ctx.Row.Values[s]
+		if !ok {
+			return "", fmt.Errorf("source column %s is not a column of table %s or is not generated yet",
+				s, ctx.Table)
+		}
+		entity = append(entity, v)
+	}
+
+	// The smallest step the column can store, so the events never round
+	// to the same timestamp
+	precision := 6
+	if strings.HasPrefix(dt, "timestamp(") {
+		precision = findTimeStampDecimal(dt)
+	}
+	step := time.Duration(math.Pow10(9 - precision))
+
+	lastEventMutex.Lock()
+	defer lastEventMutex.Unlock()
+	key := ruleKey(ctx.Table, ctx.Column.Column) + "\x00" + strings.Join(entity, "\x00")
+	last, ok := lastEvents[key]
+	var next time.Time
+	if ok {
+		next = last.Add(ctx.Rule.nextGap()).Truncate(step)
+		if !next.After(last) {
+			next = last.Add(step)
+		}
+	} else {
+		first, err := RandomCalenderDateTime(-1, 0)
+		if err != nil {
+			return "", err
+		}
+		next = first.Truncate(step)
+	}
+	lastEvents[key] = next
+	return next.Format("2006-01-02 15:04:05.000000"), nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A dependency of a table on the table it refers to
+type planEdge struct {
+	Table, Column, Reftable, Refcolumn string
+	Source                             string // the foreign key constraint or the references rule
+}
+
+// Print the plan of the run without loading anything, the order of the
+// tables, the dependencies between them, the rows of each table and how
+// each column is generated
+func ExplainPlan(tables []TableCollection) {
+	Info("Explaining the mocking plan, no data is loaded")
+	position := make(map[string]int)
+	for i, t := range tables {
+		position[GenerateTableName(t.Table, t.Schema)] = i
+	}
+
+	fmt.Println("Processing order:")
+	for i, t := range tables {
+		tab := GenerateTableName(t.Table, t.Schema)
+		fmt.Printf("  %d. %s: %d rows\n", i+1, tab, rowsToMock(tab))
+	}
+
+	fmt.Println("Dependencies:")
+	edges := planEdges(tables, position)
+	if len(edges) == 0 {
+		fmt.Println("  none")
+	}
+	for _, e := range edges {
+		fmt.Printf("  %s.%s -> %s.%s (%s)", e.Table, e.Column, e.Reftable, e.Refcolumn, e.Source)
+		p, ok := position[e.Reftable]
+		switch {
+		case !ok:
+			fmt.Printf(", %s is not mocked", e.Reftable)
+		case p > position[e.Table] && e.Source == "references rule":
+			fmt.Printf(", WARNING %s is loaded after %s so its keys are not there yet", e.Reftable, e.Table)
+		case p > position[e.Table]:
+			fmt.Printf(", %s is loaded after %s, the keys are fixed once all the tables are loaded", e.Reftable, e.Table)
+		}
+		fmt.Println()
+	}
+
+	fmt.Println("Columns:")
+	for _, t := range tables {
+		tab := GenerateTableName(t.Table, t.Schema)
+		fmt.Printf("  %s\n", tab)
+		for _, c := range t.Columns {
+			plan := describeColumn(tab, c)
+			if isRowScoped(tab, c.Column) {
+				plan += ", built after the rest of the row"
+			}
+			if p := nullProbability(tab, c); p > 0 {
+				plan += fmt.Sprintf(", %.0f%% NULLs", p*100)
+			}
+			fmt.Printf("    %s %s: %s\n", c.Column, c.Datatype, plan)
+		}
+	}
+	if !cmdOptions.IgnoreConstraint && !isFileOutput() {
+		printConstraintRisks(tables)
+		fmt.Println("After loading: the primary, unique and foreign keys are fixed and the constraints recreated")
+	}
+}
+
+// The dependencies of the tables, from the foreign keys of the database
+// and the references of the rules file
+func planEdges(tables []TableCollection, position map[string]int) []planEdge {
+	var edges []planEdge
+	if !isFileOutput() && isPostgresBackend() {
+		for _, con := range GetPGConstraintDDL("f") {
+			if _, ok := position[con.Tablename]; !ok {
+				continue
+			}
+			fk := getForeignKeyColumns(constraint{con.Tablename, con.Constraintkey})
+			edges = append(edges, planEdge{fk.Table, fk.Column, qualifiedTableName(fk.Reftable),
+				fk.Refcolumn, "foreign key " + con.Constraintname})
+		}
+	}
+	for _, t := range tables {
+		tab := GenerateTableName(t.Table, t.Schema)
+		for _, c := range t.Columns {
+			rule := columnRule(tab, c.Column)
+			if rule == nil || IsStringEmpty(rule.References) {
+				continue
+			}
+			reftab, refcol, _ := parseReference(rule.References)
+			edges = append(edges, planEdge{tab, c.Column, reftab, refcol, "references rule"})
+		}
+	}
+	return edges
+}
+
+// The referenced table of the foreign key as "schema"."table", the tables
+// on the search path are printed without the schema
+func qualifiedTableName(tab string) string {
+	tab = strings.TrimSpace(tab)
+	if strings.HasPrefix(tab, "\"") && strings.Contains(tab, "\".\"") {
+		return tab
+	}
+	s := strings.SplitN(tab, ".", 2)
+	if len(s) == 1 {
+		return GenerateTableName(strings.Trim(s[0], "\""), "public")
+	}
+	return GenerateTableName(strings.Trim(s[1], "\""), strings.Trim(s[0], "\""))
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A data type added to the database by an extension, its builder gets the
+// data type as the database reports it i.e geometry(Point,4326)
+type extensionType struct {
+	Extension string
+	Build     func(dt string) (interface{}, error)
+}
+
+var extensionTypes = make(map[string]extensionType)
+
+// Register the data type of an extension, called from the init of the extension files
+func registerExtensionType(name, extension string, build func(dt string) (interface{}, error)) {
+	extensionTypes[name] = extensionType{Extension: extension, Build: build}
+}
+
+// The base name of the data type, without the schema the extension is
+// installed on, the type modifiers or the array brackets
+func extensionTypeName(dt string) string {
+	dt = strings.TrimSuffix(dt, "[]")
+	if i := strings.Index(dt, "("); i >= 0 {
+		dt = dt[:i]
+	}
+	if i := strings.LastIndex(dt, "."); i >= 0 {
+		dt = dt[i+1:]
+	}
+	return strings.Trim(dt, `"`)
+}
+
+// Is the data type registered by an extension
+func isExtensionType(dt string) bool {
+	_, ok := extensionTypes[extensionTypeName(dt)]
+	return ok
+}
+
+// Build the value of the extension data type, the arrays get a few values
+func buildExtensionType(dt string) (interface{}, error) {
+	e := extensionTypes[extensionTypeName(dt)]
+	isItArray, t := isDataTypeAnArray(dt)
+	if !isItArray {
+		return e.Build(dt)
+	}
+	var values []string
+	for i := RandomInt(1, 4); i > 0; i-- {
+		v, err := e.Build(t)
+		if err != nil {
+			return "", err
+		}
+		values = append(values, `"`+strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(fmt.Sprint(v))+`"`)
+	}
+	return fmt.Sprintf("{%s}", strings.Join(values, ",")), nil
+}
+
+// Names of the extension data types and their extension, for the supported types list
+func extensionTypeList() (string, string) {
+	var names, extensions []string
+	for n := range extensionTypes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if e := extensionTypes[n].Extension; !StringContains(e, extensions) {
+			extensions = append(extensions, e)
+		}
+	}
+	return strings.Join(names, ", "), "needs the " + strings.Join(extensions, ", ") + " extension"
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"github.com/icrowley/fake"
+	"strings"
+)
+
+var (
+	// Directories of the generated paths
+	filePathDirs = []string{"home", "users", "documents", "projects", "reports", "archive", "invoices",
+		"images", "shared", "data", "backup", "media", "uploads", "exports", "2019", "2020", "2021", "q1", "q4"}
+
+	// Extensions of the generated files, unless the rule has its own
+	filePathExtensions = []string{"pdf", "docx", "xlsx", "txt", "csv", "png", "jpg", "zip", "json", "log"}
+)
+
+func init() {
+	registerGenerator("file_path",
+		"Filesystem paths, \"os_style: posix|windows\" (default posix) and the allowed \"extensions: [pdf, ...]\"",
+		buildFilePath)
+	registerGenerator("file_name",
+		"File names with an extension, the allowed \"extensions: [pdf, ...]\"",
+		buildFileName)
+	registerNameHint(`^(.*_)?(file_?name|attachment_?name)$`, "file_name", 24)
+	registerNameHint(`^(.*_)?(file_?path|path|attachment|document_?path)$`, "file_path", 64)
+}
+
+// Validate the options of the file path rule
+func validateFilePathRule(c *ColumnRule) error {
+	switch c.OSStyle {
+	case "", "posix", "windows":
+	default:
+		return fmt.Errorf("unknown os_style \"%s\", it can be either posix or windows", c.OSStyle)
+	}
+	for _, e := range c.Extensions {
+		if IsStringEmpty(e) || strings.ContainsAny(e, `/\`) {
+			return fmt.Errorf("invalid extension \"%s\"", e)
+		}
+	}
+	return nil
+}
+
+// Random file name with an extension i.e "quarterly_report-42.pdf"
+func randomFileName(rule *ColumnRule) string {
+	extensions := filePathExtensions
+	if rule != nil && len(rule.Extensions) > 0 {
+		extensions = rule.Extensions
+	}
+	name := strings.ToLower(fake.Word())
+	if RandomInt(0, 2) == 0 {
+		name = fmt.Sprintf("%s_%s", name, strings.ToLower(fake.Word()))
+	}
+	return fmt.Sprintf("%s-%d.%s", name, RandomInt(1, 1000), strings.TrimPrefix(RandomPickerFromArray(extensions), "."))
+}
+
+// File name generator
+func buildFileName(ctx *generatorContext) (interface{}, error) {
+	return fitFilePath(ctx.Column.Datatype, "", nil, randomFileName(ctx.Rule))
+}
+
+// File path generator
+func buildFilePath(ctx *generatorContext) (interface{}, error) {
+	var dirs []string
+	for i := RandomInt(1, 5); i > 0; i-- {
+		dirs = append(dirs, RandomPickerFromArray(filePathDirs))
+	}
+	sep := "/"
+	if ctx.Rule != nil && ctx.Rule.OSStyle == "windows" {
+		sep = `\`
+	}
+	return fitFilePath(ctx.Column.Datatype, sep, dirs, randomFileName(ctx.Rule))
+}
+
+// Join the path, the directories are dropped until the path fits the
+// length of the character column
+func fitFilePath(dt, sep string, dirs []string, name string) (string, error) {
+	limit := -1
+	if strings.HasPrefix(dt, "character") {
+		l, err := CharLen(dt)
+		if err != nil {
+			return "", err
+		}
+		limit = l
+	}
+	for {
+		var path string
+		switch {
+		case IsStringEmpty(sep):
+			path = name
+		case sep == "/":
+			path = "/" + strings.Join(append(dirs, name), sep)
+		default:
+			path = `C:\` + strings.Join(append(dirs, name), sep)
+		}
+		if limit < 0 || len(path) <= limit {
+			return path, nil
+		}
+		if len(dirs) == 0 {
+			return "", fmt.Errorf("the column of length %d is too short for the path %s", limit, path)
+		}
+		dirs = dirs[1:]
+	}
+}
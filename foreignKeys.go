@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+)
+
+// Keys of a referenced (parent) table column, loaded once and shared by
+// all the columns referring to it
+type fkKeys struct {
+	Keys []string
+	zipf map[float64]*rand.Zipf
+}
+
+var (
+	fkKeyCache      = make(map[string]*fkKeys)
+	fkKeyCacheMutex sync.Mutex
+)
+
+func init() {
+	registerGenerator("foreign_key",
+		"Pick the keys from the referenced table (references: <schema.table.column>), "+
+			"uniformly or skewed with \"distribution: zipf\" and \"skew: <s>\"",
+		buildForeignKey)
+}
+
+// Split the schema.table.column reference into the table and the column
+func parseReference(ref string) (string, string, error) {
+	s := strings.Split(ref, ".")
+	switch len(s) {
+	case 2:
+		return GenerateTableName(s[0], "public"), s[1], nil
+	case 3:
+		return GenerateTableName(s[1], s[0]), s[2], nil
+	}
+	return "", "", fmt.Errorf("reference \"%s\" should be of the format <schema>.<table>.<column>", ref)
+}
+
+// Keys of the referenced column, the keys are read from the database
+// on the first request and are kept for the rest of the run
+func referencedKeys(tab, column string) (*fkKeys, error) {
+	fkKeyCacheMutex.Lock()
+	defer fkKeyCacheMutex.Unlock()
+
+	key := ruleKey(tab, column)
+	if k, ok := fkKeyCache[key]; ok {
+		return k, nil
+	}
+
+	// The keys frozen on the key snapshot are preferred, so the references
+	// stay valid against the data shared from the previous runs
+	keys := snapshotColumnKeys(tab, column)
+	if keys == nil {
+		keys = activeBackend.referencedKeys(tab, column)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("the referenced table %s has no rows to pick the keys of column %s from, "+
+			"load the referenced table first", tab, column)
+	}
+
+	// Shuffle the keys, so the most popular keys with a skewed distribution
+	// are not always the first rows of the referenced table
+	r.Shuffle(len(keys), func(i, j int) { keys[i], keys[j] = keys[j], keys[i] })
+	k := &fkKeys{Keys: keys, zipf: make(map[float64]*rand.Zipf)}
+	fkKeyCache[key] = k
+	return k, nil
+}
+
+// Add the keys captured while loading the referenced table, the references
+// pick from them instead of reading the keys back from the table
+func addReferencedKeys(tab, column string, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	fkKeyCacheMutex.Lock()
+	defer fkKeyCacheMutex.Unlock()
+
+	key := ruleKey(tab, column)
+	k, ok := fkKeyCache[key]
+	if !ok {
+		k = &fkKeys{}
+		fkKeyCache[key] = k
+	}
+	k.Keys = append(k.Keys, keys...)
+	r.Shuffle(len(k.Keys), func(i, j int) { k.Keys[i], k.Keys[j] = k.Keys[j], k.Keys[i] })
+	k.zipf = make(map[float64]*rand.Zipf) // the distributions depend on the number of keys
+}
+
+// Pick a key, on a zipfian distribution few keys are picked far more
+// often than the rest
+func (k *fkKeys) pick(distribution string, skew float64) string {
+	if distribution != "zipf" || len(k.Keys) == 1 {
+		return RandomPickerFromArray(k.Keys)
+	}
+	fkKeyCacheMutex.Lock()
+	defer fkKeyCacheMutex.Unlock()
+	z, ok := k.zipf[skew]
+	if !ok {
+		z = rand.NewZipf(r, skew, 1, uint64(len(k.Keys)-1))
+		k.zipf[skew] = z
+	}
+	return k.Keys[z.Uint64()]
+}
+
+// Foreign key generator
+func buildForeignKey(ctx *generatorContext) (interface{}, error) {
+	tab, column, err := parseReference(ctx.Rule.References)
+	if err != nil {
+		return "", err
+	}
+	keys, err := referencedKeys(tab, column)
+	if err != nil {
+		return "", err
+	}
+	return keys.pick(ctx.Rule.Distribution, ctx.Rule.Skew), nil
+}
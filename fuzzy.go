@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	// Earlier values of each text column, the near duplicates are made from them
+	fuzzyPools     = make(map[string][]string)
+	fuzzyPoolMutex sync.Mutex
+
+	// Values kept per column to pick the near duplicates from
+	fuzzyPoolSize = 1000
+
+	// Letters that are commonly mistaken for each other, i.e Smith and Smyth
+	fuzzyLookalikes = map[rune][]rune{
+		'a': {'e', 'o'}, 'e': {'a', 'i'}, 'i': {'y', 'e'}, 'o': {'u', 'a'}, 'u': {'o'},
+		'y': {'i'}, 'c': {'k', 's'}, 'k': {'c'}, 's': {'z', 'c'}, 'z': {'s'},
+		'm': {'n'}, 'n': {'m'}, 'f': {'v'}, 'v': {'f'}, 't': {'d'}, 'd': {'t'},
+	}
+)
+
+// Occasionally replace the text value by a near duplicate of an earlier
+// value of the column, i.e "John Smith" becomes "Jon Smith" or "John Smyth"
+// the same way the real data gets misspelled
+func fuzzyDuplicate(tab string, c DBColumns, value interface{}) (interface{}, error) {
+	if cmdOptions.FuzzyDuplicateRate <= 0 || !isTextDatatype(c.Datatype) {
+		return value, nil
+	}
+	fuzzyPoolMutex.Lock()
+	defer fuzzyPoolMutex.Unlock()
+	key := ruleKey(tab, c.Column)
+	pool := fuzzyPools[key]
+	if len(pool) > 0 && r.Float64() < cmdOptions.FuzzyDuplicateRate {
+		return fitText(c.Datatype, perturb(RandomPickerFromArray(pool), cmdOptions.FuzzyDuplicateStrength))
+	}
+	if len(pool) < fuzzyPoolSize {
+		fuzzyPools[key] = append(pool, fmt.Sprint(value))
+	} else {
+		pool[RandomValueFromLength(len(pool))] = fmt.Sprint(value)
+	}
+	return value, nil
+}
+
+// Make the given number of small edits to the text, each edit drops,
+// doubles, swaps or replaces a letter by a lookalike
+func perturb(s string, edits int) string {
+	text := []rune(s)
+	for e := 0; e < edits && len(text) > 1; e++ {
+		i := RandomValueFromLength(len(text))
+		switch RandomValueFromLength(4) {
+		case 0: // drop, i.e John to Jon
+			text = append(text[:i], text[i+1:]...)
+		case 1: // double, i.e Anna to Annna
+			text = append(text[:i+1], text[i:]...)
+		case 2: // swap with the next letter, i.e Smith to Smtih
+			if i == len(text)-1 {
+				i--
+			}
+			text[i], text[i+1] = text[i+1], text[i]
+		default: // lookalike, i.e Smith to Smyth
+			if l, ok := fuzzyLookalikes[text[i]]; ok {
+				text[i] = l[RandomValueFromLength(len(l))]
+			} else {
+				text = append(text[:i], text[i+1:]...)
+			}
+		}
+	}
+	return string(text)
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Details of the column a named generator is producing data for
+type generatorContext struct {
+	Table  string
+	Column DBColumns
+	Rule   *ColumnRule
+	Row    *rowContext
+}
+
+// The row being generated, shared by the generators of its columns
+type rowContext struct {
+	Values        map[string]string // columns of the row generated so far
+	personRecord  *person           // the person the row describes, created on the first request
+	snapshotIndex int               // key of the key snapshot used by the row, -1 until its picked
+}
+
+// New row context
+func newRowContext() *rowContext {
+	return &rowContext{Values: make(map[string]string), snapshotIndex: -1}
+}
+
+// A named generator, these are picked via the rules file
+type namedGenerator struct {
+	Description string
+	Build       func(ctx *generatorContext) (interface{}, error)
+	RowScoped   bool // derived from the rest of the row, so its built after them
+}
+
+var generators = make(map[string]namedGenerator)
+
+// Register a named generator, called from the init of the generator files
+func registerGenerator(name, description string, build func(ctx *generatorContext) (interface{}, error)) {
+	generators[name] = namedGenerator{Description: description, Build: build}
+}
+
+// Register a named generator that derives the value from the other columns
+// of the row, its built after the rest of the columns of the row
+func registerRowGenerator(name, description string, build func(ctx *generatorContext) (interface{}, error)) {
+	generators[name] = namedGenerator{Description: description, Build: build, RowScoped: true}
+}
+
+// Is the column generated from the rest of the row
+func isRowScoped(tab, column string) bool {
+	rule := columnRule(tab, column)
+	return rule != nil && generators[rule.generatorName()].RowScoped
+}
+
+// Names of all the registered generators
+func generatorNames() []string {
+	var names []string
+	for n := range generators {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Build the data for the column, the rules of the column get the
+// preference over the data type of the column and the value is fitted
+// to the length of the character columns
+func buildColumnData(tab string, c DBColumns, row *rowContext) (interface{}, error) {
+	if isUniqueColumn(tab, c.Column) {
+		return buildUniqueColumnData(tab, c, row, buildFittedData)
+	}
+	return buildFittedData(tab, c, row)
+}
+
+// Build the data for the column fitted to its length
+func buildFittedData(tab string, c DBColumns, row *rowContext) (interface{}, error) {
+	var value interface{}
+	var err error
+	if rule := columnRule(tab, c.Column); rule != nil && rule.MaxDistinct > 0 {
+		value, err = distinctValue(tab, c, row)
+	} else {
+		value, err = buildColumnValue(tab, c, row)
+	}
+	if err != nil || c.MaxLength == 0 {
+		return value, err
+	}
+	return fitLength(c, fmt.Sprintf("%v", value)), nil
+}
+
+// The value of the column before its fitted to the length of the column
+func buildColumnValue(tab string, c DBColumns, row *rowContext) (interface{}, error) {
+	if v, ok := snapshotKey(tab, c.Column, row); ok {
+		return v, nil
+	}
+	if v, ok, err := sequentialKeyValue(tab, c.Column); ok {
+		return v, err
+	}
+	if v, ok := discriminatorValue(tab, c); ok {
+		return fitText(c.Datatype, v)
+	}
+	if v, ok := coverageValue(tab, c); ok {
+		return v, nil
+	}
+	rule := columnRule(tab, c.Column)
+	var name string
+	if rule != nil {
+		name = rule.generatorName()
+	}
+	hinted := false
+	if IsStringEmpty(name) {
+		if b := partitionKeyBound(tab, c.Column); b != nil {
+			return b.build(c.Datatype)
+		}
+		name = hintedGenerator(c)
+		hinted = !IsStringEmpty(name)
+	}
+	if IsStringEmpty(name) {
+		if isAdversarialText(c.Datatype) {
+			return adversarialText(c.Datatype)
+		}
+		value, err := BuildData(c.Datatype)
+		if err != nil {
+			return value, err
+		}
+		return fuzzyDuplicate(tab, c, value)
+	}
+	g := generators[name]
+	value, err := g.Build(&generatorContext{Table: tab, Column: c, Rule: rule, Row: row})
+	if err != nil {
+		return "", fmt.Errorf("generator %s: %v", name, err)
+	}
+	if hinted { // the generators hinted by the column name are the names and the addresses
+		return fuzzyDuplicate(tab, c, value)
+	}
+	return value, nil
+}
+
+// How the data of the column is generated, its the same decisions as
+// buildColumnValue so keep them in sync
+func describeColumn(tab string, c DBColumns) string {
+	description := describeColumnSource(tab, c)
+	if rule := columnRule(tab, c.Column); rule != nil && rule.MaxDistinct > 0 {
+		description += fmt.Sprintf(", picked from a pool of %d distinct values", rule.MaxDistinct)
+	}
+	if isUniqueColumn(tab, c.Column) {
+		description += ", regenerated when repeated"
+	}
+	if rule := columnRule(tab, c.Column); rule != nil && rule.SumTarget != nil {
+		description += fmt.Sprintf(", adjusted to sum to %v", *rule.SumTarget)
+		if len(rule.GroupBy) > 0 {
+			description += " per " + strings.Join(rule.GroupBy, ", ")
+		}
+	}
+	return description
+}
+
+// Where the values of the column come from before any adjustment
+func describeColumnSource(tab string, c DBColumns) string {
+	var prefix string
+	if snapshotColumnKeys(tab, c.Column) != nil {
+		prefix = "keys of the key snapshot, then "
+	}
+	if isSequentialKey(tab, c.Column) {
+		return prefix + "counter after the highest existing key"
+	}
+	if v, ok := discriminatorValue(tab, c); ok {
+		return prefix + "discriminator " + v
+	}
+	if cmdOptions.MinCoverage > 0 && len(coverageValues(tab, c)) > 0 {
+		prefix += fmt.Sprintf("each value %d times, then ", cmdOptions.MinCoverage)
+	}
+	rule := columnRule(tab, c.Column)
+	if rule != nil && !IsStringEmpty(rule.generatorName()) {
+		return prefix + "rule generator " + rule.generatorName()
+	}
+	if b := partitionKeyBound(tab, c.Column); b != nil {
+		return prefix + fmt.Sprintf("within the %s partition bound", b.Strategy)
+	}
+	var suffix string
+	if isTextDatatype(c.Datatype) && cmdOptions.FuzzyDuplicateRate > 0 {
+		suffix = fmt.Sprintf(", %.0f%% near duplicates", cmdOptions.FuzzyDuplicateRate*100)
+	}
+	if name := hintedGenerator(c); !IsStringEmpty(name) {
+		return prefix + "generator " + name + " hinted by the column name" + suffix
+	}
+	if isTextDatatype(c.Datatype) && cmdOptions.AdversarialTextRate > 0 {
+		suffix = fmt.Sprintf(", %.0f%% adversarial", cmdOptions.AdversarialTextRate*100) + suffix
+	}
+	return prefix + "random " + c.Datatype + suffix
+}
+
+// A column name pattern that picks a named generator for the text
+// columns without a rule, i.e a column named "schedule" holds cron strings
+type nameHint struct {
+	Pattern   *regexp.Regexp
+	Generator string
+	MinLength int // the shorter character columns cannot hold the generated value
+}
+
+var nameHints []nameHint
+
+// Register a column name hint, called from the init of the generator files
+func registerNameHint(pattern, generator string, minLength int) {
+	nameHints = append(nameHints, nameHint{Pattern: regexp.MustCompile(pattern), Generator: generator, MinLength: minLength})
+}
+
+// The named generator hinted by the name of the column, empty if none
+func hintedGenerator(c DBColumns) string {
+	if !isTextDatatype(c.Datatype) {
+		return ""
+	}
+	for _, h := range nameHints {
+		if !h.Pattern.MatchString(strings.ToLower(c.Column)) {
+			continue
+		}
+		if strings.HasPrefix(c.Datatype, "character") {
+			if l, err := CharLen(c.Datatype); err != nil || l < h.MinLength {
+				continue
+			}
+		}
+		return h.Generator
+	}
+	return ""
+}
@@ -5,12 +5,15 @@ go 1.14
 require (
 	github.com/corpix/uarand v0.1.1 // indirect
 	github.com/go-pg/pg/v10 v10.9.1
+	github.com/go-sql-driver/mysql v1.5.0
 	github.com/google/uuid v1.2.0
 	github.com/icrowley/fake v0.0.0-20180203215853-4178557ae428
 	github.com/k0kubun/go-ansi v0.0.0-20180517002512-3bf9e2903213
+	github.com/mattn/go-sqlite3 v1.14.6
 	github.com/schollz/progressbar/v3 v3.8.0
 	github.com/sirupsen/logrus v1.8.1
 	github.com/spf13/cobra v1.1.3
 	github.com/spf13/viper v1.7.1
+	github.com/xitongsys/parquet-go v1.6.2
 	gopkg.in/yaml.v2 v2.4.0
 )
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// Hash algorithms of the hash generator
+var hashAlgorithms = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+func init() {
+	registerRowGenerator("hash",
+		"Hash of the other columns of the row (source_columns: [<column>, ...]), "+
+			"\"algorithm: md5|sha1|sha256|sha512\" (default md5)",
+		buildHash)
+}
+
+// Validate the options of the hash rule
+func validateHashRule(c *ColumnRule) error {
+	if len(c.SourceColumns) == 0 {
+		return fmt.Errorf("hash generator needs the source_columns to hash")
+	}
+	for _, s := range c.SourceColumns {
+		if s == c.Column {
+			return fmt.Errorf("hash of the column %s cannot include itself", c.Column)
+		}
+	}
+	if IsStringEmpty(c.Algorithm) {
+		c.Algorithm = "md5"
+	}
+	if _, ok := hashAlgorithms[c.Algorithm]; !ok {
+		return fmt.Errorf("unknown hash algorithm \"%s\"", c.Algorithm)
+	}
+	return nil
+}
+
+// Hash generator, the values of the source columns are concatenated and
+// the NULLs are taken as empty, the same as md5(concat(a, b)) in postgres
+func buildHash(ctx *generatorContext) (interface{}, error) {
+	h := hashAlgorithms[ctx.Rule.Algorithm]()
+	for _, s := range ctx.Rule.SourceColumns {
+		v, ok := ctx.Row.Values[s]
+		if !ok {
+			return "", fmt.Errorf("source column %s is not a column of table %s or is not generated yet",
+				s, ctx.Table)
+		}
+		if v != nullValue {
+			h.Write([]byte(v))
+		}
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+	if strings.HasPrefix(ctx.Column.Datatype, "bytea") {
+		return `\x` + sum, nil
+	}
+	return sum, nil
+}
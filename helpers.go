@@ -19,8 +19,24 @@ func TimeNow() string {
 	return time.Now().Format("20060102150405")
 }
 
-// Create a database connection
-func ConnectDB() *pg.DB {
+// A database connection, either a pool of its own or the shared
+// transaction of --single-transaction
+type dbConnection interface {
+	pg.DBI
+	Close() error
+}
+
+// Create a database connection, within the single transaction
+// all the statements share its connection
+func ConnectDB() dbConnection {
+	if singleTx != nil {
+		return singleTx
+	}
+	return openDB()
+}
+
+// Open a connection pool to the database
+func openDB() *pg.DB {
 	if !IsStringEmpty(cmdOptions.Uri) {
 		opt, err := pg.ParseURL(cmdOptions.Uri)
 		if err != nil {
@@ -159,6 +175,12 @@ func YesOrNoConfirmation() string {
 	return ""
 }
 
+// Is the database error due to the lack of privileges
+func isPermissionDenied(err error) bool {
+	pgErr, ok := err.(pg.Error)
+	return ok && pgErr.Field('C') == "42501" // insufficient_privilege
+}
+
 // Ignore these errors, else error out
 func IgnoreError(e string, ignoreMsg string, failureMsg string) {
 	if !strings.HasSuffix(e, ignoreMsg) {
@@ -210,7 +232,6 @@ func ColExtractor(conkey, regExp string) (string, error) {
 	} else {
 		return "", fmt.Errorf("unable to extract the columns from the constraint key")
 	}
-	return "", nil
 }
 
 // Trim brackets at the start and at the end
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Histogram of values and its frequency, exported from a real data set.
+// A categorical histogram is a list of "value,frequency" lines and
+// a bucketed numeric histogram is a list of "lower,upper,frequency" lines
+type histogram struct {
+	Values     []string
+	Buckets    [][2]float64
+	Cumulative []float64
+	Decimals   int
+}
+
+func init() {
+	registerGenerator("histogram",
+		"Sample values according to the value/frequency histogram file (histogram: <path>)",
+		buildHistogram)
+}
+
+// Read the histogram file
+func readHistogram(filename string) (*histogram, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading histogram: %v", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	h := &histogram{}
+	var weights []float64
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading histogram %s: %v", filename, err)
+		}
+
+		// The last field is always the frequency
+		frequency, err := strconv.ParseFloat(strings.TrimSpace(record[len(record)-1]), 64)
+		if err != nil || frequency < 0 {
+			return nil, fmt.Errorf("histogram %s line %d: invalid frequency \"%s\"",
+				filename, line, record[len(record)-1])
+		}
+
+		switch {
+		case len(record) == 2 && len(h.Buckets) == 0: // categorical
+			h.Values = append(h.Values, record[0])
+		case len(record) == 3 && len(h.Values) == 0: // numeric bucket
+			bucket, decimals, err := parseHistogramBucket(record[0], record[1])
+			if err != nil {
+				return nil, fmt.Errorf("histogram %s line %d: %v", filename, line, err)
+			}
+			h.Buckets = append(h.Buckets, bucket)
+			if decimals > h.Decimals {
+				h.Decimals = decimals
+			}
+		default:
+			return nil, fmt.Errorf("histogram %s line %d: expected either \"value,frequency\" "+
+				"or \"lower,upper,frequency\" on all lines", filename, line)
+		}
+		weights = append(weights, frequency)
+	}
+
+	if len(weights) == 0 {
+		return nil, fmt.Errorf("histogram %s has no values", filename)
+	}
+	h.Cumulative = CumulativeWeights(weights)
+	if h.Cumulative[len(h.Cumulative)-1] <= 0 {
+		return nil, fmt.Errorf("histogram %s has all the frequencies set to zero", filename)
+	}
+	return h, nil
+}
+
+// Parse the lower and upper bound of numeric bucket
+func parseHistogramBucket(lower, upper string) ([2]float64, int, error) {
+	var bucket [2]float64
+	var decimals int
+	for i, s := range []string{lower, upper} {
+		s = strings.TrimSpace(s)
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return bucket, 0, fmt.Errorf("invalid bucket bound \"%s\"", s)
+		}
+		bucket[i] = v
+		if dot := strings.Index(s, "."); dot >= 0 && len(s)-dot-1 > decimals {
+			decimals = len(s) - dot - 1
+		}
+	}
+	if bucket[0] > bucket[1] {
+		return bucket, 0, fmt.Errorf("bucket lower bound %v is greater than upper bound %v", bucket[0], bucket[1])
+	}
+	return bucket, decimals, nil
+}
+
+// Sample a value according to the histogram
+func buildHistogram(ctx *generatorContext) (interface{}, error) {
+	h := ctx.Rule.histogram
+	n := RandomWeightedIndex(h.Cumulative)
+	if len(h.Values) > 0 {
+		return h.Values[n], nil
+	}
+
+	// Pick a value within the numeric bucket
+	lower, upper := h.Buckets[n][0], h.Buckets[n][1]
+	value := lower + r.Float64()*(upper-lower)
+	if StringHasPrefix(ctx.Column.Datatype, intKeywords) {
+		return int(math.Floor(value)), nil
+	}
+	return strconv.FormatFloat(value, 'f', h.Decimals, 64), nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"strings"
+)
+
+// Parents of the tables on the classic inheritance (INHERITS) hierarchies
+var inheritParents = make(map[string][]string)
+
+// Pick the tables of the inheritance hierarchies to load, the rows of the
+// children are also returned by the queries on the parent so loading both
+// shows the parent with more rows than asked for. With "parent" only the
+// tables at the top are loaded and with "children" only the bottom ones
+func applyInheritance(tables []DBTables) []DBTables {
+	if GreenplumOrPostgres != "postgres" {
+		return tables // the greenplum partitions are inherited tables too
+	}
+	inheritParents = make(map[string][]string)
+	hasChildren := make(map[string]bool)
+	for _, i := range GetInheritance() {
+		inheritParents[i.Child] = append(inheritParents[i.Child], i.Parent)
+		hasChildren[i.Parent] = true
+	}
+	if len(inheritParents) == 0 {
+		return tables
+	}
+
+	selected := make(map[string]bool)
+	for _, t := range tables {
+		selected[GenerateTableName(t.Table, t.Schema)] = true
+	}
+	var result, skipped []string
+	var kept []DBTables
+	for _, t := range tables {
+		tab := GenerateTableName(t.Table, t.Schema)
+		skip := false
+		switch cmdOptions.Inheritance {
+		case "parent":
+			for _, p := range inheritParents[tab] {
+				skip = skip || selected[p]
+			}
+		case "children":
+			skip = hasChildren[tab]
+		}
+		if skip {
+			skipped = append(skipped, tab)
+			continue
+		}
+		if hasChildren[tab] || len(inheritParents[tab]) > 0 {
+			result = append(result, tab)
+		}
+		kept = append(kept, t)
+	}
+
+	if len(skipped) > 0 {
+		Infof("Skipping the tables %s of the inheritance hierarchies, since --inheritance is \"%s\"",
+			strings.Join(skipped, ","), cmdOptions.Inheritance)
+	}
+	if cmdOptions.Inheritance == "all" && len(result) > 0 {
+		Warnf("Tables %s are part of inheritance hierarchies, the rows of the children also show on the parent, "+
+			"use \"--inheritance parent\" or \"--inheritance children\" to load only one level",
+			strings.Join(result, ","))
+	}
+	return kept
+}
+
+// The value of the discriminator column of the table, its the name of
+// the table the row is loaded to. The discriminator is set on the rules of
+// the table or of any of its parents
+func discriminatorValue(tab string, c DBColumns) (string, bool) {
+	seen := make(map[string]bool)
+	pending := []string{tab}
+	for len(pending) > 0 {
+		t := pending[0]
+		pending = pending[1:]
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		if rules, ok := tableRules[t]; ok && !IsStringEmpty(rules.Discriminator) && rules.Discriminator == c.Column {
+			return tableNameOnly(tab), true
+		}
+		pending = append(pending, inheritParents[t]...)
+	}
+	return "", false
+}
+
+// The table without the schema i.e "public"."orders" is orders
+func tableNameOnly(tab string) string {
+	s := strings.SplitN(tab, `"."`, 2)
+	return strings.Trim(s[len(s)-1], `"`)
+}
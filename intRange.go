@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Storage range of the integer data types
+var intStorageRanges = map[string][2]int64{
+	"smallint": {math.MinInt16, math.MaxInt16},
+	"integer":  {math.MinInt32, math.MaxInt32},
+	"bigint":   {math.MinInt64, math.MaxInt64},
+	"oid":      {0, math.MaxUint32},
+}
+
+func init() {
+	registerGenerator("int_range",
+		"Integers between the bounds of the rule i.e an age (min: <n>, max: <n>), the bound that is not set is "+
+			"the one of the data type, set by the min or max when there is no generator",
+		buildIntRange)
+}
+
+// Validate the bounds of the int_range rule
+func validateIntRangeRule(c *ColumnRule) error {
+	if c.Min != nil && c.Max != nil && *c.Min > *c.Max {
+		return fmt.Errorf("min %d is greater than max %d", *c.Min, *c.Max)
+	}
+	return nil
+}
+
+// Check the bounds of the int_range rules of the table fit the data types
+// of their columns, before anything is loaded
+func checkIntRanges(tab string, columns []DBColumns) {
+	for _, c := range columns {
+		rule := columnRule(tab, c.Column)
+		if rule == nil || rule.generatorName() != "int_range" {
+			continue
+		}
+		if _, err := intRangeBounds(rule, c.Datatype); err != nil {
+			Fatalf("Invalid rule for the column %s of table %s in the rules file %s, err: %v",
+				c.Column, tab, cmdOptions.Rules, err)
+		}
+	}
+}
+
+// The bounds of the rule on the data type, the ones that are not set are the
+// bounds of the data type
+func intRangeBounds(rule *ColumnRule, dt string) ([2]int64, error) {
+	bounds, ok := intStorageRanges[dt]
+	if !ok {
+		return bounds, fmt.Errorf("min and max are only supported on the smallint, integer, bigint and oid "+
+			"columns, got %s", dt)
+	}
+	storage := bounds
+	if rule.Min != nil {
+		bounds[0] = *rule.Min
+	}
+	if rule.Max != nil {
+		bounds[1] = *rule.Max
+	}
+	if bounds[0] < storage[0] || bounds[1] > storage[1] || bounds[0] > bounds[1] {
+		return bounds, fmt.Errorf("min %d and max %d should be within the %d to %d of %s",
+			bounds[0], bounds[1], storage[0], storage[1], dt)
+	}
+	return bounds, nil
+}
+
+// Integer range generator, the values are uniform between the bounds
+// including both of them
+func buildIntRange(ctx *generatorContext) (interface{}, error) {
+	bounds, err := intRangeBounds(ctx.Rule, ctx.Column.Datatype)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(randomInt64Between(bounds[0], bounds[1]), 10), nil
+}
+
+// Uniform integer between lo and hi including both of them, the span is
+// counted on uint64 so the whole bigint range doesn't overflow
+func randomInt64Between(lo, hi int64) int64 {
+	span := uint64(hi) - uint64(lo) // wraps around to the right count
+	offset := r.Uint64()
+	if span < math.MaxUint64 {
+		offset %= span + 1
+	}
+	return lo + int64(offset)
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// A table listed on the interactive screen
+type interactiveTable struct {
+	DBTables
+	Selected bool
+	Rows     int
+}
+
+// Interactive mode, list the tables discovered and allow the user to pick
+// the tables and the number of rows for each of them before loading
+func InteractiveTableSelector(tables []DBTables) []DBTables {
+	Debugf("Starting the interactive table selector for %d tables", len(tables))
+	var list []interactiveTable
+	for _, t := range tables {
+		// With --top-up-to the count is the target the table is topped up to
+		rows := cmdOptions.Rows
+		if cmdOptions.TopUpTo > 0 {
+			rows = cmdOptions.TopUpTo
+		}
+		if n, ok := rowsPerTable[GenerateTableName(t.Table, t.Schema)]; ok {
+			rows = n
+		}
+		list = append(list, interactiveTable{t, true, rows})
+	}
+
+	// Start the new scanner to get the user input
+	input := bufio.NewScanner(os.Stdin)
+	printInteractiveTables(list)
+	for input.Scan() {
+		fields := strings.Fields(strings.ToLower(input.Text()))
+		if len(fields) == 0 {
+			printInteractiveTables(list)
+			continue
+		}
+		switch fields[0] {
+		case "run", "r":
+			return confirmInteractiveSelection(list)
+		case "quit", "q":
+			Info("Canceling as per user request...")
+			os.Exit(0)
+		case "all", "none":
+			for i := range list {
+				list[i].Selected = fields[0] == "all"
+			}
+		case "rows":
+			if err := setInteractiveRows(list, fields[1:]); err != nil {
+				fmt.Printf("Invalid Choice: %v, try again.\n", err)
+				continue
+			}
+		default: // toggle the tables by their number
+			if err := toggleInteractiveTables(list, fields); err != nil {
+				fmt.Printf("Invalid Choice: %v, try again.\n", err)
+				continue
+			}
+		}
+		printInteractiveTables(list)
+	}
+
+	return confirmInteractiveSelection(list)
+}
+
+// Print the list of tables with its checkbox and row count
+func printInteractiveTables(list []interactiveTable) {
+	fmt.Println()
+	fmt.Printf("%5s  %-4s %10s  %s\n", "#", "Mock", "Rows", "Table")
+	for i, t := range list {
+		checkbox := "[ ]"
+		if t.Selected {
+			checkbox = "[x]"
+		}
+		fmt.Printf("%5d  %-4s %10d  %s\n", i+1, checkbox, t.Rows, GenerateTableName(t.Table, t.Schema))
+	}
+	fmt.Println()
+	fmt.Println("Commands: <n> [<n>...] or <n>-<m> toggle tables, all, none, " +
+		"rows <n|n-m|all> <count> change the rows, run to start loading, quit to cancel")
+	fmt.Print("> ")
+}
+
+// Toggle the tables whose numbers are provided
+func toggleInteractiveTables(list []interactiveTable, fields []string) error {
+	for _, f := range fields {
+		from, to, err := interactiveRange(f, len(list))
+		if err != nil {
+			return err
+		}
+		for i := from; i <= to; i++ {
+			list[i].Selected = !list[i].Selected
+		}
+	}
+	return nil
+}
+
+// Change the row count of the tables whose numbers are provided
+func setInteractiveRows(list []interactiveTable, fields []string) error {
+	if len(fields) != 2 {
+		return fmt.Errorf("usage is rows <n|n-m|all> <count>")
+	}
+	rows, err := strconv.Atoi(fields[1])
+	if err != nil || rows < 1 {
+		return fmt.Errorf("row count should be a number greater than 0")
+	}
+	from, to := 0, len(list)-1
+	if fields[0] != "all" {
+		from, to, err = interactiveRange(fields[0], len(list))
+		if err != nil {
+			return err
+		}
+	}
+	for i := from; i <= to; i++ {
+		list[i].Rows = rows
+	}
+	return nil
+}
+
+// Convert "n" or "n-m" to the index range of the list
+func interactiveRange(s string, max int) (int, int, error) {
+	bounds := strings.SplitN(s, "-", 2)
+	from, err := strconv.Atoi(bounds[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("unknown command or table number \"%s\"", s)
+	}
+	to := from
+	if len(bounds) == 2 {
+		to, err = strconv.Atoi(bounds[1])
+		if err != nil {
+			return 0, 0, fmt.Errorf("unknown table range \"%s\"", s)
+		}
+	}
+	if from < 1 || to > max || from > to {
+		return 0, 0, fmt.Errorf("table number \"%s\" is out of range 1-%d", s, max)
+	}
+	return from - 1, to - 1, nil
+}
+
+// Collect the tables the user has selected and register their row counts
+func confirmInteractiveSelection(list []interactiveTable) []DBTables {
+	var tables []DBTables
+	for _, t := range list {
+		if t.Selected {
+			tables = append(tables, t.DBTables)
+			rowsPerTable[GenerateTableName(t.Table, t.Schema)] = t.Rows
+		}
+	}
+	Infof("Selected %d out of %d tables to mock", len(tables), len(list))
+	return tables
+}
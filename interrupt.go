@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+)
+
+// Exit code of a run stopped by SIGINT or SIGTERM, the shells use 128 + SIGINT
+const exitInterrupted = 130
+
+// Set once the run is interrupted, for the summary at the end
+var runInterrupted int32
+
+// Context of the load, its cancelled by the first SIGINT or SIGTERM. The
+// tables that are not started yet are skipped, the ones in flight are
+// finished (rolled back with --transactional) and the constraints already
+// removed are restored. A second signal stops the program right away
+func interruptContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		s := <-signals
+		signal.Stop(signals)
+		atomic.StoreInt32(&runInterrupted, 1)
+		Warnf("Received %v, no new table is started, the tables in flight are finished and the removed "+
+			"constraints restored; interrupt again to stop right away", s)
+		cancel()
+	}()
+	return ctx
+}
+
+// Was the run interrupted
+func isInterrupted() bool {
+	return atomic.LoadInt32(&runInterrupted) == 1
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"sync"
+)
+
+// Version of the key snapshot file, bump it when the layout changes
+const keySnapshotVersion = 1
+
+// The key snapshot file, it freezes the primary keys of the tables so the
+// reruns generate the same keys and the data referring to them stays valid
+type keySnapshot struct {
+	Version int                `json:"version"`
+	Tables  []keySnapshotTable `json:"tables"`
+}
+
+// Primary keys of a table, each key has a value for each of the columns
+type keySnapshotTable struct {
+	Schema  string     `json:"schema"`
+	Table   string     `json:"table"`
+	Columns []string   `json:"columns"`
+	Keys    [][]string `json:"keys"`
+
+	// Next key to hand out to the rows of the table
+	next int
+}
+
+var (
+	snapshotTables = make(map[string]*keySnapshotTable)
+	snapshotMutex  sync.Mutex
+
+	// Primary key columns of the table in the database, replaced by the tests
+	primaryKeyColumns = GetPrimaryKeyColumns
+)
+
+// Read the key snapshot file, the tables on the snapshot use its keys
+// instead of generating new ones
+func LoadKeySnapshot() {
+	Infof("Loading the primary keys from the snapshot file: %s", cmdOptions.ImportKeys)
+	content, err := ioutil.ReadFile(cmdOptions.ImportKeys)
+	if err != nil {
+		Fatalf("Error reading the key snapshot file %s, err: %v", cmdOptions.ImportKeys, err)
+	}
+
+	var snapshot keySnapshot
+	err = json.Unmarshal(content, &snapshot)
+	if err != nil {
+		Fatalf("Error when parsing the key snapshot file %s, err: %v", cmdOptions.ImportKeys, err)
+	}
+	if snapshot.Version != keySnapshotVersion {
+		Fatalf("Unsupported version %d of the key snapshot file %s, expected %d",
+			snapshot.Version, cmdOptions.ImportKeys, keySnapshotVersion)
+	}
+
+	for i := range snapshot.Tables {
+		t := &snapshot.Tables[i]
+		tab := GenerateTableName(t.Table, t.Schema)
+		for _, k := range t.Keys {
+			if len(k) != len(t.Columns) {
+				Fatalf("Invalid key %v of table %s in the key snapshot file %s, expected the values of %d columns",
+					k, tab, cmdOptions.ImportKeys, len(t.Columns))
+			}
+		}
+		snapshotTables[tab] = t
+	}
+	Debugf("Loaded the primary keys of %d tables", len(snapshotTables))
+}
+
+// The key of the snapshot for the column of the row, false if the table is
+// not on the snapshot or all its keys are used. The row picks its key on the
+// first key column, so the columns of a composite key come from the same key
+func snapshotKey(tab, column string, row *rowContext) (string, bool) {
+	t, ok := snapshotTables[tab]
+	if !ok {
+		return "", false
+	}
+	col := -1
+	for i, c := range t.Columns {
+		if c == column {
+			col = i
+		}
+	}
+	if col < 0 {
+		return "", false
+	}
+	if row.snapshotIndex < 0 {
+		snapshotMutex.Lock()
+		row.snapshotIndex = t.next
+		t.next++
+		if t.next == len(t.Keys)+1 {
+			Warnf("Table %s has more rows than the %d keys on the key snapshot, the rest of the rows "+
+				"get new keys", tab, len(t.Keys))
+		}
+		snapshotMutex.Unlock()
+	}
+	if row.snapshotIndex >= len(t.Keys) {
+		return "", false
+	}
+	return t.Keys[row.snapshotIndex][col], true
+}
+
+// Values of the column on the key snapshot, nil if the table is not on it
+func snapshotColumnKeys(tab, column string) []string {
+	t, ok := snapshotTables[tab]
+	if !ok {
+		return nil
+	}
+	for i, c := range t.Columns {
+		if c != column {
+			continue
+		}
+		keys := make([]string, len(t.Keys))
+		for j, k := range t.Keys {
+			keys[j] = k[i]
+		}
+		return keys
+	}
+	return nil
+}
+
+// Warn about the tables that the key snapshot cannot cover before the load
+// starts, the tables without a primary key are loaded as usual but their
+// rows cannot be frozen or taken from the snapshot
+func checkSnapshotKeys(tables []TableCollection) {
+	for _, t := range tables {
+		tab := GenerateTableName(t.Table, t.Schema)
+		if !IsStringEmpty(cmdOptions.ExportKeys) && len(primaryKeyColumns(tab)) == 0 {
+			Warnf("Table %s has no primary key, its loaded but left out of the key snapshot (--export-keys)", tab)
+		}
+		s, ok := snapshotTables[tab]
+		if !ok {
+			continue
+		}
+		for _, c := range s.Columns {
+			if !isGeneratedColumn(t, c) {
+				Warnf("Key column %s of table %s on the key snapshot (--import-keys) is not generated i.e its "+
+					"a serial or the table changed, the keys of the snapshot are not used for it", c, tab)
+			}
+		}
+	}
+}
+
+// Is the column one of the generated columns of the table
+func isGeneratedColumn(t TableCollection, column string) bool {
+	for _, c := range t.Columns {
+		if c.Column == column {
+			return true
+		}
+	}
+	return false
+}
+
+// Write the primary keys of the mocked tables to the key snapshot file, the
+// tables and the keys are sorted so the same data always gives the same file
+func ExportKeySnapshot(tables []TableCollection) {
+	Infof("Saving the primary keys of the tables to the snapshot file: %s", cmdOptions.ExportKeys)
+	snapshot := keySnapshot{Version: keySnapshotVersion}
+	for _, t := range tables {
+		tab := GenerateTableName(t.Table, t.Schema)
+		columns := primaryKeyColumns(tab)
+		if len(columns) == 0 {
+			Debugf("Table %s has no primary key, its not saved on the key snapshot", tab)
+			continue
+		}
+		st := keySnapshotTable{Schema: t.Schema, Table: t.Table, Columns: columns, Keys: [][]string{}}
+		for _, k := range GetPrimaryKeys(tab, columns) {
+			var key []string
+			if err := json.Unmarshal([]byte(k), &key); err != nil {
+				Fatalf("Error when reading the primary key %s of table %s, err: %v", k, tab, err)
+			}
+			st.Keys = append(st.Keys, key)
+		}
+		snapshot.Tables = append(snapshot.Tables, st)
+	}
+	sort.Slice(snapshot.Tables, func(i, j int) bool {
+		a, b := snapshot.Tables[i], snapshot.Tables[j]
+		return fmt.Sprintf("%s.%s", a.Schema, a.Table) < fmt.Sprintf("%s.%s", b.Schema, b.Table)
+	})
+
+	content, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		Fatalf("Error when encoding the key snapshot, err: %v", err)
+	}
+	err = ioutil.WriteFile(cmdOptions.ExportKeys, append(content, '\n'), 0644)
+	if err != nil {
+		Fatalf("Error when writing the key snapshot file %s, err: %v", cmdOptions.ExportKeys, err)
+	}
+	Infof("Saved the primary keys of %d tables to the key snapshot", len(snapshot.Tables))
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// Stub the primary key columns of the tables in the database
+func testPrimaryKeys(t *testing.T, keys map[string][]string) {
+	lookup := primaryKeyColumns
+	primaryKeyColumns = func(tab string) []string { return keys[tab] }
+	t.Cleanup(func() { primaryKeyColumns = lookup })
+}
+
+// The table name as the log writes it, with its quotes escaped
+func logged(tab string) string {
+	return strings.Replace(tab, `"`, `\"`, -1)
+}
+
+func TestCheckSnapshotKeys(t *testing.T) {
+	defer func(export string) { cmdOptions.ExportKeys = export }(cmdOptions.ExportKeys)
+	orders, events, users := GenerateTableName("orders", "public"), GenerateTableName("events", "public"),
+		GenerateTableName("users", "public")
+	testPrimaryKeys(t, map[string][]string{orders: {"id"}, users: {"id"}})
+	tables := []TableCollection{
+		{DBTables{Schema: "public", Table: "orders"}, []DBColumns{{Column: "id"}, {Column: "total"}}},
+		{DBTables{Schema: "public", Table: "events"}, []DBColumns{{Column: "name"}}},
+		{DBTables{Schema: "public", Table: "users"}, []DBColumns{{Column: "email"}}}, // id is a serial
+	}
+	snapshotTables[orders] = &keySnapshotTable{Schema: "public", Table: "orders", Columns: []string{"id"}}
+	snapshotTables[users] = &keySnapshotTable{Schema: "public", Table: "users", Columns: []string{"id"}}
+	defer delete(snapshotTables, orders)
+	defer delete(snapshotTables, users)
+
+	// Without --export-keys the tables without a key are not warned about
+	cmdOptions.ExportKeys = ""
+	log := testLog(t)
+	checkSnapshotKeys(tables)
+	if strings.Contains(log.String(), "has no primary key") {
+		t.Errorf("warned about the tables without a primary key without --export-keys:\n%s", log)
+	}
+	if !strings.Contains(log.String(), "Key column id of table "+logged(users)) {
+		t.Errorf("no warning about the serial key column of %s:\n%s", users, log)
+	}
+	if strings.Contains(log.String(), "of table "+logged(orders)) {
+		t.Errorf("warned about the generated key column of %s:\n%s", orders, log)
+	}
+
+	cmdOptions.ExportKeys = "keys.json"
+	log.Reset()
+	checkSnapshotKeys(tables)
+	if got := strings.Count(log.String(), "has no primary key"); got != 1 ||
+		!strings.Contains(log.String(), "Table "+logged(events)+" has no primary key") {
+		t.Errorf("want a single warning about %s having no primary key:\n%s", events, log)
+	}
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-pg/pg/v10"
+	"strings"
+)
+
+// Marker of a NULL value on the generated rows, postgres text
+// cannot hold the NUL character so it never collides with real data
+const nullValue = "\x00NULL\x00"
+
+// Destination of the mocked rows of a table, either the database or a file
+type rowWriter interface {
+	Write(data []string) error
+	Close() error
+}
+
+// A destination that buffers the rows, the rows are reported to the
+// function as each batch is flushed
+type batchedWriter interface {
+	rowWriter
+	Flushed(f func(rows int))
+}
+
+// A destination whose rows can be thrown away when the load of the table
+// fails, instead of being kept by the close
+type abortableWriter interface {
+	rowWriter
+	Abort()
+}
+
+// Close the destination after a failure, the rows are rolled back when
+// the destination can do that
+func abortWriter(w rowWriter) {
+	if a, ok := w.(abortableWriter); ok {
+		a.Abort()
+		return
+	}
+	w.Close()
+}
+
+// Are we writing the mocked rows to files instead of the database
+func isFileOutput() bool {
+	return !IsStringEmpty(cmdOptions.OutputParquet) || !IsStringEmpty(cmdOptions.OutputDir) ||
+		!IsStringEmpty(cmdOptions.OutputSQL)
+}
+
+// Pick the destination of the mocked rows of the table
+func newRowWriter(t TableCollection, tab string, col []string) (rowWriter, error) {
+	if isFileOutput() && cmdOptions.Shards > 1 {
+		return newShardWriter(t, tab, col)
+	}
+	if !IsStringEmpty(cmdOptions.OutputParquet) {
+		return newParquetWriter(t, "")
+	}
+	if !IsStringEmpty(cmdOptions.OutputDir) {
+		return newCSVWriter(t, col, "")
+	}
+	if !IsStringEmpty(cmdOptions.OutputSQL) {
+		return newSQLWriter(t, tab, col, "")
+	}
+	return activeBackend.newWriter(t, tab, col)
+}
+
+// Load the rows to postgres with COPY, or with the INSERT of --insert-returning
+// when the table has a column to return
+func newPostgresWriter(t TableCollection, tab string, col []string) (rowWriter, error) {
+	if cmdOptions.InsertReturning {
+		if returning := returningColumn(t, tab); !IsStringEmpty(returning) {
+			w := newInsertWriter(tab, col, returning)
+			if cmdOptions.Transactional {
+				return w, beginTableTransaction(w.copyWriter)
+			}
+			return w, nil
+		}
+	}
+	w := newCopyWriter(tab, col)
+	if cmdOptions.Transactional {
+		return w, beginTableTransaction(w)
+	}
+	return w, nil
+}
+
+// Load the rows to the database table using COPY, the rows are buffered
+// and copied in batches of --batch-size rows
+type copyWriter struct {
+	tab     string
+	col     []string
+	db      dbConnection
+	batch   [][]string
+	flushed func(rows int)
+
+	// Row level security is turned off within the transaction of each COPY,
+	// since a transaction pooler doesn't keep the session settings
+	rowSecurityOffLocal bool
+}
+
+// Open the database connection for the COPY
+func newCopyWriter(tab string, col []string) *copyWriter {
+	w := &copyWriter{tab: tab, col: col, db: takeConnection()}
+	if rowSecurityBypass[tab] {
+		if isTransactionPooler() {
+			w.rowSecurityOffLocal = true
+		} else {
+			disableRowSecurity(w.db)
+		}
+	}
+	return w
+}
+
+// Buffer the row and copy the batch once its full
+func (w *copyWriter) Write(data []string) error {
+	w.batch = append(w.batch, data)
+	if len(w.batch) < cmdOptions.BatchSize {
+		return nil
+	}
+	return w.flush()
+}
+
+// Report the flushed rows to the function
+func (w *copyWriter) Flushed(f func(rows int)) {
+	w.flushed = f
+}
+
+// Copy the buffered rows to the table, the batch is dropped when the
+// copy failed so its not copied again on the close
+func (w *copyWriter) flush() error {
+	if len(w.batch) == 0 {
+		return nil
+	}
+	defer func() { w.batch = w.batch[:0] }()
+	if !w.rowSecurityOffLocal {
+		if err := CopyData(w.tab, w.col, w.batch, w.db); err != nil {
+			return err
+		}
+	} else {
+		err := w.db.RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+			if _, err := tx.Exec("SET LOCAL row_security = off"); err != nil {
+				return fmt.Errorf("turning off row_security: %v", err)
+			}
+			return CopyData(w.tab, w.col, w.batch, tx)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if w.flushed != nil {
+		w.flushed(len(w.batch))
+	}
+	return nil
+}
+
+// Copy the last partial batch and close the database connection
+func (w *copyWriter) Close() error {
+	if err := w.flush(); err != nil {
+		w.Abort()
+		return err
+	}
+	if err := w.db.Close(); err != nil {
+		return fmt.Errorf("closing the database connection: %v", err)
+	}
+	return nil
+}
+
+// Drop the buffered rows and close the database connection, the rows
+// already copied are rolled back with --transactional
+func (w *copyWriter) Abort() {
+	w.batch = w.batch[:0]
+	if tx, ok := w.db.(*tableTransaction); ok {
+		tx.rollback()
+		return
+	}
+	w.db.Close()
+}
+
+// Load the rows one by one with INSERT ... RETURNING, its slower than the
+// COPY but it captures the keys the database assigns i.e the serials, so
+// the tables referring to them pick from the keys of this run
+type insertWriter struct {
+	*copyWriter
+	query     string
+	returning string
+	keys      []string
+}
+
+// The database assigned column of the table that a references rule points
+// to, empty if the table can be loaded with COPY
+func returningColumn(t TableCollection, tab string) string {
+	for _, rule := range columnRules {
+		if IsStringEmpty(rule.References) {
+			continue
+		}
+		reftab, refcol, err := parseReference(rule.References)
+		if err != nil || reftab != tab || isGeneratedColumn(t, refcol) {
+			continue
+		}
+		return refcol
+	}
+	return ""
+}
+
+// Open the database connection for the INSERT
+func newInsertWriter(tab string, col []string, returning string) *insertWriter {
+	params := strings.TrimSuffix(strings.Repeat("?, ", len(col)), ", ")
+	query := fmt.Sprintf(`INSERT INTO %s ("%s") VALUES (%s) RETURNING "%s"::text`,
+		tab, strings.Join(col, "\",\""), params, returning)
+	Debugf("Loading the table %s with INSERT to capture the keys of the column %s", tab, returning)
+	return &insertWriter{copyWriter: newCopyWriter(tab, col), query: query, returning: returning}
+}
+
+// Insert the row and keep its key
+func (w *insertWriter) Write(data []string) error {
+	params := make([]interface{}, len(data))
+	for i, d := range data {
+		if d != nullValue {
+			params[i] = d
+		}
+	}
+	var key string
+	insert := func(db pg.DBI) error {
+		_, err := db.QueryOne(pg.Scan(&key), w.query, params...)
+		return err
+	}
+	var err error
+	if w.rowSecurityOffLocal {
+		err = w.db.RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+			if _, err := tx.Exec("SET LOCAL row_security = off"); err != nil {
+				return fmt.Errorf("turning off row_security: %v", err)
+			}
+			return insert(tx)
+		})
+	} else {
+		err = insert(w.db)
+	}
+	if err != nil {
+		return fmt.Errorf("inserting the row: %v%s", err, rowSecurityHint(err))
+	}
+	w.keys = append(w.keys, key)
+	if w.flushed != nil {
+		w.flushed(1)
+	}
+	return nil
+}
+
+// Hand the captured keys to the references and close the connection
+func (w *insertWriter) Close() error {
+	addReferencedKeys(w.tab, w.returning, w.keys)
+	return w.copyWriter.Close()
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Version of the manifest file, bump it when the layout changes
+const manifestVersion = 1
+
+// The manifest of the files written by --output-dir or --output-parquet,
+// it describes the format of each file so the bulk loaders don't guess
+type manifest struct {
+	Version   int            `json:"version"`
+	Generated string         `json:"generated"`
+	Files     []manifestFile `json:"files"`
+}
+
+// A file of the manifest, the csv options are left out for parquet
+type manifestFile struct {
+	File        string   `json:"file"`
+	Schema      string   `json:"schema"`
+	Table       string   `json:"table"`
+	Format      string   `json:"format"`
+	Columns     []string `json:"columns"`
+	Rows        int      `json:"rows"`
+	Header      bool     `json:"header,omitempty"`
+	Delimiter   string   `json:"delimiter,omitempty"`
+	Quote       string   `json:"quote,omitempty"`
+	Null        string   `json:"null,omitempty"`
+	Compression string   `json:"compression,omitempty"`
+	Copy        string   `json:"copy,omitempty"` // the COPY to load the csv file with
+}
+
+var (
+	manifestFiles []manifestFile
+	manifestMutex sync.Mutex
+)
+
+// Keep the file for the manifest, called once the file is complete
+func addManifestFile(f manifestFile) {
+	if IsStringEmpty(cmdOptions.Manifest) {
+		return
+	}
+	if f.Format == "csv" {
+		f.Header, f.Delimiter, f.Quote, f.Null = true, delimiter, "\x01", `\N`
+		if cmdOptions.Compress != "none" {
+			f.Compression = cmdOptions.Compress
+		}
+		f.Copy = fmt.Sprintf(`COPY %s("%s") FROM STDIN WITH CSV HEADER DELIMITER '%s' QUOTE e'\x01' NULL E'\\N'`,
+			GenerateTableName(f.Table, f.Schema), strings.Join(f.Columns, `","`), delimiter)
+	}
+	manifestMutex.Lock()
+	defer manifestMutex.Unlock()
+	manifestFiles = append(manifestFiles, f)
+}
+
+// Write the manifest of the files, sorted by the file name
+func WriteManifest() {
+	Infof("Writing the manifest of the output files to: %s", cmdOptions.Manifest)
+	m := manifest{Version: manifestVersion, Files: manifestFiles,
+		Generated: fmt.Sprintf("%s %s on %s", programName, programVersion, ExecutionTimestamp)}
+	if m.Files == nil {
+		m.Files = []manifestFile{}
+	}
+	sort.Slice(m.Files, func(i, j int) bool {
+		return m.Files[i].File < m.Files[j].File
+	})
+
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		Fatalf("Error when encoding the manifest, err: %v", err)
+	}
+	err = ioutil.WriteFile(cmdOptions.Manifest, append(content, '\n'), 0644)
+	if err != nil {
+		Fatalf("Error when writing the manifest %s, err: %v", cmdOptions.Manifest, err)
+	}
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"math"
+	"strings"
+)
+
+func init() {
+	registerBackend("mysql", mysqlBackend{})
+}
+
+// The MySQL backend, the schemas are the databases of the server
+type mysqlBackend struct{}
+
+// Open the connection pool from the --uri DSN i.e user:pass@tcp(host:3306)/db
+// or the connection flags, the foreign key checks are off on each session
+func (mysqlBackend) open() (*sql.DB, error) {
+	cfg := mysql.NewConfig()
+	if !IsStringEmpty(cmdOptions.Uri) {
+		var err error
+		if cfg, err = mysql.ParseDSN(cmdOptions.Uri); err != nil {
+			return nil, fmt.Errorf("invalid --uri, expected user:pass@tcp(host:port)/database: %v", err)
+		}
+	} else {
+		port := cmdOptions.Port
+		if port == 0 {
+			port = 3306
+		}
+		host := cmdOptions.Hostname
+		if IsStringEmpty(host) {
+			host = "localhost"
+		}
+		cfg.User, cfg.Passwd, cfg.DBName = cmdOptions.Username, cmdOptions.Password, cmdOptions.Database
+		if IsStringEmpty(cfg.User) {
+			cfg.User = "root"
+		}
+		cfg.Net, cfg.Addr = "tcp", fmt.Sprintf("%s:%d", host, port)
+	}
+	if cfg.Params == nil {
+		cfg.Params = make(map[string]string)
+	}
+	cfg.Params["foreign_key_checks"] = "0"
+	return sql.Open("mysql", cfg.FormatDSN())
+}
+
+func (mysqlBackend) versionQuery() string {
+	return "SELECT VERSION()"
+}
+
+// The database of the connection
+func (mysqlBackend) defaultSchema(db *sql.DB) (string, error) {
+	var schema sql.NullString
+	if err := db.QueryRow("SELECT DATABASE()").Scan(&schema); err != nil {
+		return "", err
+	}
+	if !schema.Valid {
+		return "", fmt.Errorf("the connection has no database, set it with --database or on the --uri")
+	}
+	return schema.String, nil
+}
+
+// Base tables of the database, of the database of the connection when the
+// schema is empty
+func (b mysqlBackend) tables(db *sql.DB, schema string) ([]DBTables, error) {
+	if IsStringEmpty(schema) {
+		var err error
+		if schema, err = b.defaultSchema(db); err != nil {
+			return nil, err
+		}
+	}
+	rows, err := db.Query(`SELECT table_schema, table_name FROM information_schema.tables
+WHERE table_type = 'BASE TABLE' AND table_schema = ? ORDER BY table_name`, schema)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var tables []DBTables
+	for rows.Next() {
+		var t DBTables
+		if err := rows.Scan(&t.Schema, &t.Table); err != nil {
+			return nil, err
+		}
+		tables = append(tables, t)
+	}
+	return tables, rows.Err()
+}
+
+// Columns of the table, the generated columns are left out
+func (mysqlBackend) columns(db *sql.DB, t DBTables) ([]DBColumns, error) {
+	rows, err := db.Query(`SELECT column_name, data_type, column_type, is_nullable = 'YES', extra, column_default,
+       COALESCE(character_maximum_length, 0), COALESCE(numeric_precision, 0), COALESCE(numeric_scale, 0)
+FROM information_schema.columns
+WHERE table_schema = ? AND table_name = ? ORDER BY ordinal_position`, t.Schema, t.Table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	tab := GenerateTableName(t.Table, t.Schema)
+	var columns []DBColumns
+	for rows.Next() {
+		var c DBColumns
+		var dataType, columnType, extra string
+		var dflt sql.NullString
+		var length int64
+		var precision, scale int
+		if err := rows.Scan(&c.Column, &dataType, &columnType, &c.IsNullable, &extra, &dflt, &length,
+			&precision, &scale); err != nil {
+			return nil, err
+		}
+		c.Sequence = dflt.String // the default, the same as on postgres
+		extra = strings.ToLower(extra)
+		if strings.Contains(extra, "virtual generated") || strings.Contains(extra, "stored generated") {
+			continue
+		}
+		if strings.Contains(extra, "auto_increment") {
+			c.Sequence = autoIncrement
+		}
+		c.Datatype = mysqlDatatype(tab, c.Column, strings.ToLower(dataType), strings.ToLower(columnType), length,
+			precision, scale)
+		c.MaxLength = declaredLength(c.Datatype)
+		columns = append(columns, c)
+	}
+	return columns, rows.Err()
+}
+
+// Storage range of the signed MySQL integers, the year is unsigned
+var mysqlIntRanges = map[string][2]int64{
+	"tinyint":   {math.MinInt8, math.MaxInt8},
+	"smallint":  {math.MinInt16, math.MaxInt16},
+	"mediumint": {-1 << 23, 1<<23 - 1},
+	"int":       {math.MinInt32, math.MaxInt32},
+	"integer":   {math.MinInt32, math.MaxInt32},
+	"bigint":    {math.MinInt64, math.MaxInt64},
+	"year":      {1901, 2155},
+}
+
+// The postgres data type the MySQL column is generated as. The integers are
+// the smallest postgres integer that holds them, bounded with an int_range
+// rule to the range of the MySQL one unless the column has a rule of its
+// own. The enum and set labels are cached for the enum builder, the data
+// types that have no postgres equivalent i.e bit or geometry are left
+// unsupported
+func mysqlDatatype(tab, column, dataType, columnType string, length int64, precision, scale int) string {
+	switch dataType {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint", "year":
+		bounds := mysqlIntRanges[dataType]
+		if strings.Contains(columnType, "unsigned") {
+			bounds[0], bounds[1] = 0, bounds[1]*2+1
+			if bounds[1] < 0 { // the unsigned bigint is generated up to the signed one
+				bounds[1] = math.MaxInt64
+			}
+		}
+		if columnType == "tinyint(1)" { // the booleans
+			bounds = [2]int64{0, 1}
+		}
+		dt := "bigint"
+		for _, t := range []string{"integer", "smallint"} {
+			if bounds[0] >= intStorageRanges[t][0] && bounds[1] <= intStorageRanges[t][1] {
+				dt = t
+			}
+		}
+		if bounds != intStorageRanges[dt] && columnRule(tab, column) == nil {
+			min, max := bounds[0], bounds[1]
+			columnRules[ruleKey(tab, column)] = &ColumnRule{Column: column, Min: &min, Max: &max}
+		}
+		return dt
+	case "decimal", "numeric":
+		return fmt.Sprintf("numeric(%d,%d)", precision, scale)
+	case "float":
+		return "real"
+	case "double", "real":
+		return "double precision"
+	case "char", "binary":
+		return fmt.Sprintf("character(%d)", length)
+	case "varchar", "varbinary":
+		return fmt.Sprintf("character varying(%d)", length)
+	case "tinytext", "tinyblob":
+		return "character varying(255)"
+	case "text", "mediumtext", "longtext", "blob", "mediumblob", "longblob":
+		return "text"
+	case "date":
+		return "date"
+	case "datetime", "timestamp":
+		if fsp := strings.TrimPrefix(columnType, dataType); fsp != "" && fsp != "(0)" {
+			return fmt.Sprintf("timestamp%s without time zone", fsp)
+		}
+		return "timestamp without time zone"
+	case "time":
+		return "time without time zone"
+	case "json":
+		return "json"
+	case "enum", "set":
+		var labels []EnumDataType
+		for _, l := range mysqlLabels(columnType) {
+			labels = append(labels, EnumDataType{EnumName: columnType, EnumValue: l})
+		}
+		enumMutex.Lock()
+		enumLabels[columnType] = labels
+		enumMutex.Unlock()
+		return columnType
+	}
+	return "mysql " + columnType
+}
+
+// Labels of the enum('a','b') or set('a','b') column type, the quotes
+// of the labels are doubled
+func mysqlLabels(columnType string) []string {
+	var labels []string
+	var b strings.Builder
+	quoted := false
+	s := columnType[strings.Index(columnType, "(")+1:]
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\'' && quoted && i+1 < len(s) && s[i+1] == '\'':
+			b.WriteByte('\'')
+			i++
+		case s[i] == '\'' && quoted:
+			labels = append(labels, b.String())
+			b.Reset()
+			quoted = false
+		case s[i] == '\'':
+			quoted = true
+		case quoted:
+			b.WriteByte(s[i])
+		}
+	}
+	return labels
+}
+
+// Columns of the unique indexes, the primary key included, of one column
+func (mysqlBackend) singleColumnKeys(db *sql.DB, t DBTables) ([]string, error) {
+	rows, err := db.Query(`SELECT MIN(column_name) FROM information_schema.statistics
+WHERE table_schema = ? AND table_name = ? AND non_unique = 0
+GROUP BY index_name HAVING COUNT(*) = 1`, t.Schema, t.Table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var keys []string
+	for rows.Next() {
+		var k string
+		if err := rows.Scan(&k); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+func (mysqlBackend) quote(name string) string {
+	return "`" + strings.Replace(name, "`", "``", -1) + "`"
+}
+
+func (mysqlBackend) insertDefaults(tab string) string {
+	return fmt.Sprintf("INSERT INTO %s () VALUES ()", backendTableName(tab))
+}
+
+func (mysqlBackend) maxParams() int {
+	return 65535
+}
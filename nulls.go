@@ -0,0 +1,91 @@
+package main
+
+import (
+	"sync"
+)
+
+var (
+	// Rows sampled to find the fraction of NULLs on the existing data
+	nullSampleSize = 10000
+
+	// Fraction of NULLs on the existing data of the nullable columns
+	sampledNullFractions = make(map[string]float64)
+
+	// Nullable columns loaded with NULLs, since their data type is not supported
+	nullFallbacks     = make(map[string]bool)
+	nullFallbackMutex sync.Mutex
+)
+
+// Derive the probability of NULLs on the nullable columns from the existing
+// rows of the table, the tables without any rows use the flat percentage
+func SampleNullFractions(tables []TableCollection) {
+	Info("Extracting the fraction of NULLs on the existing data of the tables")
+	bar := StartProgressBar("Sampling the fraction of NULLs from tables", len(tables))
+	for _, t := range tables {
+		tab := GenerateTableName(t.Table, t.Schema)
+		var columns []string
+		for _, c := range t.Columns {
+			if c.IsNullable {
+				columns = append(columns, c.Column)
+			}
+		}
+		if len(columns) > 0 {
+			total, fractions := GetNullFractions(tab, columns)
+			if total == 0 {
+				Debugf("Table %s has no rows, using the flat %d%% NULLs", tab, cmdOptions.NullPercent)
+			}
+			for c, f := range fractions {
+				sampledNullFractions[ruleKey(tab, c)] = f
+			}
+		}
+		bar.Add(1)
+	}
+}
+
+// The probability of the column value being a NULL, the columns
+// that doesn't accept NULLs never get one
+func nullProbability(tab string, c DBColumns) float64 {
+	if !c.IsNullable {
+		return 0
+	}
+	rule := columnRule(tab, c.Column)
+	if rule != nil && rule.generatorName() == "tristate" {
+		return rule.triStateNullFraction()
+	}
+	if rule != nil && rule.generatorName() == "correlated" {
+		return 0 // the boolean of the row decides the NULLs
+	}
+	if isDistributionColumn(tab, c.Column) {
+		return 0 // citus rejects the rows without a distribution value
+	}
+	if f, ok := sampledNullFractions[ruleKey(tab, c.Column)]; ok {
+		return f
+	}
+	return float64(cmdOptions.NullPercent) / 100
+}
+
+// Is the column loaded with NULLs since its data type is not supported
+func isNullFallback(tab string, c DBColumns) bool {
+	nullFallbackMutex.Lock()
+	defer nullFallbackMutex.Unlock()
+	return nullFallbacks[ruleKey(tab, c.Column)]
+}
+
+// Load the nullable column of an unsupported data type with NULLs, so
+// the rest of the columns of the table are still loaded
+func addNullFallback(tab string, c DBColumns) {
+	nullFallbackMutex.Lock()
+	defer nullFallbackMutex.Unlock()
+	key := ruleKey(tab, c.Column)
+	if !nullFallbacks[key] {
+		Warnf("Data type %s of the column %s of table %s is not supported, the column is loaded with NULLs",
+			c.Datatype, c.Column, tab)
+		nullFallbacks[key] = true
+	}
+}
+
+// Should the value of the column on this row be a NULL
+func isNullValue(tab string, c DBColumns) bool {
+	p := nullProbability(tab, c)
+	return p > 0 && r.Float64() < p
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	registerGenerator("numeric_distribution",
+		"Numbers of the \"distribution: normal\" (mean, stddev), \"lognormal\" (mean and stddev of the logarithm) "+
+			"or \"exponential\" (mean), set by the distribution when there is no generator",
+		buildNumericDistribution)
+}
+
+// Is it a distribution of the numeric_distribution generator
+func isNumericDistribution(distribution string) bool {
+	return StringContains(distribution, []string{"normal", "lognormal", "exponential"})
+}
+
+// Validate the options of the numeric_distribution rule
+func validateNumericDistributionRule(c *ColumnRule) error {
+	if !isNumericDistribution(c.Distribution) {
+		return fmt.Errorf("numeric_distribution generator needs the distribution normal, lognormal or exponential")
+	}
+	if c.Mean == nil {
+		return fmt.Errorf("%s distribution needs the mean", c.Distribution)
+	}
+	switch {
+	case c.Distribution == "exponential" && *c.Mean <= 0:
+		return fmt.Errorf("mean of the exponential distribution should be greater than 0, got %v", *c.Mean)
+	case c.Distribution != "exponential" && c.Stddev <= 0:
+		return fmt.Errorf("%s distribution needs a stddev greater than 0, got %v", c.Distribution, c.Stddev)
+	case c.Distribution == "exponential" && c.Stddev != 0:
+		return fmt.Errorf("exponential distribution has no stddev, its the same as the mean")
+	}
+	return nil
+}
+
+// Numeric distribution generator, the sample is clamped to the range of the
+// data type of the column and rounded to its scale. The samples out of the
+// range are set to the bound instead of drawn again, so a distribution that
+// spills over the range piles up on the bounds
+func buildNumericDistribution(ctx *generatorContext) (interface{}, error) {
+	dt := ctx.Column.Datatype
+	var value float64
+	switch ctx.Rule.Distribution {
+	case "normal":
+		value = *ctx.Rule.Mean + r.NormFloat64()*ctx.Rule.Stddev
+	case "lognormal":
+		value = math.Exp(*ctx.Rule.Mean + r.NormFloat64()*ctx.Rule.Stddev)
+	case "exponential":
+		value = r.ExpFloat64() * *ctx.Rule.Mean
+	}
+
+	switch {
+	case StringHasPrefix(dt, intKeywords) && !strings.HasSuffix(dt, "[]"):
+		b := intStorageRanges[dt]
+		value = math.Max(float64(b[0]), math.Min(float64(b[1]), math.Round(value)))
+		if value >= math.MaxInt64 { // the float of the bigint bound is one above it
+			return strconv.FormatInt(math.MaxInt64, 10), nil
+		}
+		return strconv.FormatFloat(value, 'f', 0, 64), nil
+	case strings.HasPrefix(dt, "numeric") && !strings.HasSuffix(dt, "[]"):
+		precision, scale, ok := numericTypmod(dt)
+		if !ok {
+			return strconv.FormatFloat(value, 'f', -1, 64), nil
+		}
+		// Rounded to the scale and clamped to the most that fits the precision
+		unit := math.Pow10(-scale)
+		max := math.Pow10(precision-scale) - unit
+		value = math.Max(-max, math.Min(max, math.Round(value/unit)*unit))
+		if scale < 0 {
+			return strconv.FormatFloat(value, 'f', 0, 64), nil
+		}
+		return strconv.FormatFloat(value, 'f', scale, 64), nil
+	case dt == "money":
+		return strconv.FormatFloat(value, 'f', 2, 64), nil
+	case dt == "real":
+		return strconv.FormatFloat(math.Max(-math.MaxFloat32, math.Min(math.MaxFloat32, value)), 'f', -1, 32), nil
+	case dt == "double precision":
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	}
+	return "", fmt.Errorf("numeric_distribution generator only supports the integer, numeric and floating point "+
+		"columns, got %s", dt)
+}
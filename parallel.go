@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Load the tables with a pool of --parallel workers, each table opens its own
+// database connections. The progress of all the tables goes to a single bar
+// so the bars don't overwrite each other. A table whose references rules
+// point to a table before it on the list waits for that table to be loaded,
+// the same as when the tables are loaded one by one
+func loadTablesInParallel(ctx context.Context, tables []TableCollection) {
+	total := 0
+	done := make(map[string]chan struct{})
+	for _, t := range tables {
+		tab := GenerateTableName(t.Table, t.Schema)
+		total += rowsToMock(tab)
+		done[tab] = make(chan struct{})
+	}
+	Infof("Loading %d tables using %d parallel workers", len(tables), cmdOptions.Parallel)
+	bar := StartProgressBar(fmt.Sprintf("Mocking %d tables", len(tables)), total)
+
+	pending := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < cmdOptions.Parallel; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range pending {
+				t := tables[i]
+				tab := GenerateTableName(t.Table, t.Schema)
+				for _, dep := range referencedTables(t, tables[:i]) {
+					Debugf("Table %s waits for the table %s it refers to", tab, dep)
+					<-done[dep]
+				}
+				loadTable(ctx, t, bar)
+				close(done[tab])
+			}
+		}()
+	}
+	for i := range tables {
+		pending <- i
+	}
+	close(pending)
+	wg.Wait()
+}
+
+var (
+	// Connections the workers of all the tables can take, one at a time each
+	connectionSlots     chan struct{}
+	connectionSlotsOnce sync.Once
+)
+
+// Most connections the workers of all the tables have open at the same time
+func maxConnections() int {
+	if cmdOptions.MaxConnections > 0 {
+		return cmdOptions.MaxConnections
+	}
+	if n := cmdOptions.Parallel * cmdOptions.MaxConcurrencyPerTable; n > 1 {
+		return n
+	}
+	return 1
+}
+
+// Wait for a free connection of --max-connections, the worker gives it back
+// with releaseConnection when its done with the database
+func acquireConnection() {
+	connectionSlotsOnce.Do(func() {
+		connectionSlots = make(chan struct{}, maxConnections())
+	})
+	connectionSlots <- struct{}{}
+}
+
+func releaseConnection() {
+	<-connectionSlots
+}
+
+// The tables of the list that the references rules of the table point to
+func referencedTables(t TableCollection, tables []TableCollection) []string {
+	tab := GenerateTableName(t.Table, t.Schema)
+	var refs []string
+	for _, c := range t.Columns {
+		rule := columnRule(tab, c.Column)
+		if rule == nil || IsStringEmpty(rule.References) {
+			continue
+		}
+		reftab, _, err := parseReference(rule.References)
+		if err != nil || reftab == tab || StringContains(reftab, refs) {
+			continue
+		}
+		for _, p := range tables {
+			if GenerateTableName(p.Table, p.Schema) == reftab {
+				refs = append(refs, reftab)
+			}
+		}
+	}
+	return refs
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/xitongsys/parquet-go/writer"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// Data types that doesn't have a clean parquet mapping
+	parquetSkippedKeywords = []string{"interval", "time with time zone", "bit", "tsquery", "tsvector",
+		"pg_lsn", "txid_snapshot", "path", "polygon", "line", "lseg", "box", "circle", "point"}
+
+	// Layouts of the dates and time generated by the randomizer
+	parquetTimestampLayouts = []string{"2006-01-02 15:04:05.999999999", "2006-01-02 15:04:05.999999999Z07:00",
+		"2006-01-02 15:04:05.999999999-07"}
+)
+
+// A column of the parquet file
+type parquetColumn struct {
+	index    int
+	datatype string
+}
+
+// Write the rows of the table to a parquet file
+type parquetWriter struct {
+	file    *os.File
+	pw      *writer.CSVWriter
+	columns []parquetColumn
+
+	// The file as its listed on the manifest
+	manifest manifestFile
+}
+
+// Create the parquet file <dir>/<schema>.<table><part>.parquet, the columns
+// without a parquet mapping are left out of the file. The part is empty
+// unless the table is split to shards
+func newParquetWriter(t TableCollection, part string) (*parquetWriter, error) {
+	tab := GenerateTableName(t.Table, t.Schema)
+	w := &parquetWriter{manifest: manifestFile{Schema: t.Schema, Table: t.Table, Format: "parquet"}}
+	var metadata []string
+	for i, c := range t.Columns {
+		md, ok := parquetMetadata(c)
+		if !ok {
+			Warnf("Column %s of table %s is left out of the parquet file, since the data type %s "+
+				"has no parquet mapping", c.Column, tab, c.Datatype)
+			continue
+		}
+		metadata = append(metadata, md)
+		w.columns = append(w.columns, parquetColumn{index: i, datatype: c.Datatype})
+		w.manifest.Columns = append(w.manifest.Columns, c.Column)
+	}
+	if len(metadata) == 0 {
+		return nil, fmt.Errorf("table %s has no columns with a parquet mapping", tab)
+	}
+
+	err := os.MkdirAll(cmdOptions.OutputParquet, os.ModePerm)
+	if err != nil {
+		return nil, fmt.Errorf("creating the parquet directory: %v", err)
+	}
+	filename := filepath.Join(cmdOptions.OutputParquet, fmt.Sprintf("%s.%s%s.parquet", t.Schema, t.Table, part))
+	w.manifest.File = filename
+	w.file, err = os.Create(filename)
+	if err != nil {
+		return nil, fmt.Errorf("creating the parquet file: %v", err)
+	}
+	w.pw, err = writer.NewCSVWriterFromWriter(metadata, w.file, 1)
+	if err != nil {
+		w.file.Close()
+		return nil, fmt.Errorf("creating the parquet writer for %s: %v", filename, err)
+	}
+	Debugf("Writing the mock data of table %s to the parquet file %s", tab, filename)
+	return w, nil
+}
+
+// Parquet schema of the column, false if the data type has no clean mapping
+func parquetMetadata(c DBColumns) (string, bool) {
+	dt := c.Datatype
+	name := strings.Replace(c.Column, ",", "_", -1)
+	var t string
+	switch {
+	case strings.HasSuffix(dt, "[]") || StringHasPrefix(dt, parquetSkippedKeywords):
+		return "", false
+	case StringHasPrefix(dt, []string{"smallint", "integer"}):
+		t = "type=INT32"
+	case StringHasPrefix(dt, []string{"bigint", "oid"}):
+		t = "type=INT64"
+	case strings.HasPrefix(dt, "boolean"):
+		t = "type=BOOLEAN"
+	case strings.HasPrefix(dt, "real"):
+		t = "type=FLOAT"
+	case StringHasPrefix(dt, []string{"double precision", "numeric", "money"}):
+		t = "type=DOUBLE"
+	case strings.HasPrefix(dt, "date"):
+		t = "type=INT32, convertedtype=DATE"
+	case strings.HasPrefix(dt, "timestamp"):
+		t = "type=INT64, convertedtype=TIMESTAMP_MICROS"
+	case strings.HasPrefix(dt, "time without time zone"):
+		t = "type=INT64, convertedtype=TIME_MICROS"
+	case strings.HasPrefix(dt, "bytea"):
+		t = "type=BYTE_ARRAY"
+	default: // character, text, uuid, json, xml, network, enums ...
+		t = "type=BYTE_ARRAY, convertedtype=UTF8"
+	}
+	return fmt.Sprintf("name=%s, %s, repetitiontype=OPTIONAL", name, t), true
+}
+
+// Write the row to the parquet file
+func (w *parquetWriter) Write(data []string) error {
+	rec := make([]*string, len(w.columns))
+	for i, c := range w.columns {
+		if data[c.index] == nullValue {
+			continue
+		}
+		v, err := parquetValue(data[c.index], c.datatype)
+		if err != nil {
+			return err
+		}
+		rec[i] = &v
+	}
+	if err := w.pw.WriteString(rec); err != nil {
+		return err
+	}
+	w.manifest.Rows++
+	return nil
+}
+
+// The dates and times are stored as numbers on the parquet file and the
+// bytea as its bytes instead of the hex format
+func parquetValue(value, dt string) (string, error) {
+	switch {
+	case strings.HasPrefix(dt, "date"):
+		d, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return "", fmt.Errorf("converting date %s to parquet: %v", value, err)
+		}
+		return strconv.FormatInt(d.Unix()/86400, 10), nil
+	case strings.HasPrefix(dt, "timestamp"):
+		for _, layout := range parquetTimestampLayouts {
+			if ts, err := time.Parse(layout, value); err == nil {
+				return strconv.FormatInt(ts.UnixNano()/1000, 10), nil
+			}
+		}
+		return "", fmt.Errorf("converting timestamp %s to parquet: unknown format", value)
+	case strings.HasPrefix(dt, "time without time zone"):
+		t, err := time.Parse("15:04:05.999999999", value)
+		if err != nil {
+			return "", fmt.Errorf("converting time %s to parquet: %v", value, err)
+		}
+		midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return strconv.FormatInt(t.Sub(midnight).Microseconds(), 10), nil
+	case strings.HasPrefix(dt, "bytea") && strings.HasPrefix(value, `\x`):
+		b, err := hex.DecodeString(value[2:])
+		if err != nil {
+			return "", fmt.Errorf("converting bytea %s to parquet: %v", value, err)
+		}
+		return string(b), nil
+	}
+	return value, nil
+}
+
+// Flush the footer and close the file
+func (w *parquetWriter) Close() error {
+	if err := w.pw.WriteStop(); err != nil {
+		w.file.Close()
+		return fmt.Errorf("completing the parquet file: %v", err)
+	}
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	addManifestFile(w.manifest)
+	return nil
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Bound of a partition, the partition key values generated for the
+// partition should stay within it or the rows are rejected by the database
+type partitionBound struct {
+	Column   string
+	Strategy string
+	From, To string   // range partitions, empty when unbounded i.e MINVALUE / MAXVALUE
+	Values   []string // list partitions
+}
+
+var (
+	// The bounds of the partition and of the partitions above it, by column
+	partitionBounds = make(map[string][]*partitionBound)
+
+	// Layouts of the date and time literals on the partition bounds
+	partitionTimeLayouts = []string{"2006-01-02 15:04:05.999999999-07:00", "2006-01-02 15:04:05.999999999-07",
+		"2006-01-02 15:04:05.999999999", "2006-01-02"}
+)
+
+// If the table is a partition, extract the bound of its partition key. On a
+// partition of a partitioned partition the bounds of the partitions above it
+// apply too, the bound of the lower level wins when both are on the column
+func extractPartitionBound(tab string) {
+	for _, p := range GetPartitionBound(tab) {
+		b, err := parsePartitionBound(p.Partkey, p.Bound, p.Siblings)
+		if err != nil {
+			Warnf("Values of the partition key of table %s are not aligned to the partition bound "+
+				"\"%s\", the rows outside the partition will be rejected: %v", tab, p.Bound, err)
+			continue
+		}
+		if b == nil || partitionKeyBound(tab, b.Column) != nil {
+			continue
+		}
+		Debugf("Values of the column %s of partition %s are generated within \"%s\"", b.Column, tab, p.Bound)
+		partitionBounds[tab] = append(partitionBounds[tab], b)
+	}
+}
+
+// Parse the partition key i.e "RANGE (created_at)" and the partition
+// bound i.e "FOR VALUES FROM ('2020-01-01') TO ('2020-02-01')", the
+// bounds of the other partitions place the default partition
+func parsePartitionBound(partkey, bound string, siblings []string) (*partitionBound, error) {
+	rs := regexp.MustCompile(`^(RANGE|LIST|HASH) \((.*)\)$`).FindStringSubmatch(partkey)
+	if len(rs) == 0 {
+		return nil, fmt.Errorf("unknown partition key %s", partkey)
+	}
+	strategy, column := strings.ToLower(rs[1]), rs[2]
+	if !regexp.MustCompile(`^("[^"]+"|[a-z_][a-z0-9_$]*)$`).MatchString(column) {
+		return nil, fmt.Errorf("only the partitions by a single column are supported, got %s", partkey)
+	}
+	b := &partitionBound{Column: strings.Trim(column, `"`), Strategy: strategy}
+	if bound == "DEFAULT" {
+		return defaultPartitionBound(b, siblings)
+	}
+
+	switch strategy {
+	case "range":
+		rs = regexp.MustCompile(`^FOR VALUES FROM \((.*)\) TO \((.*)\)$`).FindStringSubmatch(bound)
+		if len(rs) == 0 {
+			return nil, fmt.Errorf("unknown range bound %s", bound)
+		}
+		b.From, b.To = partitionLiteral(rs[1]), partitionLiteral(rs[2])
+	case "list":
+		rs = regexp.MustCompile(`^FOR VALUES IN \((.*)\)$`).FindStringSubmatch(bound)
+		if len(rs) == 0 {
+			return nil, fmt.Errorf("unknown list bound %s", bound)
+		}
+		for _, v := range splitPartitionLiterals(rs[1]) {
+			if v == "NULL" {
+				b.Values = append(b.Values, nullValue)
+			} else {
+				b.Values = append(b.Values, partitionLiteral(v))
+			}
+		}
+	default:
+		return nil, fmt.Errorf("hash partitions are not supported")
+	}
+	return b, nil
+}
+
+// The default partition gets the rows that the other partitions don't, so
+// the values of a range default partition are generated past the highest
+// upper bound of the others, or below their lowest lower bound when one of
+// them is up to MAXVALUE. The list ones are left to the random values
+func defaultPartitionBound(b *partitionBound, siblings []string) (*partitionBound, error) {
+	if b.Strategy != "range" || len(siblings) == 0 {
+		return nil, nil
+	}
+	var lowest, highest string
+	unboundedBelow, unboundedAbove := false, false
+	for _, s := range siblings {
+		rs := regexp.MustCompile(`^FOR VALUES FROM \((.*)\) TO \((.*)\)$`).FindStringSubmatch(s)
+		if len(rs) == 0 {
+			return nil, fmt.Errorf("unknown range bound %s", s)
+		}
+		from, to := partitionLiteral(rs[1]), partitionLiteral(rs[2])
+		if IsStringEmpty(from) {
+			unboundedBelow = true
+		} else if IsStringEmpty(lowest) || comparePartitionLiterals(from, lowest) < 0 {
+			lowest = from
+		}
+		if IsStringEmpty(to) {
+			unboundedAbove = true
+		} else if IsStringEmpty(highest) || comparePartitionLiterals(to, highest) > 0 {
+			highest = to
+		}
+	}
+	switch {
+	case !unboundedAbove:
+		b.From = highest
+	case !unboundedBelow:
+		b.To = lowest
+	default:
+		return nil, fmt.Errorf("the other partitions go from MINVALUE to MAXVALUE")
+	}
+	return b, nil
+}
+
+// Compare the range bound literals as dates, numbers or else text
+func comparePartitionLiterals(a, b string) int {
+	if ta, err := parsePartitionTime(a); err == nil {
+		if tb, err := parsePartitionTime(b); err == nil {
+			switch {
+			case ta.Before(tb):
+				return -1
+			case ta.After(tb):
+				return 1
+			}
+			return 0
+		}
+	}
+	if fa, err := strconv.ParseFloat(a, 64); err == nil {
+		if fb, err := strconv.ParseFloat(b, 64); err == nil {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+// Split the comma separated literals, the commas within quotes are retained
+func splitPartitionLiterals(s string) []string {
+	var literals []string
+	var current strings.Builder
+	quoted := false
+	for _, c := range s {
+		switch {
+		case c == '\'':
+			quoted = !quoted // doubled quotes toggle twice, so they stay quoted
+		case c == ',' && !quoted:
+			literals = append(literals, strings.TrimSpace(current.String()))
+			current.Reset()
+			continue
+		}
+		current.WriteRune(c)
+	}
+	return append(literals, strings.TrimSpace(current.String()))
+}
+
+// Unquote the bound literal, MINVALUE and MAXVALUE are taken as unbounded
+func partitionLiteral(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "MINVALUE" || s == "MAXVALUE" {
+		return ""
+	}
+	if strings.HasPrefix(s, "'") && strings.HasSuffix(s, "'") {
+		s = strings.Replace(s[1:len(s)-1], "''", "'", -1)
+	}
+	return s
+}
+
+// The partition bound of the column, nil if its not a partition key of the table
+func partitionKeyBound(tab, column string) *partitionBound {
+	for _, b := range partitionBounds[tab] {
+		if b.Column == column {
+			return b
+		}
+	}
+	return nil
+}
+
+// Build a value that lies within the partition, lower bound is inclusive
+// and the upper bound is exclusive
+func (b *partitionBound) build(dt string) (interface{}, error) {
+	if b.Strategy == "list" {
+		return RandomPickerFromArray(b.Values), nil
+	}
+	switch {
+	case StringHasPrefix(dt, intKeywords):
+		return b.buildInteger(dt)
+	case strings.HasPrefix(dt, "date"), strings.HasPrefix(dt, "timestamp"):
+		return b.buildTime(dt)
+	case strings.HasPrefix(dt, "numeric"), StringHasPrefix(dt, floatKeywords):
+		return b.buildFloat(dt)
+	case !IsStringEmpty(b.From): // the lower bound is always within the partition
+		return b.From, nil
+	}
+	return BuildData(dt)
+}
+
+// Integer within the partition, the unbounded sides are the storage range
+// of the data type
+func (b *partitionBound) buildInteger(dt string) (interface{}, error) {
+	storage, ok := intStorageRanges[dt]
+	if !ok {
+		storage = intStorageRanges["bigint"]
+	}
+	lo, hi := storage[0], storage[1]
+	if !IsStringEmpty(b.From) {
+		from, err := strconv.ParseInt(b.From, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("partition lower bound %s: %v", b.From, err)
+		}
+		if from > lo {
+			lo = from
+		}
+	}
+	if !IsStringEmpty(b.To) {
+		to, err := strconv.ParseInt(b.To, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("partition upper bound %s: %v", b.To, err)
+		}
+		if to <= lo {
+			return "", fmt.Errorf("partition range [%d, %d) has no values of %s", lo, to, dt)
+		}
+		if to-1 < hi { // the upper bound is exclusive
+			hi = to - 1
+		}
+	}
+	if lo > hi {
+		return "", fmt.Errorf("partition range [%s, %s) has no values of %s", b.From, b.To, dt)
+	}
+	return randomInt64Between(lo, hi), nil
+}
+
+// Date or timestamp within the partition
+func (b *partitionBound) buildTime(dt string) (interface{}, error) {
+	var lo, hi time.Time
+	var err error
+	if !IsStringEmpty(b.From) {
+		if lo, err = parsePartitionTime(b.From); err != nil {
+			return "", err
+		}
+	}
+	if !IsStringEmpty(b.To) {
+		if hi, err = parsePartitionTime(b.To); err != nil {
+			return "", err
+		}
+	}
+	switch { // unbounded sides are the usual years range around the other side
+	case lo.IsZero() && hi.IsZero():
+		lo, hi = referenceTime().AddDate(fromYear, 0, 0), referenceTime().AddDate(toYear, 0, 0)
+	case lo.IsZero():
+		lo = hi.AddDate(fromYear, 0, 0)
+	case hi.IsZero():
+		hi = lo.AddDate(toYear, 0, 0)
+	}
+
+	if strings.HasPrefix(dt, "date") {
+		days := int(hi.Sub(lo).Hours() / 24)
+		if days < 1 {
+			return "", fmt.Errorf("partition range [%s, %s) has no dates", b.From, b.To)
+		}
+		return lo.AddDate(0, 0, r.Intn(days)).Format("2006-01-02"), nil
+	}
+	seconds := hi.Unix() - lo.Unix()
+	if seconds < 1 {
+		return lo.Format("2006-01-02 15:04:05.999999-07:00"), nil
+	}
+	value := lo.Add(time.Duration(r.Int63n(seconds)) * time.Second)
+	if strings.Contains(dt, "with time zone") {
+		return value.Format("2006-01-02 15:04:05-07:00"), nil
+	}
+	return value.Format("2006-01-02 15:04:05"), nil
+}
+
+// Parse the date or timestamp literal of the partition bound
+func parsePartitionTime(s string) (time.Time, error) {
+	for _, layout := range partitionTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown date or timestamp format of the partition bound %s", s)
+}
+
+// Decimal within the partition
+func (b *partitionBound) buildFloat(dt string) (interface{}, error) {
+	decimals := 3
+	if strings.HasPrefix(dt, "numeric") && BracketsExists(dt) {
+		_, scale, err := FloatPrecision(dt)
+		if err != nil {
+			return "", err
+		}
+		decimals = scale
+	}
+	lo, hi := float64(math.MinInt32), float64(math.MaxInt32)
+	var err error
+	if !IsStringEmpty(b.From) {
+		if lo, err = strconv.ParseFloat(b.From, 64); err != nil {
+			return "", fmt.Errorf("partition lower bound %s: %v", b.From, err)
+		}
+	}
+	if !IsStringEmpty(b.To) {
+		if hi, err = strconv.ParseFloat(b.To, 64); err != nil {
+			return "", fmt.Errorf("partition upper bound %s: %v", b.To, err)
+		}
+	}
+	switch { // unbounded sides are the integer range away from the other side
+	case IsStringEmpty(b.From) && !IsStringEmpty(b.To):
+		lo = hi - math.MaxInt32
+	case !IsStringEmpty(b.From) && IsStringEmpty(b.To):
+		hi = lo + math.MaxInt32
+	}
+	if lo >= hi {
+		return "", fmt.Errorf("partition range [%v, %v) has no values", lo, hi)
+	}
+
+	// Round down, so the value never reaches the exclusive upper bound
+	p := math.Pow(10, float64(decimals))
+	value := math.Max(math.Floor((lo+r.Float64()*(hi-lo))*p)/p, lo)
+	return strconv.FormatFloat(value, 'f', decimals, 64), nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestPartitionBuildInteger(t *testing.T) {
+	for _, tc := range []struct {
+		dt, from, to string
+		lo, hi       int64 // both included
+	}{
+		{"integer", "10000000", "", 10000000, math.MaxInt32},
+		{"integer", "", "-10000000", math.MinInt32, -10000001},
+		{"integer", "2000000000", "", 2000000000, math.MaxInt32},
+		{"integer", "5", "6", 5, 5},
+		{"smallint", "", "", math.MinInt16, math.MaxInt16},
+		{"bigint", "1000000000000000000", "", 1000000000000000000, math.MaxInt64},
+		{"bigint", "", "", math.MinInt64, math.MaxInt64},
+		{"bigint", "-9000000000000000000", "9000000000000000000", -9000000000000000000, 8999999999999999999},
+		{"oid", "", "100", 0, 99},
+	} {
+		b := &partitionBound{Column: "id", Strategy: "range", From: tc.from, To: tc.to}
+		for i := 0; i < 200; i++ {
+			v, err := b.buildInteger(tc.dt)
+			if err != nil {
+				t.Fatalf("buildInteger(%s) of [%s, %s): %v", tc.dt, tc.from, tc.to, err)
+			}
+			if value := v.(int64); value < tc.lo || value > tc.hi {
+				t.Fatalf("buildInteger(%s) of [%s, %s) = %d, want within %d to %d",
+					tc.dt, tc.from, tc.to, value, tc.lo, tc.hi)
+			}
+		}
+	}
+	for _, tc := range []struct{ dt, from, to string }{
+		{"integer", "5", "5"},
+		{"integer", "3000000000", ""},
+		{"smallint", "", "-40000"},
+	} {
+		b := &partitionBound{Column: "id", Strategy: "range", From: tc.from, To: tc.to}
+		if v, err := b.buildInteger(tc.dt); err == nil {
+			t.Errorf("buildInteger(%s) of [%s, %s) = %v, want an error", tc.dt, tc.from, tc.to, v)
+		}
+	}
+}
+
+func TestDefaultPartitionBound(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		siblings []string
+		from, to string
+		lo, hi   int64 // values of the default partition, both included
+	}{
+		{"above the others", []string{"FOR VALUES FROM (0) TO (10000000)", "FOR VALUES FROM (10000000) TO (20000000)"},
+			"20000000", "", 20000000, math.MaxInt32},
+		{"below the one up to MAXVALUE", []string{"FOR VALUES FROM (10000000) TO (MAXVALUE)"},
+			"", "10000000", math.MinInt32, 9999999},
+	} {
+		b, err := defaultPartitionBound(&partitionBound{Column: "id", Strategy: "range"}, tc.siblings)
+		if err != nil {
+			t.Fatalf("%s: defaultPartitionBound: %v", tc.name, err)
+		}
+		if b.From != tc.from || b.To != tc.to {
+			t.Fatalf("%s: defaultPartitionBound = [%s, %s), want [%s, %s)", tc.name, b.From, b.To, tc.from, tc.to)
+		}
+		for i := 0; i < 200; i++ {
+			v, err := b.buildInteger("integer")
+			if err != nil {
+				t.Fatalf("%s: buildInteger of the default partition: %v", tc.name, err)
+			}
+			if value := v.(int64); value < tc.lo || value > tc.hi {
+				t.Fatalf("%s: buildInteger of the default partition = %d, want within %d to %d",
+					tc.name, value, tc.lo, tc.hi)
+			}
+			v, err = b.buildFloat("numeric(12,2)")
+			if err != nil {
+				t.Fatalf("%s: buildFloat of the default partition: %v", tc.name, err)
+			}
+			if value, _ := strconv.ParseFloat(v.(string), 64); (tc.from != "" && value < float64(tc.lo)) ||
+				(tc.to != "" && value >= float64(tc.hi)+1) {
+				t.Fatalf("%s: buildFloat of the default partition = %s, want within [%s, %s)",
+					tc.name, v, tc.from, tc.to)
+			}
+		}
+	}
+	if _, err := defaultPartitionBound(&partitionBound{Column: "id", Strategy: "range"},
+		[]string{"FOR VALUES FROM (MINVALUE) TO (0)", "FOR VALUES FROM (0) TO (MAXVALUE)"}); err == nil {
+		t.Errorf("defaultPartitionBound of partitions from MINVALUE to MAXVALUE, want an error")
+	}
+}
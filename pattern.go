@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"strings"
+)
+
+// Most repetitions added to the minimum of the *, + and {n,} quantifiers
+const patternMaxRepeat = 8
+
+func init() {
+	registerGenerator("pattern",
+		"Strings matching the regular expression of the rule i.e SKU-[A-Z]{3}-\\d{4} (pattern: <regex>), "+
+			"with literals, character classes, groups, alternation and the quantifiers, set by the pattern "+
+			"when there is no generator",
+		buildPattern)
+}
+
+// Validate the pattern of the rule, only the expressions whose strings can
+// be built are accepted i.e not the word boundaries
+func validatePatternRule(c *ColumnRule) error {
+	if IsStringEmpty(c.Pattern) {
+		return fmt.Errorf("pattern generator needs the pattern")
+	}
+	re, err := syntax.Parse(c.Pattern, syntax.Perl)
+	if err != nil {
+		return fmt.Errorf("invalid pattern: %v", err)
+	}
+	re = re.Simplify()
+	if err := checkPattern(re); err != nil {
+		return fmt.Errorf("pattern %s: %v", c.Pattern, err)
+	}
+	c.pattern = re
+	return nil
+}
+
+// Check all the parts of the pattern can be built
+func checkPattern(re *syntax.Regexp) error {
+	switch re.Op {
+	case syntax.OpWordBoundary, syntax.OpNoWordBoundary, syntax.OpNoMatch:
+		return fmt.Errorf("%s is not supported", re)
+	}
+	for _, s := range re.Sub {
+		if err := checkPattern(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Pattern generator, the strings longer than the column are built again
+// and after a few tries the value is truncated to the length of the column
+func buildPattern(ctx *generatorContext) (interface{}, error) {
+	max := ctx.Column.MaxLength
+	if min := patternMinLength(ctx.Rule.pattern); max > 0 && min > max {
+		return "", fmt.Errorf("pattern %s needs at least %d characters, more than the %d of %s",
+			ctx.Rule.Pattern, min, max, ctx.Column.Datatype)
+	}
+	var value string
+	for i := 0; i < maxLoop; i++ {
+		var b strings.Builder
+		writePattern(&b, ctx.Rule.pattern)
+		value = b.String()
+		if max == 0 || len([]rune(value)) <= max {
+			break
+		}
+	}
+	return value, nil
+}
+
+// Write a random string matching the expression
+func writePattern(b *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, c := range re.Rune {
+			if re.Flags&syntax.FoldCase != 0 && r.Intn(2) == 0 {
+				c = []rune(strings.ToUpper(string(c)))[0]
+			}
+			b.WriteRune(c)
+		}
+	case syntax.OpCharClass:
+		b.WriteRune(randomClassRune(re.Rune))
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		b.WriteRune(rune(RandomInt(0x20, 0x7f)))
+	case syntax.OpCapture:
+		writePattern(b, re.Sub[0])
+	case syntax.OpConcat:
+		for _, s := range re.Sub {
+			writePattern(b, s)
+		}
+	case syntax.OpAlternate:
+		writePattern(b, re.Sub[r.Intn(len(re.Sub))])
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		min, max := re.Min, re.Max
+		switch re.Op {
+		case syntax.OpStar:
+			min, max = 0, -1
+		case syntax.OpPlus:
+			min, max = 1, -1
+		case syntax.OpQuest:
+			min, max = 0, 1
+		}
+		if max < 0 {
+			max = min + patternMaxRepeat
+		}
+		for n := RandomInt(min, max+1); n > 0; n-- {
+			writePattern(b, re.Sub[0])
+		}
+	}
+	// The anchors and the empty matches add nothing
+}
+
+// Random character of the class, the ranges are clipped to the printable
+// ASCII characters when they have any i.e the negated classes
+func randomClassRune(ranges []rune) rune {
+	var clipped []rune
+	for i := 0; i < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		if lo < 0x20 {
+			lo = 0x20
+		}
+		if hi > 0x7e {
+			hi = 0x7e
+		}
+		if lo <= hi {
+			clipped = append(clipped, lo, hi)
+		}
+	}
+	if len(clipped) > 0 {
+		ranges = clipped
+	}
+	total := 0
+	for i := 0; i < len(ranges); i += 2 {
+		total += int(ranges[i+1]-ranges[i]) + 1
+	}
+	n := r.Intn(total)
+	for i := 0; i < len(ranges); i += 2 {
+		size := int(ranges[i+1]-ranges[i]) + 1
+		if n < size {
+			return ranges[i] + rune(n)
+		}
+		n -= size
+	}
+	return ranges[0]
+}
+
+// Fewest characters of the strings matching the expression
+func patternMinLength(re *syntax.Regexp) int {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return len(re.Rune)
+	case syntax.OpCharClass, syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		return 1
+	case syntax.OpCapture:
+		return patternMinLength(re.Sub[0])
+	case syntax.OpConcat:
+		n := 0
+		for _, s := range re.Sub {
+			n += patternMinLength(s)
+		}
+		return n
+	case syntax.OpAlternate:
+		n := -1
+		for _, s := range re.Sub {
+			if l := patternMinLength(s); n < 0 || l < n {
+				n = l
+			}
+		}
+		return n
+	case syntax.OpPlus:
+		return patternMinLength(re.Sub[0])
+	case syntax.OpRepeat:
+		return re.Min * patternMinLength(re.Sub[0])
+	}
+	return 0
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"github.com/icrowley/fake"
+	"strings"
+)
+
+// The person a row describes, the name and the address columns of the row
+// are taken from the same person so they are consistent with each other
+// and with the locale (--locale)
+type person struct {
+	Gender    string
+	FirstName string
+	LastName  string
+	Street    string
+	City      string
+	State     string
+	Country   string
+	Zip       string
+	Phone     string
+}
+
+// Country of the addresses generated on the locale
+var localeCountries = map[string]string{"en": "United States", "ru": "Россия"}
+
+func init() {
+	personGenerators := []struct {
+		name, description, hint string
+		minLength               int
+	}{
+		{"first_name", "First name of the person of the row", `^(.*_)?(first_?name|given_?name|fname)$`, 16},
+		{"last_name", "Last name of the person of the row", `^(.*_)?(last_?name|surname|family_?name|lname)$`, 16},
+		{"full_name", "First and last name of the person of the row", `^(.*_)?(full_?name|person_?name|customer_?name|contact_?name)$`, 32},
+		{"street_address", "Street address of the person of the row", `^((home|billing|shipping|mailing|postal)_)?(street|street_?address|address|address_?line_?1|address1)$`, 48},
+		{"city", "City of the person of the row", `^(.*_)?(city|town)$`, 32},
+		{"state", "State of the person of the row", `^(.*_)?(province|state_?name|state_?province)$`, 32},
+		{"country", "Country of the locale of the row", `^(.*_)?(country|country_?name)$`, 24},
+		{"postal_code", "Postal code of the person of the row", `^(.*_)?(zip|zip_?code|postal_?code|post_?code)$`, 10},
+		{"phone", "Phone number of the person of the row", `^(.*_)?(phone|phone_?number|mobile|telephone)$`, 20},
+	}
+	for _, g := range personGenerators {
+		name := g.name
+		registerGenerator(name, g.description+", consistent with the rest of the person columns", func(ctx *generatorContext) (interface{}, error) {
+			return fitText(ctx.Column.Datatype, ctx.Row.person().field(name))
+		})
+		registerNameHint(g.hint, name, g.minLength)
+	}
+}
+
+// The person of the row, its generated on the first request
+func (row *rowContext) person() *person {
+	if row.personRecord != nil {
+		return row.personRecord
+	}
+	p := &person{Gender: "female"}
+	if RandomInt(0, 2) == 0 {
+		p.Gender = "male"
+	}
+	if p.Gender == "male" {
+		p.FirstName, p.LastName = fake.MaleFirstName(), fake.MaleLastName()
+	} else {
+		p.FirstName, p.LastName = fake.FemaleFirstName(), fake.FemaleLastName()
+	}
+	p.Street, p.City, p.State = localeStreetAddress(), fake.City(), fake.State()
+	p.Country, p.Zip, p.Phone = localeCountries[cmdOptions.Locale], fake.Zip(), fake.Phone()
+	row.personRecord = p
+	return p
+}
+
+// Street address on the locale, only the english data has the street
+// suffixes so the other locales drop the english suffix they fall back to
+func localeStreetAddress() string {
+	if cmdOptions.Locale == "en" {
+		return fake.StreetAddress()
+	}
+	street := fake.Street()
+	if i := strings.LastIndex(street, " "); i > 0 {
+		street = street[:i]
+	}
+	return fmt.Sprintf("%s, %d", street, RandomInt(1, 100))
+}
+
+// Value of the field of the person
+func (p *person) field(name string) string {
+	switch name {
+	case "first_name":
+		return p.FirstName
+	case "last_name":
+		return p.LastName
+	case "full_name":
+		return strings.TrimSpace(p.FirstName + " " + p.LastName)
+	case "street_address":
+		return p.Street
+	case "city":
+		return p.City
+	case "state":
+		return p.State
+	case "country":
+		return p.Country
+	case "postal_code":
+		return p.Zip
+	}
+	return p.Phone
+}
+
+// Cut the text to the length of the character column
+func fitText(dt, text string) (string, error) {
+	if !strings.HasPrefix(dt, "character") || declaredLength(dt) == 0 && strings.HasPrefix(dt, "character varying") {
+		return text, nil
+	}
+	l, err := CharLen(dt)
+	if err != nil {
+		return "", err
+	}
+	if runes := []rune(text); len(runes) > l {
+		text = string(runes[:l])
+	}
+	return text, nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"strings"
+)
+
+// PgBouncer listens on this port unless configured otherwise
+const pgbouncerPort = "6432"
+
+// Is the database reached through a pooler in transaction pooling mode,
+// i.e PgBouncer with pool_mode = transaction, where the session level
+// settings don't persist between the transactions
+func isTransactionPooler() bool {
+	return cmdOptions.Pooler == "transaction"
+}
+
+// Let the user know how the pooler is handled, the database behind a
+// PgBouncer on its default port likely needs the transaction mode
+func checkPooler() {
+	if isTransactionPooler() {
+		Infof("Pooler in transaction mode, the session level settings are applied with SET LOCAL " +
+			"within the transaction of each COPY")
+		return
+	}
+	db := openDB()
+	defer db.Close()
+	if strings.HasSuffix(db.Options().Addr, ":"+pgbouncerPort) {
+		Warnf("Port %s is the default port of PgBouncer, if it uses transaction pooling "+
+			"use \"--pooler transaction\" so the session level settings are not lost", pgbouncerPort)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	registerExtensionType("geometry", "postgis", buildPostGIS)
+	registerExtensionType("geography", "postgis", buildPostGIS)
+}
+
+// PostGIS geometry and geography builder, the values are WKT in longitude /
+// latitude degrees, with the SRID of the column as EWKT when it has one. The
+// columns without a geometry type i.e plain geometry get points
+func buildPostGIS(dt string) (interface{}, error) {
+	shape, srid := "POINT", ""
+	if i := strings.Index(dt, "("); i >= 0 {
+		modifiers := strings.Split(strings.TrimSuffix(dt[i+1:], ")"), ",")
+		shape = strings.ToUpper(strings.TrimSpace(modifiers[0]))
+		if len(modifiers) > 1 {
+			srid = strings.TrimSpace(modifiers[1])
+		}
+	}
+
+	// The dimensions are part of the type i.e PointZ, PointM, PointZM
+	dims := ""
+	for _, d := range []string{"ZM", "Z", "M"} {
+		if strings.HasSuffix(shape, d) && shape != "GEOMETRY" {
+			shape, dims = strings.TrimSuffix(shape, d), d
+			break
+		}
+	}
+
+	var wkt string
+	switch shape {
+	case "POINT", "GEOMETRY":
+		lon, lat := randomLonLat(180, 90)
+		wkt = fmt.Sprintf("POINT%s(%s)", wktDimensions(dims), wktCoordinate(lon, lat, dims))
+	case "LINESTRING":
+		lon, lat := randomLonLat(179, 89)
+		var points []string
+		for i := RandomInt(2, 6); i > 0; i-- {
+			points = append(points, wktCoordinate(lon, lat, dims))
+			lon = math.Max(-180, math.Min(180, lon+r.Float64()-0.5))
+			lat = math.Max(-90, math.Min(90, lat+r.Float64()-0.5))
+		}
+		wkt = fmt.Sprintf("LINESTRING%s(%s)", wktDimensions(dims), strings.Join(points, ","))
+	case "POLYGON":
+		// A rectangle around the center, the ring is counter clockwise and closed
+		lon, lat := randomLonLat(179, 89)
+		w, h := 0.001+r.Float64()/2, 0.001+r.Float64()/2
+		var ring []string
+		for _, c := range [][2]float64{{-w, -h}, {w, -h}, {w, h}, {-w, h}, {-w, -h}} {
+			ring = append(ring, wktCoordinate(lon+c[0], lat+c[1], dims))
+		}
+		wkt = fmt.Sprintf("POLYGON%s((%s))", wktDimensions(dims), strings.Join(ring, ","))
+	default:
+		return "", fmt.Errorf("unsupported datatypes found: %v, only the point, linestring and polygon "+
+			"geometries are generated", dt)
+	}
+
+	if !IsStringEmpty(srid) && srid != "0" {
+		return fmt.Sprintf("SRID=%s;%s", srid, wkt), nil
+	}
+	return wkt, nil
+}
+
+// Random longitude and latitude within the bounds
+func randomLonLat(lon, lat float64) (float64, float64) {
+	return (r.Float64()*2 - 1) * lon, (r.Float64()*2 - 1) * lat
+}
+
+// Dimensions of the WKT geometry, the 2D geometries have none
+func wktDimensions(dims string) string {
+	if IsStringEmpty(dims) {
+		return ""
+	}
+	return " " + dims + " "
+}
+
+// Coordinate of the WKT geometry, the Z is an elevation and the M a measure
+func wktCoordinate(lon, lat float64, dims string) string {
+	c := []string{strconv.FormatFloat(lon, 'f', 6, 64), strconv.FormatFloat(lat, 'f', 6, 64)}
+	for range dims {
+		c = append(c, strconv.FormatFloat(r.Float64()*1000, 'f', 2, 64))
+	}
+	return strings.Join(c, " ")
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+)
+
+// The postgres and greenplum backend, reached with go-pg. The tables are
+// loaded with COPY once their constraints are removed
+type postgresBackend struct{}
+
+// Print the version of the database, whether its postgres or greenplum and
+// how the pooler in front of it is handled
+func (postgresBackend) connect() {
+	dbVersion()
+	checkPooler()
+}
+
+func (postgresBackend) tables(schema string, names []string) []DBTables {
+	switch {
+	case len(names) > 0:
+		return dbExtractTables(generateWhereClause(names))
+	case !IsStringEmpty(schema):
+		return dbExtractTables(fmt.Sprintf("AND n.nspname = '%s'", schema))
+	}
+	return dbExtractTables("")
+}
+
+// Columns of the table, the partition bounds, the row security and the
+// domains of the columns are read along with them on postgres
+func (postgresBackend) columns(t DBTables) []DBColumns {
+	tab := GenerateTableName(t.Table, t.Schema)
+	var columns []DBColumns
+	if GreenplumOrPostgres == "postgres" {
+		columns = columnExtractorPostgres(fmt.Sprintf("\"%s\"", t.Schema), t.Table)
+		extractPartitionBound(tab)
+		checkRowSecurity(tab)
+	} else {
+		columns = columnExtractorGPDB(fmt.Sprintf("\"%s\"", t.Schema), t.Table)
+	}
+
+	// The domains are generated as their base type
+	resolveDomains(tab, columns)
+	return columns
+}
+
+// Columns of the single column primary and unique keys of the catalog
+func (postgresBackend) singleColumnKeys(tab string) []string {
+	var keys []string
+	for _, contype := range []string{"p", "u"} {
+		for _, con := range GetPGConstraintDDL(contype) {
+			if con.Tablename != tab {
+				continue
+			}
+			cols, err := ColExtractor(con.Constraintkey, `\(([^\[\]]*)\)`)
+			if err == nil && len(constraintColumns(cols)) == 1 {
+				keys = append(keys, constraintColumns(cols)[0])
+			}
+		}
+	}
+	return keys
+}
+
+func (postgresBackend) columnMax(tab, column string) int64 {
+	return GetColumnMax(tab, column)
+}
+
+func (postgresBackend) referencedKeys(tab, column string) []string {
+	return GetReferencedKeys(tab, column)
+}
+
+func (postgresBackend) newWriter(t TableCollection, tab string, col []string) (rowWriter, error) {
+	return newPostgresWriter(t, tab, col)
+}
+
+func (postgresBackend) exec(stmt string) error {
+	_, err := ExecuteDB(stmt)
+	return err
+}
+
+// The table names of postgres are already quoted where they need it
+func (postgresBackend) tableName(tab string) string {
+	return tab
+}
+
+func (postgresBackend) insertDefaults(tab string) string {
+	return fmt.Sprintf("INSERT INTO %s default values;", tab)
+}
+
+func (postgresBackend) backupConstraints() {
+	BackupDDL()
+}
+
+func (postgresBackend) removeConstraints(tab string) {
+	RemoveConstraints(tab)
+}
+
+// Fix the keys the mocked rows broke and recreate the constraints
+func (postgresBackend) restoreConstraints() {
+	reportConstraints(FixConstraints())
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// Rows of each table sampled by --probe-types
+	probeSampleRows = 1000
+
+	// Share of the sampled values that has to match a pattern to classify the column
+	probeMatchRatio = 0.9
+
+	// The most distinct values a column can have to be taken as categorical
+	probeMaxCategories = 50
+)
+
+// A semantic the values of a text column can hold, with the generator or
+// the data type override that generates it
+type probePattern struct {
+	Name      string
+	Match     func(string) bool
+	Generator string
+	Datatype  string // to override on --columns-from-file when there is no generator
+}
+
+var probePatterns = []probePattern{
+	{"UUIDs", matchPattern(`^(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`), "", "uuid"},
+	{"emails", matchPattern(`^[^@\s]+@[^@\s]+\.[a-zA-Z]{2,}$`), "", ""},
+	{"URLs", matchPattern(`^(?i)https?://\S+$`), "", ""},
+	{"IP addresses", matchPattern(`^(\d{1,3}\.){3}\d{1,3}(/\d{1,2})?$`), "", "inet"},
+	{"timestamps", matchPattern(`^\d{4}-\d{2}-\d{2}[ T]\d{2}:\d{2}(:\d{2}(\.\d+)?)?`), "", "timestamp without time zone"},
+	{"dates", matchPattern(`^\d{4}-\d{2}-\d{2}$`), "", "date"},
+	{"integers", matchPattern(`^-?\d{1,18}$`), "", "bigint"},
+	{"decimal numbers", matchPattern(`^-?\d+\.\d+$`), "", "numeric"},
+	{"JSON documents", isJSONDocument, "", "jsonb"},
+	{"phone numbers", matchPattern(`^\+?[\d\s().-]{7,20}$`), "phone", ""},
+	{"file paths", matchPattern(`^([a-zA-Z]:\\|/)?([^/\\\s]+[/\\])+[^/\\\s]+\.[a-zA-Z0-9]{1,5}$`), "file_path", ""},
+	{"file names", matchPattern(`^[^/\\\s]+\.[a-zA-Z0-9]{1,5}$`), "file_name", ""},
+	{"cron expressions", func(v string) bool { return validateCron(v) == nil }, "cron", ""},
+}
+
+// Match the values with the regular expression
+func matchPattern(pattern string) func(string) bool {
+	return regexp.MustCompile(pattern).MatchString
+}
+
+// Is the value a JSON object or array
+func isJSONDocument(v string) bool {
+	v = strings.TrimSpace(v)
+	return (strings.HasPrefix(v, "{") || strings.HasPrefix(v, "[")) && json.Valid([]byte(v))
+}
+
+// Sample the rows of the tables, classify the values of their text columns
+// and write the suggested generators as a starter rules file. The categorical
+// columns get a histogram file of the sampled values next to the rules file
+func ProbeTypes(tables []TableCollection) {
+	Infof("Probing the values of the text columns, the suggested rules are written to: %s", cmdOptions.ProbeTypes)
+	var rules, overrides strings.Builder
+	fmt.Fprintf(&rules, "# Starter rules file generated by %s %s on %s from a sample of %d rows of each table,\n"+
+		"# review the suggestions before using it with --rules\n", programName, programVersion,
+		ExecutionTimestamp, probeSampleRows)
+	rules.WriteString("tables:\n")
+
+	dir := filepath.Dir(cmdOptions.ProbeTypes)
+	for _, t := range tables {
+		tab := GenerateTableName(t.Table, t.Schema)
+		var columns []DBColumns
+		for _, c := range t.Columns {
+			if isTextDatatype(c.Datatype) {
+				columns = append(columns, c)
+			}
+		}
+		if len(columns) == 0 {
+			continue
+		}
+		var names []string
+		for _, c := range columns {
+			names = append(names, c.Column)
+		}
+		samples := make([][]string, len(columns))
+		for _, s := range GetKeySample(tab, names, probeSampleRows) {
+			var values []*string
+			if err := json.Unmarshal([]byte(s), &values); err != nil {
+				Fatalf("Error when reading the sampled row %s of table %s, err: %v", s, tab, err)
+			}
+			for i, v := range values {
+				if v != nil {
+					samples[i] = append(samples[i], *v)
+				}
+			}
+		}
+
+		var lines []string
+		for i, c := range columns {
+			values := samples[i]
+			if len(values) == 0 {
+				continue
+			}
+			p, ok := classifyValues(values)
+			switch {
+			case ok && !IsStringEmpty(p.Generator):
+				lines = append(lines, fmt.Sprintf("      # %s holds %s", c.Column, p.Name),
+					fmt.Sprintf("      - column: %s", yamlString(c.Column)),
+					fmt.Sprintf("        generator: %s", p.Generator))
+			case ok && !IsStringEmpty(p.Datatype):
+				lines = append(lines, fmt.Sprintf("      # %s holds %s, generate it as %s with --columns-from-file",
+					c.Column, p.Name, p.Datatype))
+				fmt.Fprintf(&overrides, "# %s.%s.%s %s\n", t.Schema, t.Table, c.Column, p.Datatype)
+			case ok:
+				lines = append(lines, fmt.Sprintf("      # %s holds %s, there is no generator for them yet",
+					c.Column, p.Name))
+			default:
+				counts := make(map[string]int)
+				for _, v := range values {
+					counts[v]++
+				}
+				switch {
+				case len(counts) == len(values) && len(values) > 1:
+					lines = append(lines, fmt.Sprintf("      # %s has no repeated values on the sample", c.Column),
+						fmt.Sprintf("      - column: %s", yamlString(c.Column)),
+						"        generator: unique")
+				case len(counts) <= probeMaxCategories && len(counts)*10 <= len(values) && !hasCommentValue(counts):
+					file := filepath.Join(dir, fmt.Sprintf("%s.%s.%s.csv", t.Schema, t.Table, c.Column))
+					writeProbeHistogram(file, counts)
+					lines = append(lines, fmt.Sprintf("      # %s has %d distinct values on the sample", c.Column, len(counts)),
+						fmt.Sprintf("      - column: %s", yamlString(c.Column)),
+						fmt.Sprintf("        histogram: %s", yamlString(file)))
+				}
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		fmt.Fprintf(&rules, "  - schema: %s\n    table: %s\n    columns:\n%s\n",
+			yamlString(t.Schema), yamlString(t.Table), strings.Join(lines, "\n"))
+	}
+	if overrides.Len() > 0 {
+		rules.WriteString("# The data types to override with --columns-from-file\n" + overrides.String())
+	}
+
+	if err := ioutil.WriteFile(cmdOptions.ProbeTypes, []byte(rules.String()), 0644); err != nil {
+		Fatalf("Error when writing the rules file %s, err: %v", cmdOptions.ProbeTypes, err)
+	}
+	Infof("Written the suggested rules to %s, review them before loading the data with --rules", cmdOptions.ProbeTypes)
+}
+
+// The first pattern most of the values match
+func classifyValues(values []string) (probePattern, bool) {
+	for _, p := range probePatterns {
+		matched := 0
+		for _, v := range values {
+			if p.Match(v) {
+				matched++
+			}
+		}
+		if float64(matched) >= probeMatchRatio*float64(len(values)) {
+			return p, true
+		}
+	}
+	return probePattern{}, false
+}
+
+// Write the sampled values and their frequency as a categorical histogram
+func writeProbeHistogram(filename string, counts map[string]int) {
+	var values []string
+	for v := range counts {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if counts[values[i]] != counts[values[j]] {
+			return counts[values[i]] > counts[values[j]]
+		}
+		return values[i] < values[j]
+	})
+
+	file, err := os.Create(filename)
+	if err != nil {
+		Fatalf("Error when creating the histogram file %s, err: %v", filename, err)
+	}
+	defer file.Close()
+	w := csv.NewWriter(file)
+	for _, v := range values {
+		if err := w.Write([]string{v, fmt.Sprint(counts[v])}); err != nil {
+			Fatalf("Error when writing the histogram file %s, err: %v", filename, err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		Fatalf("Error when writing the histogram file %s, err: %v", filename, err)
+	}
+}
+
+// Values starting with # are read as comments of the histogram file
+func hasCommentValue(counts map[string]int) bool {
+	for v := range counts {
+		if strings.HasPrefix(v, "#") {
+			return true
+		}
+	}
+	return false
+}
+
+// Quote the value for the yaml file
+func yamlString(s string) string {
+	return fmt.Sprintf("%q", s)
+}
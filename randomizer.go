@@ -1,21 +1,67 @@
 package main
 
 import (
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/icrowley/fake"
 	"math"
 	"math/rand"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 // Set the seed value of the random generator
 var r *rand.Rand
 func init() {
-	r = rand.New(rand.NewSource(time.Now().UnixNano()))
+	r = rand.New(&lockedSource{src: rand.NewSource(time.Now().UnixNano()).(rand.Source64)})
+}
+
+// Seed the random generator and the fake data library
+func SeedRandom(seed int64) {
+	Debugf("Seeding the random generator with %d", seed)
+	r.Seed(seed)
+	fake.Seed(seed)
+}
+
+// The random dates are relative to this time when seeded, so the same seed
+// generates the same dates on any day
+var seededReferenceTime = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Time the random dates are relative to
+func referenceTime() time.Time {
+	if cmdOptions.Seed != 0 {
+		return seededReferenceTime
+	}
+	return time.Now()
+}
+
+// Random source that is safe to share between the loading workers
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source64
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
 }
 
 // Random String generator
@@ -36,15 +82,20 @@ func RandomInt(min, max int) int {
 	return r.Intn(max-min) + min
 }
 
-// Random Bytea data
+// Random Bytea data of 1 to maxlen bytes
 func RandomBytea(maxlen int) []byte {
 	result := make([]byte, r.Intn(maxlen)+1)
 	for i := range result {
-		result[i] = byte(r.Intn(255))
+		result[i] = byte(r.Intn(256))
 	}
 	return result
 }
 
+// Random bytea in the hex format \x... of --bytea-length bytes at most
+func RandomByteaHex() string {
+	return `\x` + hex.EncodeToString(RandomBytea(cmdOptions.ByteaLength))
+}
+
 // Random Float generator based on precision specified
 func RandomFloat(min, max, precision int) (float64) {
 	output := math.Pow(10, float64(precision))
@@ -55,10 +106,11 @@ func RandomFloat(min, max, precision int) (float64) {
 // Random calender date time generator
 func RandomCalenderDateTime(fromyear, toyear int) (time.Time, error) {
 	if fromyear > toyear {
-		return time.Now(), errors.New("number of years behind is greater than number of years in future")
+		return referenceTime(), errors.New("number of years behind is greater than number of years in future")
 	}
-	min := time.Now().AddDate(fromyear, 0, 0).Unix()
-	max := time.Now().AddDate(toyear, 0, 0).Unix()
+	now := referenceTime()
+	min := now.AddDate(fromyear, 0, 0).Unix()
+	max := now.AddDate(toyear, 0, 0).Unix()
 	delta := max - min
 	sec := r.Int63n(delta) + min
 	return time.Unix(sec, 0), nil
@@ -169,17 +221,36 @@ func RandomBit(max int) string {
 	return bitValue
 }
 
-// Random UUID
+// Random UUID, taken from the random generator when seeded so the
+// same seed generates the same UUIDs
 func RandomUUID() string {
-	return uuid.New().String()
+	if cmdOptions.Seed == 0 {
+		return uuid.New().String()
+	}
+	var u uuid.UUID
+	hi, lo := r.Uint64(), r.Uint64()
+	for i := 0; i < 8; i++ {
+		u[i], u[8+i] = byte(hi>>(56-8*i)), byte(lo>>(56-8*i))
+	}
+	u[6] = u[6]&0x0f | 0x40 // version 4
+	u[8] = u[8]&0x3f | 0x80 // variant 10
+	return u.String()
+}
+
+// Random IPv4 network, the bits of the address after the mask are zero
+// since the cidr data type rejects them
+func RandomCIDR() string {
+	bits := RandomInt(8, 31)
+	ip := r.Uint32() &^ (1<<uint(32-bits) - 1)
+	return fmt.Sprintf("%d.%d.%d.%d/%d", byte(ip>>24), byte(ip>>16), byte(ip>>8), byte(ip), bits)
 }
 
 // Random Mac Address
 func RandomMacAddress() string {
 	return fmt.Sprintf("%02x:%02x:%02x:%02x:%02x:%02x",
-		RandomString(1), RandomString(1),
-		RandomString(1), RandomString(1),
-		RandomString(1), RandomString(1))
+		r.Intn(256), r.Intn(256),
+		r.Intn(256), r.Intn(256),
+		r.Intn(256), r.Intn(256))
 }
 
 // Random Text Search Query
@@ -197,7 +268,6 @@ func RandomTSQuery() string {
 	} else {
 		return fake.WordsN(1) + " & ( " + fake.WordsN(1) + " | " + fake.WordsN(1) + " )"
 	}
-	return ""
 }
 
 // Random Text Search Query
@@ -222,7 +292,6 @@ func RandomGeometricData(randomInt int, GeoMetry string, IsItArray bool) string
 			RandomInt(1, 999), RandomInt(1, 999))
 		return FormatForArray(data, IsItArray)
 	}
-	return ""
 }
 
 // Random Log Sequence Number
@@ -284,4 +353,20 @@ func RandomPickerFromArray(a []string) string {
 // Random value from length
 func RandomValueFromLength(i int) int {
 	return r.Int() % i
-}
\ No newline at end of file
+}
+// Running total of the weights, used to pick a weighted random value
+func CumulativeWeights(weights []float64) []float64 {
+	cumulative := make([]float64, len(weights))
+	var total float64
+	for i, w := range weights {
+		total += w
+		cumulative[i] = total
+	}
+	return cumulative
+}
+
+// Random index picked based on the cumulative weights
+func RandomWeightedIndex(cumulative []float64) int {
+	n := r.Float64() * cumulative[len(cumulative)-1]
+	return sort.Search(len(cumulative)-1, func(i int) bool { return cumulative[i] > n })
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Largest values of the integer columns
+var intMaxValues = map[string]float64{"smallint": math.MaxInt16, "integer": math.MaxInt32,
+	"bigint": math.MaxInt64, "oid": math.MaxUint32}
+
+func init() {
+	registerRowGenerator("range_end",
+		"End of a range stored in two numeric columns i.e max_price, never below the start column of the "+
+			"row (source_columns: [<start column>]) and at most \"spread: <n>\" above it (default 100)",
+		buildRangeEnd)
+}
+
+// Validate the options of the range end rule
+func validateRangeEndRule(c *ColumnRule) error {
+	if len(c.SourceColumns) != 1 {
+		return fmt.Errorf("range_end generator needs the source_columns with the start column of the range")
+	}
+	if c.SourceColumns[0] == c.Column {
+		return fmt.Errorf("range of the column %s cannot start on itself", c.Column)
+	}
+	if c.Spread == 0 {
+		c.Spread = 100
+	}
+	if c.Spread < 0 {
+		return fmt.Errorf("spread cannot be negative, got %v", c.Spread)
+	}
+	return nil
+}
+
+// Range end generator, its the start of the row plus a random part of the
+// spread, cut at the largest value of the column. The start being NULL
+// leaves the end random
+func buildRangeEnd(ctx *generatorContext) (interface{}, error) {
+	dt := ctx.Column.Datatype
+	decimals, ok := numericDecimals(dt)
+	if !ok {
+		return "", fmt.Errorf("range_end generator only supports the integer and numeric columns, got %s", dt)
+	}
+	s := ctx.Rule.SourceColumns[0]
+	v, ok := ctx.Row.Values[s]
+	if !ok {
+		return "", fmt.Errorf("source column %s is not a column of table %s or is not generated yet",
+			s, ctx.Table)
+	}
+	if v == nullValue {
+		return BuildData(dt)
+	}
+	start, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return "", fmt.Errorf("start column %s has the non numeric value \"%s\"", s, v)
+	}
+
+	scale := math.Pow10(decimals)
+	end := math.Floor((start+r.Float64()*ctx.Rule.Spread)*scale) / scale
+	if end < start { // the start can have more decimals than the column
+		end = math.Ceil(start*scale) / scale
+	}
+	if max, ok := numericMaxValue(dt, decimals); ok && end > max {
+		end = max
+	}
+	return strconv.FormatFloat(end, 'f', decimals, 64), nil
+}
+
+// Largest value the column can store, false when its not limited
+func numericMaxValue(dt string, decimals int) (float64, bool) {
+	for k, max := range intMaxValues {
+		if strings.HasPrefix(dt, k) {
+			return max, true
+		}
+	}
+	if !strings.HasPrefix(dt, "numeric(") {
+		return 0, false
+	}
+	precision, err := strconv.Atoi(strings.Split(strings.Trim(dt, "numeric()"), ",")[0])
+	if err != nil {
+		return 0, false
+	}
+	return math.Pow10(precision-decimals) - math.Pow10(-decimals), true
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Token bucket of the run, the tokens refill at the --rate-limit and each
+// row takes one, so a burst is at most a second worth of rows. The workers
+// of all the tables share it, so together they stay under the limit
+type rateLimiter struct {
+	mutex  sync.Mutex
+	rate   float64 // rows per second
+	tokens float64
+	last   time.Time
+}
+
+var (
+	// Rows paced by the rate limit and the time the workers waited for them
+	rateLimitedRows int64
+	rateLimitedWait time.Duration
+	rateLimitMutex  sync.Mutex
+
+	runRateLimiter     *rateLimiter
+	runRateLimiterOnce sync.Once
+)
+
+// Rate limiter shared by the workers of the run, its nil when there is no
+// rate limit
+func sharedRateLimiter() *rateLimiter {
+	if cmdOptions.RateLimit <= 0 {
+		return nil
+	}
+	runRateLimiterOnce.Do(func() {
+		runRateLimiter = &rateLimiter{rate: float64(cmdOptions.RateLimit), tokens: 1, last: time.Now()}
+	})
+	return runRateLimiter
+}
+
+// Wait till there is a token for the next row. The token is taken right
+// away and the worker sleeps off the debt, so the workers waiting together
+// are spaced by the rate
+func (l *rateLimiter) wait() {
+	if l == nil {
+		return
+	}
+	l.mutex.Lock()
+	now := time.Now()
+	l.tokens = math.Min(l.tokens+now.Sub(l.last).Seconds()*l.rate, math.Max(1, l.rate))
+	l.last = now
+	l.tokens--
+	var waited time.Duration
+	if l.tokens < 0 {
+		waited = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mutex.Unlock()
+	time.Sleep(waited)
+
+	rateLimitMutex.Lock()
+	rateLimitedRows++
+	rateLimitedWait += waited
+	rateLimitMutex.Unlock()
+}
+
+// Report how the rate limit paced the loading
+func rateLimitReport(elapsed time.Duration) {
+	if cmdOptions.RateLimit <= 0 || elapsed <= 0 {
+		return
+	}
+	Infof("Rate limit of %d rows/s: loaded %d rows at %.0f rows/s, the workers waited %v in total",
+		cmdOptions.RateLimit, rateLimitedRows, float64(rateLimitedRows)/elapsed.Seconds(),
+		rateLimitedWait.Round(time.Millisecond))
+}
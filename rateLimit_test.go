@@ -0,0 +1,41 @@
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterShared(t *testing.T) {
+	limiter := &rateLimiter{rate: 200, tokens: 1, last: time.Now()}
+	start := time.Now()
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 25; i++ {
+				limiter.wait()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 100 rows at 200 rows/s take about half a second whatever the workers,
+	// the first token is there from the start
+	if elapsed := time.Since(start); elapsed < 450*time.Millisecond {
+		t.Errorf("4 workers took %v for 100 rows at 200 rows/s, want at least 450ms", elapsed)
+	}
+}
+
+func TestSharedRateLimiter(t *testing.T) {
+	defer func(rate int) { cmdOptions.RateLimit = rate }(cmdOptions.RateLimit)
+	cmdOptions.RateLimit = 0
+	if sharedRateLimiter() != nil {
+		t.Errorf("sharedRateLimiter without --rate-limit, want nil")
+	}
+	cmdOptions.RateLimit = 1000
+	if a, b := sharedRateLimiter(), sharedRateLimiter(); a == nil || a != b {
+		t.Errorf("sharedRateLimiter = %p and %p, want the same limiter for every worker", a, b)
+	}
+}
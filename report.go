@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Version of the report file, bump it when the layout changes
+const reportVersion = 1
+
+// The report of --report, what the run did to each table for the wrappers
+// and the dashboards that can't read the log
+type runReport struct {
+	Version             int           `json:"version"`
+	Generated           string        `json:"generated"`
+	Success             bool          `json:"success"`
+	Interrupted         bool          `json:"interrupted"`
+	DurationSeconds     float64       `json:"duration_seconds"`
+	Tables              []reportTable `json:"tables"`
+	Skipped             []reportTable `json:"skipped"`
+	Failed              []reportTable `json:"failed"`
+	SerialTables        []reportTable `json:"serial_tables"`
+	ConstraintsRestored *bool         `json:"constraints_restored"` // null when they are not removed
+}
+
+// A table of the report, the reason is left out of the loaded tables
+type reportTable struct {
+	Table  string `json:"table"`
+	Rows   int    `json:"rows"`
+	Reason string `json:"reason,omitempty"`
+}
+
+var (
+	reportStart         = time.Now()
+	reportRows          = make(map[string]int) // rows written to each table
+	constraintsRestored *bool
+	reportMutex         sync.Mutex
+	reportPending       int32 // 1 once the load started, until the report is written
+	reportFatal         bool  // the run stopped on a fatal error
+)
+
+// Write the report when the run ends, the fatal errors write it too
+func startReport() {
+	atomic.StoreInt32(&reportPending, 1)
+}
+
+// Write the report of the run that stops on the fatal error, its not a
+// success whatever the tables did
+func writeFatalReport() {
+	if atomic.LoadInt32(&reportPending) == 1 {
+		reportFatal = true
+		WriteReport()
+	}
+}
+
+// Count the rows written to the table
+func reportWrittenRows(tab string, rows int) {
+	reportMutex.Lock()
+	defer reportMutex.Unlock()
+	reportRows[tab] += rows
+}
+
+// Keep whether all the removed constraints are restored
+func reportConstraints(restored bool) {
+	constraintsRestored = &restored
+}
+
+// Write the report of the run. Its a success when no table failed, the
+// constraints are restored and the run is not interrupted, the skipped
+// tables only count with --on-skipped fail-at-end like on the exit code.
+// Its written once, the errors writing it don't write it again
+func WriteReport() {
+	if !atomic.CompareAndSwapInt32(&reportPending, 1, 0) {
+		return
+	}
+	Infof("Writing the report of the run to: %s", cmdOptions.Report)
+	skipped, failed, serial := make(map[string]bool), make(map[string]bool), make(map[string]bool)
+	rep := runReport{Version: reportVersion, Interrupted: isInterrupted(),
+		Generated:       fmt.Sprintf("%s %s on %s", programName, programVersion, ExecutionTimestamp),
+		DurationSeconds: time.Since(reportStart).Seconds(), ConstraintsRestored: constraintsRestored,
+		Tables: []reportTable{}, Skipped: []reportTable{}, Failed: []reportTable{}, SerialTables: []reportTable{}}
+	for _, tab := range skippedTab {
+		reason, ok := skipReasons[tab]
+		if !ok {
+			reason = unsupportedTypes[tab]
+		}
+		rep.Skipped = append(rep.Skipped, reportTable{Table: tab, Reason: reason})
+		skipped[tab] = true
+	}
+	for _, tab := range failedTab {
+		rep.Failed = append(rep.Failed, reportTable{Table: tab, Rows: reportRows[tab], Reason: failReasons[tab]})
+		failed[tab] = true
+	}
+	for _, tab := range oneColumnTable {
+		serial[tab] = true
+		if !skipped[tab] && !failed[tab] {
+			rep.SerialTables = append(rep.SerialTables, reportTable{Table: tab, Rows: reportRows[tab]})
+		}
+	}
+	for _, tab := range loadedTables() {
+		if !serial[tab] {
+			rep.Tables = append(rep.Tables, reportTable{Table: tab, Rows: reportRows[tab]})
+		}
+	}
+	for _, tables := range [][]reportTable{rep.Tables, rep.Skipped, rep.Failed, rep.SerialTables} {
+		sort.Slice(tables, func(i, j int) bool {
+			return tables[i].Table < tables[j].Table
+		})
+	}
+	rep.Success = !reportFatal && len(rep.Failed) == 0 && !rep.Interrupted &&
+		(constraintsRestored == nil || *constraintsRestored) &&
+		(len(rep.Skipped) == 0 || cmdOptions.OnSkipped != "fail-at-end")
+
+	content, err := json.MarshalIndent(rep, "", "  ")
+	if err != nil {
+		Fatalf("Error when encoding the report, err: %v", err)
+	}
+	err = ioutil.WriteFile(cmdOptions.Report, append(content, '\n'), 0644)
+	if err != nil {
+		Fatalf("Error when writing the report %s, err: %v", cmdOptions.Report, err)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/schollz/progressbar/v3"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFatalReport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(report string) { cmdOptions.Report, reportFatal = report, false }(cmdOptions.Report)
+	cmdOptions.Report = filepath.Join(dir, "report.json")
+
+	// Nothing is written before the load starts
+	writeFatalReport()
+	if _, err := os.Stat(cmdOptions.Report); !os.IsNotExist(err) {
+		t.Fatalf("report written before the load started, err: %v", err)
+	}
+
+	startReport()
+	writeFatalReport()
+	content, err := ioutil.ReadFile(cmdOptions.Report)
+	if err != nil {
+		t.Fatalf("report not written on the fatal error: %v", err)
+	}
+	var rep runReport
+	if err := json.Unmarshal(content, &rep); err != nil {
+		t.Fatalf("report %s is not json: %v", content, err)
+	}
+	if rep.Success {
+		t.Errorf("report of the fatal error is a success: %s", content)
+	}
+
+	// The deferred write of the run doesn't write it again
+	os.Remove(cmdOptions.Report)
+	WriteReport()
+	if _, err := os.Stat(cmdOptions.Report); !os.IsNotExist(err) {
+		t.Errorf("report written twice, err: %v", err)
+	}
+}
+
+// A backend whose tables are loaded by the writer of the test
+type writerBackend struct {
+	sqlBackend
+	writer rowWriter
+}
+
+func (b writerBackend) newWriter(t TableCollection, tab string, col []string) (rowWriter, error) {
+	return b.writer, nil
+}
+
+// A database writer that flushes batches of 10 rows, the flush that
+// would go past the kept rows fails
+type failingWriter struct {
+	batch, written, keeps int
+	flushed               func(rows int)
+}
+
+func (w *failingWriter) Write(data []string) error {
+	if w.batch++; w.batch < 10 {
+		return nil
+	}
+	return w.flush()
+}
+
+func (w *failingWriter) flush() error {
+	if w.written+w.batch > w.keeps {
+		return errors.New("the database went away")
+	}
+	if w.batch > 0 {
+		w.flushed(w.batch)
+	}
+	w.written, w.batch = w.written+w.batch, 0
+	return nil
+}
+
+func (w *failingWriter) Flushed(f func(rows int)) { w.flushed = f }
+func (w *failingWriter) Close() error             { return w.flush() }
+
+func TestReportRowsOfFailedTable(t *testing.T) {
+	defer func(b backend, p int, transactional bool) {
+		activeBackend, cmdOptions.NullPercent, cmdOptions.Transactional = b, p, transactional
+	}(activeBackend, cmdOptions.NullPercent, cmdOptions.Transactional)
+	cmdOptions.NullPercent = 0
+	table := TableCollection{DBTables{Schema: "public", Table: "payments"},
+		[]DBColumns{{Column: "id", Datatype: "integer"}}}
+	tab := GenerateTableName(table.Table, table.Schema)
+	defer delete(reportRows, tab)
+	for _, tc := range []struct {
+		transactional bool
+		want          int
+	}{
+		{false, 20}, // the two batches flushed before the failure
+		{true, 0},   // rolled back along with the failure
+	} {
+		cmdOptions.Transactional = tc.transactional
+		reportRows[tab] = 0
+		activeBackend = writerBackend{writer: &failingWriter{keeps: 25}}
+		err := loadRows(context.Background(), table, tab, []string{"id"}, 50, nil, &progressbar.ProgressBar{}, nil)
+		if err == nil {
+			t.Fatalf("loadRows succeeded past the failing flush")
+		}
+		if reportRows[tab] != tc.want {
+			t.Errorf("--transactional %v: %d rows reported for the failed table, want %d",
+				tc.transactional, reportRows[tab], tc.want)
+		}
+	}
+
+	// The whole table is counted once when its loaded
+	cmdOptions.Transactional = false
+	reportRows[tab] = 0
+	activeBackend = writerBackend{writer: &failingWriter{keeps: 100}}
+	err := loadRows(context.Background(), table, tab, []string{"id"}, 50, nil, &progressbar.ProgressBar{}, nil)
+	if err != nil {
+		t.Fatalf("loadRows: %v", err)
+	}
+	if reportRows[tab] != 50 {
+		t.Errorf("%d rows reported for the loaded table, want 50", reportRows[tab])
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"strings"
+)
+
+// Pick the values of the foreign key columns from the keys of the tables
+// they refer to, by adding a references rule to the columns without a rule.
+// The self references and the multi column foreign keys are left to be fixed
+// after the load
+func respectForeignKeys(tables []TableCollection) {
+	mocked := make(map[string]TableCollection)
+	for _, t := range tables {
+		mocked[GenerateTableName(t.Table, t.Schema)] = t
+	}
+	for _, con := range GetPGConstraintDDL("f") {
+		t, ok := mocked[con.Tablename]
+		if !ok {
+			continue
+		}
+		fk := getForeignKeyColumns(constraint{con.Tablename, con.Constraintkey})
+		if strings.Contains(fk.Column, ",") {
+			Debugf("Foreign key %s of table %s has multiple columns, its fixed after the load",
+				con.Constraintname, con.Tablename)
+			continue
+		}
+		column, refcol := strings.Trim(fk.Column, `"`), strings.Trim(fk.Refcolumn, `"`)
+		reftab := qualifiedTableName(fk.Reftable)
+		if _, ok := keyColumn(t, column); !ok || reftab == con.Tablename || columnRule(con.Tablename, column) != nil {
+			continue
+		}
+		schema, table := splitTableName(reftab)
+		Debugf("Column %s of table %s picks the keys of %s.%s", column, con.Tablename, reftab, refcol)
+		columnRules[ruleKey(con.Tablename, column)] = &ColumnRule{Column: column,
+			References: strings.Join([]string{schema, table, refcol}, ".")}
+	}
+}
+
+// The referenced table of the references rules of the table that has no
+// keys to pick from, false if all of them have keys
+func tableWithoutKeys(t TableCollection) (string, bool) {
+	tab := GenerateTableName(t.Table, t.Schema)
+	for _, c := range t.Columns {
+		rule := columnRule(tab, c.Column)
+		if rule == nil || IsStringEmpty(rule.References) {
+			continue
+		}
+		reftab, refcol, err := parseReference(rule.References)
+		if err != nil {
+			continue
+		}
+		if _, err := referencedKeys(reftab, refcol); err != nil {
+			return reftab, true
+		}
+	}
+	return "", false
+}
+
+// Schema and table of the "schema"."table" name
+func splitTableName(tab string) (string, string) {
+	s := strings.SplitN(tab, `"."`, 2)
+	if len(s) == 1 {
+		return "public", strings.Trim(tab, `"`)
+	}
+	return strings.TrimPrefix(s[0], `"`), strings.TrimSuffix(s[1], `"`)
+}
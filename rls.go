@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"github.com/go-pg/pg/v10"
+	"strings"
+)
+
+// Tables with row level security enabled that the connecting role can
+// bypass, the loading connection turns off row_security for them
+var rowSecurityBypass = make(map[string]bool)
+
+// Check the row level security of the table, the policies of the table
+// can reject the mocked rows if the connecting role is not exempt from them
+func checkRowSecurity(tab string) {
+	rls := GetRowSecurity(tab)
+	if !rls.Enabled {
+		return
+	}
+	switch {
+	case rls.Superuser || rls.Bypass:
+		Debugf("Table %s has row level security enabled, loading with row_security off "+
+			"since the role bypasses the policies", tab)
+		rowSecurityBypass[tab] = true
+	case rls.Owner && !rls.Forced:
+		Debugf("Table %s has row level security enabled, the policies are not applied to the table owner", tab)
+	default:
+		Warnf("Table %s has row level security enabled and the connecting role is subject to its policies, "+
+			"the rows rejected by the policies fail the load, connect as the table owner or a role "+
+			"with BYPASSRLS to avoid it", tab)
+	}
+}
+
+// Turn off row_security on every connection of the pool, so a policy that
+// still applies errors out instead of silently filtering the rows. Within
+// the single transaction its turned off until the transaction ends
+func disableRowSecurity(db dbConnection) {
+	pool, ok := db.(*pg.DB)
+	if !ok {
+		if _, err := db.Exec("SET LOCAL row_security = off"); err != nil {
+			Fatalf("Error when turning off row_security, err: %v", err)
+		}
+		return
+	}
+	pool.Options().OnConnect = func(ctx context.Context, cn *pg.Conn) error {
+		_, err := cn.Exec("SET row_security = off")
+		return err
+	}
+}
+
+// Row level security hint for the error that the database returned, if any
+func rowSecurityHint(err error) string {
+	if !strings.Contains(err.Error(), "row-level security") {
+		return ""
+	}
+	return ", the rows are rejected by the row level security policies of the table, " +
+		"connect as the table owner or a role with BYPASSRLS"
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Read the row counts of --rows-per-table, each value is a "<schema>.<table>=<rows>"
+// pair i.e "public.orders=1000000" or a file of these pairs one per line, the
+// empty lines and the lines starting with # are skipped. The tables without
+// a schema are on public
+func LoadRowsPerTable() {
+	for _, value := range cmdOptions.RowsPerTable {
+		if strings.Contains(value, "=") {
+			if err := addRowsPerTable(value); err != nil {
+				Fatalf("Argument Error: invalid --rows-per-table \"%s\", err: %v", value, err)
+			}
+			continue
+		}
+		Infof("Loading the row counts of the tables from the file: %s", value)
+		file, err := os.Open(value)
+		if err != nil {
+			Fatalf("Error reading the row counts file %s, err: %v", value, err)
+		}
+		scanner := bufio.NewScanner(file)
+		for n := 1; scanner.Scan(); n++ {
+			line := strings.TrimSpace(scanner.Text())
+			if IsStringEmpty(line) || strings.HasPrefix(line, "#") {
+				continue
+			}
+			if err := addRowsPerTable(line); err != nil {
+				Fatalf("Invalid line %d of the row counts file %s, err: %v", n, value, err)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			Fatalf("Error reading the row counts file %s, err: %v", value, err)
+		}
+		file.Close()
+	}
+	Debugf("Loaded the row counts of %d tables", len(rowsPerTable))
+}
+
+// Register the row count of the "<schema>.<table>=<rows>" pair
+func addRowsPerTable(pair string) error {
+	s := strings.SplitN(pair, "=", 2)
+	if len(s) != 2 || IsStringEmpty(strings.TrimSpace(s[0])) {
+		return fmt.Errorf("expected \"<schema>.<table>=<rows>\"")
+	}
+	rows, err := strconv.Atoi(strings.TrimSpace(s[1]))
+	if err != nil || rows < 1 {
+		return fmt.Errorf("the rows of %s should be a number of at least 1", s[0])
+	}
+	rowsPerTable[qualifiedTableName(s[0])] = rows
+	return nil
+}
+
+// Warn about the row counts of the tables that are not mocked, i.e a typo
+// on the name, they are ignored
+func checkRowsPerTable(tables []DBTables) {
+	var names []string
+	for tab := range rowsPerTable {
+		names = append(names, tab)
+	}
+	sort.Strings(names)
+	for _, tab := range names {
+		found := false
+		for _, t := range tables {
+			if GenerateTableName(t.Table, t.Schema) == tab {
+				found = true
+				break
+			}
+		}
+		if !found {
+			Warnf("Table %s of --rows-per-table is not one of the tables to mock, its row count is ignored", tab)
+		}
+	}
+}
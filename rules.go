@@ -0,0 +1,332 @@
+package main
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"regexp/syntax"
+	"time"
+)
+
+// The rules file, it controls how the data of specific columns are generated
+type RulesFile struct {
+	Tables []TableRules `yaml:"tables"`
+}
+
+// Rules of a table
+type TableRules struct {
+	Schema  string       `yaml:"schema"`
+	Table   string       `yaml:"table"`
+	Columns []ColumnRule `yaml:"columns"`
+	Before  []string     `yaml:"before"` // statements to run before loading the table
+	After   []string     `yaml:"after"`  // statements to run after loading the table
+
+	// Column of an inheritance parent that holds the name of the table the row is loaded to
+	Discriminator string `yaml:"discriminator"`
+}
+
+// Rules of a column
+type ColumnRule struct {
+	Column        string             `yaml:"column"`
+	Generator     string             `yaml:"generator"`
+	Histogram     string             `yaml:"histogram"`
+	References    string             `yaml:"references"`
+	Distribution  string             `yaml:"distribution"`
+	Skew          float64            `yaml:"skew"`
+	Algorithm     string             `yaml:"algorithm"`
+	SourceColumns []string           `yaml:"source_columns"`
+	CronFields    int                `yaml:"cron_fields"`
+	Weights       []float64          `yaml:"weights"`
+	OSStyle       string             `yaml:"os_style"`
+	Extensions    []string           `yaml:"extensions"`
+	Start         int64              `yaml:"start"`
+	Gap           string             `yaml:"gap"`
+	Vocabulary    []string           `yaml:"vocabulary"`
+	MinItems      int                `yaml:"min_items"`
+	MaxItems      int                `yaml:"max_items"`
+	SumTarget     *float64           `yaml:"sum_target"`
+	GroupBy       []string           `yaml:"group_by"`
+	Depth         int                `yaml:"depth"`
+	Keys          []string           `yaml:"keys"`
+	Spread        float64            `yaml:"spread"`
+	Length        int                `yaml:"length"`
+	Zone          string             `yaml:"zone"`
+	From          string             `yaml:"from"`
+	To            string             `yaml:"to"`
+	LabelWeights  map[string]float64 `yaml:"label_weights"`
+	WhenTrue      *float64           `yaml:"when_true"`
+	WhenFalse     *float64           `yaml:"when_false"`
+	TruePercent   *float64           `yaml:"true_percent"`
+	MaxDistinct   int                `yaml:"max_distinct"`
+	Mean          *float64           `yaml:"mean"`
+	Stddev        float64            `yaml:"stddev"`
+	Pattern       string             `yaml:"pattern"`
+	Values        []string           `yaml:"values"`
+	ValuesFile    string             `yaml:"values_file"`
+	Min           *int64             `yaml:"min"`
+	Max           *int64             `yaml:"max"`
+
+	// Preloaded content of the rule, filled in when the rules are validated
+	histogram  *histogram
+	gap        time.Duration
+	zone       *time.Location
+	from, to   time.Time
+	pattern    *syntax.Regexp
+	cumulative []float64 // of the weights of the values
+}
+
+var (
+	tableRules  = make(map[string]*TableRules)
+	columnRules = make(map[string]*ColumnRule)
+)
+
+// Read the rules file, validate it and keep the rules in memory
+func LoadRules() {
+	Infof("Loading the column rules from the file: %s", cmdOptions.Rules)
+	content, err := ioutil.ReadFile(cmdOptions.Rules)
+	if err != nil {
+		Fatalf("Error reading the rules file %s, err: %v", cmdOptions.Rules, err)
+	}
+
+	var rules RulesFile
+	err = yaml.UnmarshalStrict(content, &rules)
+	if err != nil {
+		Fatalf("Error when parsing the rules file %s, err: %v", cmdOptions.Rules, err)
+	}
+
+	for i := range rules.Tables {
+		t := &rules.Tables[i]
+		if IsStringEmpty(t.Schema) {
+			t.Schema = "public"
+		}
+		tab := GenerateTableName(t.Table, t.Schema)
+		tableRules[tab] = t
+		for j := range t.Columns {
+			c := &t.Columns[j]
+			if err := c.validate(); err != nil {
+				Fatalf("Invalid rule for the column %s of table %s in the rules file %s, err: %v",
+					c.Column, tab, cmdOptions.Rules, err)
+			}
+			columnRules[ruleKey(tab, c.Column)] = c
+		}
+	}
+	Debugf("Loaded the rules of %d columns from %d tables", len(columnRules), len(tableRules))
+}
+
+// Validate the rule and preload any content it refers to
+func (c *ColumnRule) validate() error {
+	if IsStringEmpty(c.Column) {
+		return fmt.Errorf("column name is missing")
+	}
+	name := c.generatorName()
+	if _, ok := generators[name]; !IsStringEmpty(name) && !ok {
+		return fmt.Errorf("unknown generator \"%s\"", name)
+	}
+	if !IsStringEmpty(c.Histogram) {
+		h, err := readHistogram(c.Histogram)
+		if err != nil {
+			return err
+		}
+		c.histogram = h
+	}
+	if !IsStringEmpty(c.References) {
+		if _, _, err := parseReference(c.References); err != nil {
+			return err
+		}
+	}
+	switch name {
+	case "histogram":
+		if IsStringEmpty(c.Histogram) {
+			return fmt.Errorf("histogram generator needs the histogram file of the column")
+		}
+	case "hash":
+		if err := validateHashRule(c); err != nil {
+			return err
+		}
+	case "tristate":
+		if err := validateTriStateRule(c); err != nil {
+			return err
+		}
+	case "file_path", "file_name":
+		if err := validateFilePathRule(c); err != nil {
+			return err
+		}
+	case "event_time":
+		if err := validateEventTimeRule(c); err != nil {
+			return err
+		}
+	case "tags":
+		if err := validateTagsRule(c); err != nil {
+			return err
+		}
+	case "range_end":
+		if err := validateRangeEndRule(c); err != nil {
+			return err
+		}
+	case "zoned_time":
+		if err := validateZonedTimeRule(c); err != nil {
+			return err
+		}
+	case "enum":
+		if err := validateEnumRule(c); err != nil {
+			return err
+		}
+	case "ciphertext":
+		if err := validateCiphertextRule(c); err != nil {
+			return err
+		}
+	case "document":
+		if err := validateDocumentRule(c); err != nil {
+			return err
+		}
+	case "correlated":
+		if err := validateCorrelatedRule(c); err != nil {
+			return err
+		}
+	case "numeric_distribution":
+		if err := validateNumericDistributionRule(c); err != nil {
+			return err
+		}
+	case "pattern":
+		if err := validatePatternRule(c); err != nil {
+			return err
+		}
+	case "values":
+		if err := validateValuesRule(c); err != nil {
+			return err
+		}
+	case "int_range":
+		if err := validateIntRangeRule(c); err != nil {
+			return err
+		}
+	case "time_range":
+		if err := validateTimeRangeRule(c); err != nil {
+			return err
+		}
+	case "boolean":
+		if err := validateBooleanRule(c); err != nil {
+			return err
+		}
+	}
+	if (!IsStringEmpty(c.From) || !IsStringEmpty(c.To)) && name != "zoned_time" && name != "time_range" {
+		return fmt.Errorf("from and to are only supported by the time_range and zoned_time generators, not %s", name)
+	}
+	if (c.Min != nil || c.Max != nil) && name != "int_range" {
+		return fmt.Errorf("min and max are only supported by the int_range generator, not %s", name)
+	}
+	if c.TruePercent != nil && name != "boolean" {
+		return fmt.Errorf("true_percent is only supported by the boolean generator, not %s", name)
+	}
+	if !IsStringEmpty(c.Pattern) && name != "pattern" {
+		return fmt.Errorf("pattern is only supported by the pattern generator, not %s", name)
+	}
+	if err := validateMaxDistinct(c); err != nil {
+		return err
+	}
+	if len(c.GroupBy) > 0 && c.SumTarget == nil {
+		return fmt.Errorf("group_by is only supported along with sum_target")
+	}
+	for _, g := range c.GroupBy {
+		if g == c.Column {
+			return fmt.Errorf("group_by of the column %s cannot include itself", c.Column)
+		}
+	}
+	if c.Start < 0 {
+		return fmt.Errorf("start cannot be negative, got %d", c.Start)
+	}
+	if c.CronFields != 0 && c.CronFields != 5 && c.CronFields != 6 {
+		return fmt.Errorf("cron_fields can only be 5 or 6, got %d", c.CronFields)
+	}
+	switch c.Distribution {
+	case "", "uniform":
+	case "normal", "lognormal":
+		if name != "numeric_distribution" {
+			return fmt.Errorf("%s distribution is only supported by the numeric_distribution generator",
+				c.Distribution)
+		}
+	case "exponential":
+		if name != "event_time" && name != "numeric_distribution" {
+			return fmt.Errorf("exponential distribution is only supported by the event_time and " +
+				"numeric_distribution generators")
+		}
+	case "fixed":
+		if name != "event_time" {
+			return fmt.Errorf("fixed distribution is only supported by the event_time generator")
+		}
+	case "zipf":
+		if IsStringEmpty(c.References) {
+			return fmt.Errorf("zipf distribution is only supported along with references")
+		}
+		if c.Skew == 0 {
+			c.Skew = 1.1
+		}
+		if c.Skew <= 1 {
+			return fmt.Errorf("zipf skew should be greater than 1, got %v", c.Skew)
+		}
+	default:
+		return fmt.Errorf("unknown distribution \"%s\"", c.Distribution)
+	}
+	return nil
+}
+
+// The named generator that the rule asks for, if not specified explicitly
+// its derived from the options set on the rule
+func (c *ColumnRule) generatorName() string {
+	switch {
+	case !IsStringEmpty(c.Generator):
+		return c.Generator
+	case !IsStringEmpty(c.Histogram):
+		return "histogram"
+	case !IsStringEmpty(c.References):
+		return "foreign_key"
+	case len(c.LabelWeights) > 0:
+		return "enum"
+	case isNumericDistribution(c.Distribution):
+		return "numeric_distribution"
+	case !IsStringEmpty(c.Pattern):
+		return "pattern"
+	case len(c.Values) > 0 || !IsStringEmpty(c.ValuesFile):
+		return "values"
+	case c.Min != nil || c.Max != nil:
+		return "int_range"
+	case !IsStringEmpty(c.From) || !IsStringEmpty(c.To):
+		return "time_range"
+	case c.TruePercent != nil:
+		return "boolean"
+	}
+	return ""
+}
+
+// Key of the column on the rules collection
+func ruleKey(tab, column string) string {
+	return fmt.Sprintf("%s.\"%s\"", tab, column)
+}
+
+// Run the before or after statements of the table, false if any of them
+// failed and the user asked to continue on errors
+func runTableHooks(tab, when string) bool {
+	t, ok := tableRules[tab]
+	if !ok {
+		return true
+	}
+	statements := t.Before
+	if when == "after" {
+		statements = t.After
+	}
+	for _, stmt := range statements {
+		Debugf("Running the %s statement of table %s: %s", when, tab, stmt)
+		if err := executeStatement(stmt); err != nil {
+			if cmdOptions.ContinueOnError {
+				Warnf("Error when running the %s statement of table %s, err: %v", when, tab, err)
+				return false
+			}
+			Fatalf("Error when running the %s statement of table %s, err: %v", when, tab, err)
+		}
+	}
+	return true
+}
+
+// The rule for the column of the table, nil if there is none
+func columnRule(tab, column string) *ColumnRule {
+	return columnRules[ruleKey(tab, column)]
+}
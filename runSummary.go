@@ -0,0 +1,100 @@
+package main
+
+import (
+	"os"
+	"strings"
+)
+
+// Exit codes of the tables that are not loaded, the failed tables always
+// count and the skipped ones with --on-skipped fail-at-end. The errors that
+// stop the run exit with 1 as they happen
+const (
+	exitSkipped          = 3 // some tables are skipped with --on-skipped fail-at-end, the rest are loaded
+	exitFailed           = 4 // some tables failed with --on-error continue, the rest are loaded
+	exitSkippedAndFailed = 5
+)
+
+var (
+	mockedTables []string                  // tables the run tried to load, the serial ones included
+	failedTab    []string                  // tables that failed with --on-error continue
+	failReasons  = make(map[string]string) // why each of them failed
+)
+
+// Keep the table failed for the reason, its already reported
+func failTable(tab, reason string) {
+	tableListMutex.Lock()
+	defer tableListMutex.Unlock()
+	failedTab = append(failedTab, tab)
+	failReasons[tab] = reason
+}
+
+// Stop the run on the error of the table, or with --on-error continue keep
+// the table failed and move on to the next table
+func tableLoadFailed(tab string, err error) {
+	if !cmdOptions.ContinueOnError {
+		Fatalf("Error when loading the data of table %s: %v", tab, err)
+	}
+	Errorf("Error when loading the data of table %s, moving on to the next table: %v", tab, err)
+	failTable(tab, err.Error())
+}
+
+// The tables the run tried to load that are neither skipped nor failed
+func loadedTables() []string {
+	var loaded []string
+	seen := make(map[string]bool)
+	for _, tab := range mockedTables {
+		if !seen[tab] && !StringContains(tab, skippedTab) && !StringContains(tab, failedTab) {
+			loaded = append(loaded, tab)
+		}
+		seen[tab] = true
+	}
+	return loaded
+}
+
+// Print how many of the tables are loaded, skipped and failed at the end of
+// the run. The run exits with the code of the tables that are not loaded,
+// the skipped ones only with --on-skipped fail-at-end
+func runSummary() {
+	if len(mockedTables) == 0 {
+		return
+	}
+	Infof("Summary: %d tables loaded, %d skipped, %d failed", len(loadedTables()), len(skippedTab), len(failedTab))
+	for _, tab := range skippedTab {
+		reason, ok := skipReasons[tab]
+		if !ok {
+			reason = "unsupported data types"
+		}
+		Warnf("Skipped %s: %s", tab, reason)
+	}
+	for _, tab := range failedTab {
+		Errorf("Failed %s: %s", tab, failReasons[tab])
+	}
+	if isInterrupted() {
+		Errorf("The run was interrupted, the tables loaded and failed above are as listed and the skipped ones "+
+			"are untouched, exiting with %d", exitInterrupted)
+		os.Exit(exitInterrupted)
+	}
+	if code := summaryExitCode(); code != 0 {
+		notLoaded := failedTab
+		if code != exitFailed {
+			notLoaded = append(append([]string{}, skippedTab...), failedTab...)
+		}
+		Errorf("Not all the tables are loaded, exiting with %d, tables: %s", code, strings.Join(notLoaded, ","))
+		os.Exit(code)
+	}
+}
+
+// Exit code of the tables that are not loaded, 0 when they all are or the
+// skipped ones don't count
+func summaryExitCode() int {
+	skipped := len(skippedTab) > 0 && cmdOptions.OnSkipped == "fail-at-end"
+	switch {
+	case skipped && len(failedTab) > 0:
+		return exitSkippedAndFailed
+	case skipped:
+		return exitSkipped
+	case len(failedTab) > 0:
+		return exitFailed
+	}
+	return 0
+}
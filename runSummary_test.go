@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+func TestSummaryExitCode(t *testing.T) {
+	defer func(skipped, failed []string, onSkipped string) {
+		skippedTab, failedTab, cmdOptions.OnSkipped = skipped, failed, onSkipped
+	}(skippedTab, failedTab, cmdOptions.OnSkipped)
+	for _, tc := range []struct {
+		onSkipped       string
+		skipped, failed []string
+		want            int
+	}{
+		{"continue", nil, nil, 0},
+		{"continue", []string{"a"}, nil, 0},
+		{"continue", nil, []string{"b"}, exitFailed},
+		{"continue", []string{"a"}, []string{"b"}, exitFailed},
+		{"fail-at-end", nil, nil, 0},
+		{"fail-at-end", []string{"a"}, nil, exitSkipped},
+		{"fail-at-end", nil, []string{"b"}, exitFailed},
+		{"fail-at-end", []string{"a"}, []string{"b"}, exitSkippedAndFailed},
+	} {
+		skippedTab, failedTab, cmdOptions.OnSkipped = tc.skipped, tc.failed, tc.onSkipped
+		if got := summaryExitCode(); got != tc.want {
+			t.Errorf("summaryExitCode with --on-skipped %s, skipped %v and failed %v = %d, want %d",
+				tc.onSkipped, tc.skipped, tc.failed, got, tc.want)
+		}
+	}
+}
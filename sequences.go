@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// A column whose sequence default is overridden by the generated values
+type sequenceColumn struct {
+	Table, Column, Sequence string
+}
+
+var (
+	overriddenSequences []sequenceColumn
+
+	// Sequence of the default i.e nextval('orders_id_seq'::regclass)
+	nextvalPattern = regexp.MustCompile(`^nextval\('(.+)'(::regclass)?\)$`)
+)
+
+// Is the column left to its sequence, the columns defaulting to a sequence
+// are left to the database unless --override-sequences is set
+func isLeftToSequence(tab string, c DBColumns) bool {
+	if !isItSerialDatatype(c) {
+		return false
+	}
+	_, ok := defaultSequence(c)
+	return !cmdOptions.OverrideSequences || !ok
+}
+
+// Sequence of the nextval default of the column
+func defaultSequence(c DBColumns) (string, bool) {
+	rs := nextvalPattern.FindStringSubmatch(c.Sequence)
+	if len(rs) == 0 {
+		return "", false
+	}
+	return strings.Replace(rs[1], "''", "'", -1), true
+}
+
+// Keep the columns of the table whose sequence is overridden with
+// --override-sequences, they are synced once the tables are loaded
+func recordOverriddenSequences(tab string, columns []DBColumns) {
+	if !cmdOptions.OverrideSequences {
+		return
+	}
+	for _, c := range columns {
+		if !isItSerialDatatype(c) {
+			continue
+		}
+		seq, ok := defaultSequence(c)
+		if !ok {
+			Warnf("Unable to find the sequence of the default %s of the column %s of table %s, its left to the database",
+				c.Sequence, c.Column, tab)
+			continue
+		}
+		Debugf("Column %s of table %s is generated instead of its sequence %s", c.Column, tab, seq)
+		overriddenSequences = append(overriddenSequences, sequenceColumn{tab, c.Column, seq})
+	}
+}
+
+// Move the sequences of the overridden columns past the generated values,
+// so the rows inserted later by the application don't collide with them
+func SyncSequences() {
+	for _, s := range overriddenSequences {
+		Debugf("Moving the sequence %s past the values of the column %s of table %s", s.Sequence, s.Column, s.Table)
+		query := fmt.Sprintf(`SELECT setval('%s', max("%s")) FROM %s HAVING max("%s") >= 1`,
+			strings.Replace(s.Sequence, "'", "''", -1), s.Column, s.Table, s.Column)
+		if _, err := ExecuteDB(query); err != nil {
+			Debugf("query: %s", query)
+			Warnf("Error when moving the sequence %s past the values of the column %s of table %s, err: %v",
+				s.Sequence, s.Column, s.Table, err)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Counter of the integer key, the values continue after the largest value
+// the table already has
+type sequentialKey struct {
+	started bool
+	next    int64
+	max     int64
+}
+
+var (
+	// The single column integer keys filled with a counter, by rule key
+	sequentialKeys  = make(map[string]*sequentialKey)
+	sequentialMutex sync.Mutex
+)
+
+// Mark the columns that are the only column of a primary or unique key and
+// an integer, they get a counter instead of the random values that would
+// repeat. The columns with a rule or left to a sequence keep them
+func markSequentialKeys(tab string, columns []DBColumns) {
+	if cmdOptions.KeyMode != "sequential" {
+		return
+	}
+	keys := activeBackend.singleColumnKeys(tab)
+	for _, c := range columns {
+		bounds, ok := intStorageRanges[c.Datatype]
+		if !ok || !StringContains(c.Column, keys) || columnRule(tab, c.Column) != nil || isLeftToSequence(tab, c) {
+			continue
+		}
+		Debugf("The key column %s of table %s is filled with a counter", c.Column, tab)
+		sequentialKeys[ruleKey(tab, c.Column)] = &sequentialKey{max: bounds[1]}
+	}
+}
+
+// Is the column filled with a counter
+func isSequentialKey(tab, column string) bool {
+	_, ok := sequentialKeys[ruleKey(tab, column)]
+	return ok
+}
+
+// Next value of the counter of the key, shared by the workers and the
+// batches of the table so the values never repeat. The first value is
+// after the largest one on the table
+func sequentialKeyValue(tab, column string) (interface{}, bool, error) {
+	k, ok := sequentialKeys[ruleKey(tab, column)]
+	if !ok {
+		return nil, false, nil
+	}
+	sequentialMutex.Lock()
+	defer sequentialMutex.Unlock()
+	if !k.started {
+		k.next = activeBackend.columnMax(tab, column) + 1
+		k.started = true
+	}
+	if k.next > k.max || k.next < 1 {
+		return "", true, fmt.Errorf("the counter of the key column %s of table %s is past the largest value %d "+
+			"of its data type", column, tab, k.max)
+	}
+	v := k.next
+	k.next++
+	return v, true, nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// Split the rows of the table between the shard files, by the hash of the
+// shard key when the table has the column or else round robin. Both only
+// depend on the rows so the same seed always gives the same shards
+type shardWriter struct {
+	shards []rowWriter
+	key    int // index of the shard key column, -1 for round robin
+	next   int
+}
+
+// Open the files <schema>.<table>.part-<n> of the shards of the table
+func newShardWriter(t TableCollection, tab string, col []string) (*shardWriter, error) {
+	w := &shardWriter{key: -1}
+	for i, c := range col {
+		if c == cmdOptions.ShardKey {
+			w.key = i
+		}
+	}
+	if w.key < 0 && !IsStringEmpty(cmdOptions.ShardKey) {
+		Debugf("Table %s has no shard key column %s, its rows are split round robin", tab, cmdOptions.ShardKey)
+	}
+	for i := 0; i < cmdOptions.Shards; i++ {
+		part := fmt.Sprintf(".part-%d", i)
+		var shard rowWriter
+		var err error
+		switch {
+		case !IsStringEmpty(cmdOptions.OutputParquet):
+			shard, err = newParquetWriter(t, part)
+		case !IsStringEmpty(cmdOptions.OutputSQL):
+			shard, err = newSQLWriter(t, tab, col, part)
+		default:
+			shard, err = newCSVWriter(t, col, part)
+		}
+		if err != nil {
+			w.Close()
+			return nil, err
+		}
+		w.shards = append(w.shards, shard)
+	}
+	return w, nil
+}
+
+// Write the row to its shard
+func (w *shardWriter) Write(data []string) error {
+	shard := w.next % len(w.shards)
+	w.next++
+	if w.key >= 0 {
+		h := fnv.New32a()
+		h.Write([]byte(data[w.key]))
+		shard = int(h.Sum32() % uint32(len(w.shards)))
+	}
+	return w.shards[shard].Write(data)
+}
+
+// Close all the shards, the first error is returned
+func (w *shardWriter) Close() error {
+	var err error
+	for _, s := range w.shards {
+		if e := s.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
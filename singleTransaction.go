@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"github.com/go-pg/pg/v10"
+)
+
+// The transaction of --single-transaction, nil unless its open
+var singleTx *sharedTransaction
+
+// The transaction that all the statements of the run share, its closed
+// once at the end of the run instead of by each statement
+type sharedTransaction struct {
+	*pg.Tx
+	db *pg.DB
+}
+
+// The transaction ends with the run, not with the statement
+func (s *sharedTransaction) Close() error {
+	return nil
+}
+
+// Already within the transaction, the function runs on it and the
+// transaction is left open
+func (s *sharedTransaction) RunInTransaction(ctx context.Context, fn func(*pg.Tx) error) error {
+	return fn(s.Tx)
+}
+
+// Open the transaction that the whole load runs in, from the removal of
+// the constraints to their restore
+func BeginSingleTransaction() {
+	Info("Loading all the tables in a single transaction, any failure rolls back the whole run")
+	db := openDB()
+	tx, err := db.Begin()
+	if err != nil {
+		db.Close()
+		Fatalf("Error when starting the single transaction, err: %v", err)
+	}
+	singleTx = &sharedTransaction{Tx: tx, db: db}
+}
+
+// Commit the single transaction, a statement that failed on the way
+// leaves the transaction aborted and its rolled back instead
+func CommitSingleTransaction() {
+	if singleTx == nil {
+		return
+	}
+	if _, err := singleTx.Exec("SELECT 1"); err != nil {
+		Fatalf("The single transaction cannot be committed, err: %v", err)
+	}
+	s := singleTx
+	singleTx = nil
+	defer s.db.Close()
+	if err := s.Commit(); err != nil {
+		Fatalf("Error when committing the single transaction, nothing is loaded, err: %v", err)
+	}
+	Info("Committed the single transaction, all the tables are loaded")
+}
+
+// Roll back the single transaction, if its open, so the database is left
+// as it was before the run
+func rollbackSingleTransaction(reason string) {
+	if singleTx == nil {
+		return
+	}
+	s := singleTx
+	singleTx = nil
+	defer s.db.Close()
+	if err := s.Rollback(); err != nil {
+		Errorf("Error when rolling back the single transaction, err: %v", err)
+		return
+	}
+	Errorf("Rolled back the single transaction, none of the tables are changed. The run failed with: %s", reason)
+}
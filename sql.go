@@ -4,9 +4,18 @@ import (
 	"fmt"
 	"github.com/go-pg/pg/v10"
 	"strings"
+	"sync"
 )
 
-var GreenplumOrPostgres = "greenplum"
+var (
+	GreenplumOrPostgres = "greenplum"
+	serverVersionNum    int
+	serverVersionOnce   sync.Once
+
+	// The labels of the enum data types, read once per data type
+	enumLabels = make(map[string][]EnumDataType)
+	enumMutex  sync.Mutex
+)
 
 type DBTables struct {
 	Schema string
@@ -14,9 +23,19 @@ type DBTables struct {
 }
 
 type DBColumns struct {
-	Column   string
-	Datatype string
-	Sequence string
+	Column     string
+	Datatype   string
+	Sequence   string
+	IsNullable bool
+	MaxLength  int // of the character(n) and character varying(n) columns, else 0
+}
+
+// The domain data type, its base type is the type the domain is on and
+// when that's a domain too the base type of that domain
+type DBDomain struct {
+	Basetype string
+	NotNull  bool
+	Checks   []string `pg:",array"`
 }
 
 type DBConstraints struct {
@@ -46,6 +65,39 @@ type DBViolationRow struct {
 	Row string
 }
 
+type DBColumnStats struct {
+	Attname   string
+	NullFrac  float64
+	NDistinct float64
+	Reltuples float64
+}
+
+type DBRowSecurity struct {
+	Enabled   bool
+	Forced    bool
+	Owner     bool
+	Superuser bool
+	Bypass    bool
+}
+
+type DBPartition struct {
+	Partkey  string
+	Bound    string
+	Siblings []string `pg:",array"` // bounds of the other partitions of the parent
+}
+
+type DBInheritance struct {
+	Parent string
+	Child  string
+}
+
+type DBCitusTable struct {
+	Tablename string
+	Method    string // h hash distributed, a append distributed, n reference table
+	Column    string // the distribution column, empty on the reference tables
+	Shards    int
+}
+
 type EnumDataType struct {
 	EnumSchema string
 	EnumName   string
@@ -184,7 +236,13 @@ SELECT   a.attname                                       AS COLUMN,
                     FROM   pg_catalog.pg_attrdef d 
                     WHERE  d.adrelid = a.attrelid 
                     AND    d.adnum = a.attnum 
-                    AND    a.atthasdef ), '' ) AS sequence 
+                    AND    a.atthasdef ), '' ) AS sequence, 
+         NOT a.attnotnull                                AS is_nullable, 
+         CASE 
+                  WHEN a.atttypid IN ( 'bpchar' :: regtype, 'varchar' :: regtype ) 
+                  AND      a.atttypmod > 4 THEN a.atttypmod - 4 
+                  ELSE 0 
+         END                                             AS max_length 
 FROM     pg_catalog.pg_attribute a 
 WHERE    a.attrelid = '%s' :: regclass 
 AND      a.attnum > 0 
@@ -223,7 +281,13 @@ SELECT          a.attname                                       AS COLUMN,
                            FROM   pg_catalog.pg_attrdef d 
                            WHERE  d.adrelid = a.attrelid 
                            AND    d.adnum = a.attnum 
-                           AND    a.atthasdef ), '' ) AS sequence 
+                           AND    a.atthasdef ), '' ) AS sequence, 
+                NOT a.attnotnull                                AS is_nullable, 
+                CASE 
+                                WHEN a.atttypid IN ( 'bpchar' :: regtype, 'varchar' :: regtype ) 
+                                AND             a.atttypmod > 4 THEN a.atttypmod - 4 
+                                ELSE 0 
+                END                                             AS max_length 
 FROM            pg_catalog.pg_attribute a 
 LEFT OUTER JOIN pg_catalog.pg_attribute_encoding e 
 ON              e.attrelid = a.attrelid 
@@ -574,22 +638,33 @@ WHERE  (
 	query = fmt.Sprintf(query, tab, column)
 	_, err := ExecuteDB(query)
 	if err != nil {
-		Debug("query: %s", query)
+		Debugf("query: %s", query)
 		return err
 	}
 	return nil
 }
 
-// Check & provide values if the datatype is ENUM
+// Check & provide values if the datatype is ENUM, the labels of each data
+// type are read once. The enums outside of the search path are qualified
+// with their schema i.e myschema.mood and the mixed case ones are quoted
 func checkEnumDatatype(dt string) []EnumDataType {
-	Debugf("Checking if the datatype is enum")
+	enumMutex.Lock()
+	defer enumMutex.Unlock()
+	if result, ok := enumLabels[dt]; ok {
+		return result
+	}
+	if !isPostgresBackend() {
+		return nil // the backends cache the labels of their enum columns
+	}
+	Debugf("Checking if the datatype %s is enum", dt)
 	var result []EnumDataType
+	schema, name := splitTypeName(dt)
 
 	// db connection
 	db := ConnectDB()
 	defer db.Close()
 
-	// query
+	// query, the enums on the search path when there is no schema
 	query := `
 SELECT n.nspname   AS enum_schema, 
        t.typname   AS enum_name, 
@@ -599,16 +674,549 @@ FROM   pg_type t
          ON t.oid = e.enumtypid 
        JOIN pg_catalog.pg_namespace n 
          ON n.oid = t.typnamespace 
-WHERE  t.typname = '%s' 
+WHERE  t.typname = ?0 
+       AND ( n.nspname = ?1 
+              OR ( ?1 = '' 
+                   AND pg_catalog.pg_type_is_visible(t.oid) ) ) 
 `
-	query = fmt.Sprintf(query, dt)
 
 	// Execute and provide the result
-	_, err := db.Query(&result, query)
+	_, err := db.Query(&result, query, name, schema)
 	if err != nil {
 		Debugf("query: %s", query)
 		Fatalf("Error when executing the query to check if the data type is enum:%v", err)
 	}
 
+	enumLabels[dt] = result
+	return result
+}
+
+// The base type of the domain along with its NOT NULL and CHECK constraints,
+// false when the data type is not a domain
+func GetDomain(dt string) (DBDomain, bool) {
+	Debugf("Checking if the datatype %s is a domain", dt)
+	var result []DBDomain
+
+	// db connection
+	db := ConnectDB()
+	defer db.Close()
+
+	query := `
+SELECT pg_catalog.Format_type(t.typbasetype, t.typtypmod)    AS basetype, 
+       t.typnotnull                                          AS not_null, 
+       ARRAY(SELECT pg_catalog.Pg_get_constraintdef(c.oid) 
+             FROM   pg_catalog.pg_constraint c 
+             WHERE  c.contypid = t.oid)                      AS checks 
+FROM   pg_catalog.pg_type t 
+WHERE  t.typtype = 'd' 
+       AND pg_catalog.Format_type(t.oid, NULL) = ?0 
+`
+	_, err := db.Query(&result, query, dt)
+	if err != nil {
+		Debugf("query: %s", query)
+		Fatalf("Error when executing the query to check if the data type is a domain: %v", err)
+	}
+	if len(result) == 0 {
+		return DBDomain{}, false
+	}
+	return result[0], true
+}
+
+// Schema and name of the data type as format_type prints it, the schema is
+// empty for the data types on the search path
+func splitTypeName(dt string) (string, string) {
+	var parts []string
+	var b strings.Builder
+	quoted := false
+	runes := []rune(dt)
+	for i := 0; i < len(runes); i++ {
+		switch c := runes[i]; {
+		case c == '"' && quoted && i+1 < len(runes) && runes[i+1] == '"':
+			b.WriteRune(c) // doubled quote is a literal quote
+			i++
+		case c == '"':
+			quoted = !quoted
+		case c == '.' && !quoted:
+			parts = append(parts, b.String())
+			b.Reset()
+		default:
+			b.WriteRune(c)
+		}
+	}
+	if len(parts) == 0 {
+		return "", b.String()
+	}
+	return parts[0], b.String()
+}
+
+// Get the distinct keys of the column of the referenced table
+func GetReferencedKeys(tab, column string) []string {
+	Debugf("Extracting the keys of the column %s from the referenced table %s", column, tab)
+	var result pg.Strings
+
+	// db connection
+	db := ConnectDB()
+	defer db.Close()
+
+	query := fmt.Sprintf(`SELECT DISTINCT "%[1]s"::text FROM %[2]s WHERE "%[1]s" IS NOT NULL`, column, tab)
+	_, err := db.Query(&result, query)
+	if err != nil {
+		Debugf("query: %s", query)
+		Fatalf("Error when extracting the keys of the referenced table %s, err: %v", tab, err)
+	}
+
+	return result
+}
+
+// Fraction of NULLs on the nullable columns of the table, extracted from a sample of
+// the existing rows, returns the total rows sampled along with the fractions
+func GetNullFractions(tab string, columns []string) (int, map[string]float64) {
+	Debugf("Extracting the fraction of NULLs on the existing rows of table %s", tab)
+	var total int
+	var nulls []string
+	for _, c := range columns {
+		nulls = append(nulls, fmt.Sprintf(`COALESCE(SUM(CASE WHEN "%[1]s" IS NULL THEN 1 ELSE 0 END), 0)`, c))
+	}
+	query := fmt.Sprintf(`SELECT COUNT(*), %s FROM (SELECT "%s" FROM %s LIMIT %d) a`,
+		strings.Join(nulls, ", "), strings.Join(columns, `", "`), tab, nullSampleSize)
+
+	// db connection
+	db := ConnectDB()
+	defer db.Close()
+
+	// Scan the count followed by the nulls of each column
+	counts := make([]int, len(columns))
+	values := []interface{}{&total}
+	for i := range counts {
+		values = append(values, &counts[i])
+	}
+	_, err := db.QueryOne(pg.Scan(values...), query)
+	if err != nil {
+		Debugf("query: %s", query)
+		Fatalf("Error when extracting the fraction of NULLs of table %s, err: %v", tab, err)
+	}
+
+	fractions := make(map[string]float64)
+	for i, c := range columns {
+		if total > 0 {
+			fractions[c] = float64(counts[i]) / float64(total)
+		}
+	}
+	return total, fractions
+}
+
+// Get the partition key of the parent and the partition bound of the
+// table, if the table is a partition
+func GetPartitionBound(tab string) []DBPartition {
+	Debugf("Extracting the partition bound of table %s", tab)
+	var result []DBPartition
+
+	// Declarative partitions are only available from postgres 10
+	if postgresVersionNum() < 100000 {
+		return result
+	}
+
+	// db connection
+	db := ConnectDB()
+	defer db.Close()
+
+	// The bound of the partition and of the partitions above it when its a
+	// partition of a partition, the partition itself first
+	query := `
+WITH RECURSIVE partitions AS 
+( 
+       SELECT c.oid, 
+              0 AS level 
+       FROM   pg_catalog.pg_class c 
+       WHERE  c.oid = '%s' :: regclass 
+              AND c.relispartition 
+       UNION ALL 
+       SELECT i.inhparent, 
+              p.level + 1 
+       FROM   partitions p 
+              JOIN pg_catalog.pg_inherits i 
+                ON i.inhrelid = p.oid 
+              JOIN pg_catalog.pg_class c 
+                ON c.oid = i.inhparent 
+       WHERE  c.relispartition ) 
+SELECT   pg_catalog.Pg_get_partkeydef(i.inhparent)      AS partkey, 
+         pg_catalog.Pg_get_expr(c.relpartbound, c.oid) AS bound, 
+         ARRAY(SELECT pg_catalog.Pg_get_expr(s.relpartbound, s.oid) 
+               FROM   pg_catalog.pg_inherits si 
+                      JOIN pg_catalog.pg_class s 
+                        ON s.oid = si.inhrelid 
+               WHERE  si.inhparent = i.inhparent 
+                      AND s.oid <> c.oid)             AS siblings 
+FROM     partitions p 
+         JOIN pg_catalog.pg_class c 
+           ON c.oid = p.oid 
+         JOIN pg_catalog.pg_inherits i 
+           ON i.inhrelid = c.oid 
+ORDER BY p.level 
+`
+	query = fmt.Sprintf(query, tab)
+	_, err := db.Query(&result, query)
+	if err != nil {
+		Debugf("query: %s", query)
+		Fatalf("Error when extracting the partition bound of table %s, err: %v", tab, err)
+	}
+
+	return result
+}
+
+// Get the row level security setting of the table and if the connecting
+// role is exempt from the policies of the table
+func GetRowSecurity(tab string) DBRowSecurity {
+	Debugf("Extracting the row level security of table %s", tab)
+	var result DBRowSecurity
+
+	// Row level security is only available from postgres 9.5
+	if postgresVersionNum() < 90500 {
+		return result
+	}
+
+	// db connection
+	db := ConnectDB()
+	defer db.Close()
+
+	query := `
+SELECT c.relrowsecurity                              AS enabled, 
+       c.relforcerowsecurity                         AS forced, 
+       pg_catalog.Pg_has_role(c.relowner, 'USAGE')   AS owner, 
+       r.rolsuper                                    AS superuser, 
+       r.rolbypassrls                                AS bypass 
+FROM   pg_catalog.pg_class c, 
+       pg_catalog.pg_roles r 
+WHERE  c.oid = '%s' :: regclass 
+       AND r.rolname = current_user 
+`
+	query = fmt.Sprintf(query, tab)
+	_, err := db.QueryOne(&result, query)
+	if err != nil {
+		Debugf("query: %s", query)
+		Fatalf("Error when extracting the row level security of table %s, err: %v", tab, err)
+	}
+
+	return result
+}
+
+// Get the distributed and reference tables of citus, none when the
+// database doesn't have the citus extension
+func GetCitusTables() []DBCitusTable {
+	Debugf("Extracting the citus distributed and reference tables")
+	var result []DBCitusTable
+
+	// to_regclass is only available from postgres 9.4
+	if postgresVersionNum() < 90400 {
+		return result
+	}
+
+	// db connection
+	db := ConnectDB()
+	defer db.Close()
+
+	var installed bool
+	_, err := db.QueryOne(pg.Scan(&installed), `SELECT to_regclass('pg_catalog.pg_dist_partition') IS NOT NULL`)
+	if err != nil || !installed {
+		return result
+	}
+
+	query := `
+SELECT '"' 
+       || n.nspname 
+       || '"."' 
+       || c.relname 
+       || '"'                                                    tablename, 
+       p.partmethod                                              method, 
+       CASE 
+         WHEN p.partmethod = 'n' THEN '' 
+         ELSE column_to_column_name(p.logicalrelid, p.partkey) 
+       END                                                       "column", 
+       (SELECT Count(*) 
+        FROM   pg_dist_shard s 
+        WHERE  s.logicalrelid = p.logicalrelid)                  shards 
+FROM   pg_dist_partition p 
+       JOIN pg_catalog.pg_class c 
+         ON c.oid = p.logicalrelid 
+       JOIN pg_catalog.pg_namespace n 
+         ON n.oid = c.relnamespace 
+`
+	_, err = db.Query(&result, query)
+	if err != nil {
+		Debugf("query: %s", query)
+		Fatalf("Error when extracting the citus tables, err: %v", err)
+	}
+
+	return result
+}
+
+// Refresh the statistics of the table
+func AnalyzeTable(tab string) {
+	Debugf("Analyzing the table %s", tab)
+	query := fmt.Sprintf("ANALYZE %s", tab)
+	_, err := ExecuteDB(query)
+	if err != nil {
+		Debugf("query: %s", query)
+		Fatalf("Error when analyzing the table %s, err: %v", tab, err)
+	}
+}
+
+// Get the null fraction and the distinct values of the columns of the table from pg_stats
+func GetColumnStats(schema, table string) []DBColumnStats {
+	Debugf("Extracting the column statistics of table %s.%s", schema, table)
+	var result []DBColumnStats
+
+	// db connection
+	db := ConnectDB()
+	defer db.Close()
+
+	query := `
+SELECT s.attname, 
+       s.null_frac, 
+       s.n_distinct, 
+       c.reltuples 
+FROM   pg_catalog.pg_stats s 
+       JOIN pg_catalog.pg_namespace n 
+         ON n.nspname = s.schemaname 
+       JOIN pg_catalog.pg_class c 
+         ON c.relnamespace = n.oid 
+            AND c.relname = s.tablename 
+WHERE  s.schemaname = '%s' 
+       AND s.tablename = '%s' 
+`
+	query = fmt.Sprintf(query, schema, table)
+	_, err := db.Query(&result, query)
+	if err != nil {
+		Debugf("query: %s", query)
+		Fatalf("Error when extracting the column statistics of table %s.%s, err: %v", schema, table, err)
+	}
+
+	return result
+}
+
+// Get the lowest and the highest value of the numeric column, false if
+// the column only has NULLs
+func GetColumnRange(tab, column string) (float64, float64, bool) {
+	Debugf("Extracting the range of the column %s of table %s", column, tab)
+	var min, max float64
+	var count int
+
+	// db connection
+	db := ConnectDB()
+	defer db.Close()
+
+	query := fmt.Sprintf(`SELECT count("%[1]s"), COALESCE(min("%[1]s"), 0)::float8, COALESCE(max("%[1]s"), 0)::float8 FROM %[2]s`,
+		column, tab)
+	_, err := db.QueryOne(pg.Scan(&count, &min, &max), query)
+	if err != nil {
+		Debugf("query: %s", query)
+		Fatalf("Error when extracting the range of the column %s of table %s, err: %v", column, tab, err)
+	}
+
+	return min, max, count > 0
+}
+
+// Get the highest value of the integer column, 0 when the table has no rows
+func GetColumnMax(tab, column string) int64 {
+	Debugf("Extracting the highest value of the column %s of table %s", column, tab)
+	var max int64
+
+	// db connection
+	db := ConnectDB()
+	defer db.Close()
+
+	query := fmt.Sprintf(`SELECT COALESCE(max("%s"), 0)::bigint FROM %s`, column, tab)
+	_, err := db.QueryOne(pg.Scan(&max), query)
+	if err != nil {
+		Debugf("query: %s", query)
+		Fatalf("Error when extracting the highest value of the column %s of table %s, err: %v", column, tab, err)
+	}
+
+	return max
+}
+
+// Version number of the postgres database i.e 90624 or 130002
+func postgresVersionNum() int {
+	serverVersionOnce.Do(func() {
+		db := ConnectDB()
+		defer db.Close()
+		query := "SELECT current_setting('server_version_num')::int"
+		_, err := db.QueryOne(pg.Scan(&serverVersionNum), query)
+		if err != nil {
+			Debugf("query: %s", query)
+			Fatalf("Error when extracting the version number of the database, err: %v", err)
+		}
+	})
+	return serverVersionNum
+}
+
+// Get the primary key columns of the table, in the order of the columns
+func GetPrimaryKeyColumns(tab string) []string {
+	Debugf("Extracting the primary key columns of table %s", tab)
+	var result pg.Strings
+
+	// db connection
+	db := ConnectDB()
+	defer db.Close()
+
+	query := fmt.Sprintf(`
+SELECT a.attname
+FROM   pg_index i
+       JOIN pg_attribute a
+         ON a.attrelid = i.indrelid
+            AND a.attnum = ANY(i.indkey)
+WHERE  i.indrelid = '%s'::regclass
+       AND i.indisprimary
+ORDER  BY a.attnum`, tab)
+	_, err := db.Query(&result, query)
+	if err != nil {
+		Debugf("query: %s", query)
+		Fatalf("Error when extracting the primary key columns of table %s, err: %v", tab, err)
+	}
+
+	return result
+}
+
+// Get the keys of the table, each key is the json array of the values of
+// the columns, sorted so the same rows always give the same list
+func GetPrimaryKeys(tab string, columns []string) []string {
+	Debugf("Extracting the primary keys of table %s", tab)
+	var result pg.Strings
+
+	// db connection
+	db := ConnectDB()
+	defer db.Close()
+
+	var values []string
+	for _, c := range columns {
+		values = append(values, fmt.Sprintf(`"%s"::text`, c))
+	}
+	query := fmt.Sprintf(`SELECT array_to_json(ARRAY[%[1]s])::text FROM %[2]s ORDER BY "%[3]s"`,
+		strings.Join(values, ", "), tab, strings.Join(columns, `", "`))
+	_, err := db.Query(&result, query)
+	if err != nil {
+		Debugf("query: %s", query)
+		Fatalf("Error when extracting the primary keys of table %s, err: %v", tab, err)
+	}
+
+	return result
+}
+
+// Get a few keys of the table, each key is the json array of the values of the columns
+func GetKeySample(tab string, columns []string, limit int) []string {
+	Debugf("Extracting a sample of the keys of table %s", tab)
+	var result pg.Strings
+
+	// db connection
+	db := ConnectDB()
+	defer db.Close()
+
+	var values []string
+	for _, c := range columns {
+		values = append(values, fmt.Sprintf(`"%s"::text`, c))
+	}
+	query := fmt.Sprintf(`SELECT array_to_json(ARRAY[%s])::text FROM %s LIMIT %d`,
+		strings.Join(values, ", "), tab, limit)
+	_, err := db.Query(&result, query)
+	if err != nil {
+		Debugf("query: %s", query)
+		Fatalf("Error when extracting a sample of the keys of table %s, err: %v", tab, err)
+	}
+
+	return result
+}
+
+// Get the parent and the child tables of the classic table inheritance
+// (INHERITS), the declarative partitions are left out
+func GetInheritance() []DBInheritance {
+	Debugf("Extracting the table inheritance hierarchies")
+	var result []DBInheritance
+
+	// db connection
+	db := ConnectDB()
+	defer db.Close()
+
+	query := `
+SELECT '"' 
+       || pn.nspname 
+       || '"."' 
+       || p.relname 
+       || '"' AS parent, 
+       '"' 
+       || cn.nspname 
+       || '"."' 
+       || c.relname 
+       || '"' AS child 
+FROM   pg_catalog.pg_inherits i 
+       JOIN pg_catalog.pg_class p 
+         ON p.oid = i.inhparent 
+       JOIN pg_catalog.pg_namespace pn 
+         ON pn.oid = p.relnamespace 
+       JOIN pg_catalog.pg_class c 
+         ON c.oid = i.inhrelid 
+       JOIN pg_catalog.pg_namespace cn 
+         ON cn.oid = c.relnamespace 
+WHERE  p.relkind = 'r' 
+`
+	_, err := db.Query(&result, query)
+	if err != nil {
+		Debugf("query: %s", query)
+		Fatalf("Error when extracting the table inheritance hierarchies, err: %v", err)
+	}
+
+	return result
+}
+
+// Condition of the child rows whose foreign key has no referenced row, the
+// keys with a NULL are not checked the same as MATCH SIMPLE
+func orphanedRowsCondition(reftab string, columns, refcolumns []string) string {
+	var notNull, match []string
+	for i, c := range columns {
+		notNull = append(notNull, fmt.Sprintf(`c."%s" IS NOT NULL`, c))
+		match = append(match, fmt.Sprintf(`p."%s" = c."%s"`, refcolumns[i], c))
+	}
+	return fmt.Sprintf(`%s AND NOT EXISTS (SELECT 1 FROM %s p WHERE %s)`,
+		strings.Join(notNull, " AND "), reftab, strings.Join(match, " AND "))
+}
+
+// Total rows of the table whose foreign key has no referenced row
+func CountOrphanedRows(tab, reftab string, columns, refcolumns []string) int {
+	var total int
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM %s c WHERE %s`, tab,
+		orphanedRowsCondition(reftab, columns, refcolumns))
+
+	// db connection
+	db := ConnectDB()
+	defer db.Close()
+
+	_, err := db.Query(pg.Scan(&total), query)
+	if err != nil {
+		Debugf("query: %s", query)
+		Fatalf("Error when counting the orphaned rows of table %s, err: %v", tab, err)
+	}
+
+	return total
+}
+
+// Get a sample of the foreign keys that has no referenced row
+func GetOrphanedKeys(tab, reftab string, columns, refcolumns []string, limit int) []string {
+	var result pg.Strings
+	var values []string
+	for _, c := range columns {
+		values = append(values, fmt.Sprintf(`c."%s"::text`, c))
+	}
+	query := fmt.Sprintf(`SELECT DISTINCT array_to_json(ARRAY[%s])::text FROM %s c WHERE %s LIMIT %d`,
+		strings.Join(values, ", "), tab, orphanedRowsCondition(reftab, columns, refcolumns), limit)
+
+	// db connection
+	db := ConnectDB()
+	defer db.Close()
+
+	_, err := db.Query(&result, query)
+	if err != nil {
+		Debugf("query: %s", query)
+		Fatalf("Error when extracting the orphaned keys of table %s, err: %v", tab, err)
+	}
+
 	return result
 }
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Write the rows of the table to a sql file of INSERT statements, for the
+// systems that can't COPY. Each INSERT has up to --batch-size rows, the
+// values are string literals that the database casts to the column types
+type sqlWriter struct {
+	file   *os.File
+	w      *bufio.Writer
+	insert string // the INSERT up to the VALUES
+	batch  []string
+
+	// The file as its listed on the manifest
+	manifest manifestFile
+}
+
+// Create the sql file <dir>/<schema>.<table><part>.sql, the part is empty
+// unless the table is split to shards
+func newSQLWriter(t TableCollection, tab string, col []string, part string) (*sqlWriter, error) {
+	err := os.MkdirAll(cmdOptions.OutputSQL, os.ModePerm)
+	if err != nil {
+		return nil, fmt.Errorf("creating the output directory: %v", err)
+	}
+	filename := filepath.Join(cmdOptions.OutputSQL, fmt.Sprintf("%s.%s%s.sql", t.Schema, t.Table, part))
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, fmt.Errorf("creating the sql file: %v", err)
+	}
+	Debugf("Writing the mock data of table %s.%s to the sql file %s", t.Schema, t.Table, filename)
+	return &sqlWriter{file: file, w: bufio.NewWriter(file),
+		insert:   fmt.Sprintf(`INSERT INTO %s("%s") VALUES`, tab, strings.Join(col, `","`)),
+		manifest: manifestFile{File: filename, Schema: t.Schema, Table: t.Table, Format: "sql", Columns: col}}, nil
+}
+
+// Add the row to the INSERT, its written once it has --batch-size rows
+func (w *sqlWriter) Write(data []string) error {
+	values := make([]string, len(data))
+	for i, d := range data {
+		values[i] = sqlLiteral(d)
+	}
+	w.batch = append(w.batch, "("+strings.Join(values, ", ")+")")
+	w.manifest.Rows++
+	if len(w.batch) >= cmdOptions.BatchSize {
+		return w.flush()
+	}
+	return nil
+}
+
+// Write the INSERT of the rows of the batch
+func (w *sqlWriter) flush() error {
+	if len(w.batch) == 0 {
+		return nil
+	}
+	_, err := w.w.WriteString(w.insert + "\n" + strings.Join(w.batch, ",\n") + ";\n")
+	w.batch = w.batch[:0]
+	if err != nil {
+		return fmt.Errorf("writing to the sql file: %v", err)
+	}
+	return nil
+}
+
+// Write the last INSERT and close the file
+func (w *sqlWriter) Close() error {
+	if err := w.flush(); err != nil {
+		w.file.Close()
+		return err
+	}
+	if err := w.w.Flush(); err != nil {
+		w.file.Close()
+		return fmt.Errorf("completing the sql file: %v", err)
+	}
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	addManifestFile(w.manifest)
+	return nil
+}
+
+// The value as a sql string literal with its single quotes doubled, the
+// NULLs are the NULL keyword
+func sqlLiteral(value string) string {
+	if value == nullValue {
+		return "NULL"
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,22 @@
+package main
+
+import "testing"
+
+func TestSplitTypeName(t *testing.T) {
+	for _, tc := range []struct {
+		dt, schema, name string
+	}{
+		{"mood", "", "mood"},
+		{"myschema.mood", "myschema", "mood"},
+		{`"Mood"`, "", "Mood"},
+		{`"My Schema"."Mood"`, "My Schema", "Mood"},
+		{`myschema."Mood"`, "myschema", "Mood"},
+		{`"my.schema"."mo.od"`, "my.schema", "mo.od"},
+		{`"say ""hi"""`, "", `say "hi"`},
+		{`"a""b"."c""d"`, `a"b`, `c"d`},
+	} {
+		if schema, name := splitTypeName(tc.dt); schema != tc.schema || name != tc.name {
+			t.Errorf("splitTypeName(%s) = %q, %q, want %q, %q", tc.dt, schema, name, tc.schema, tc.name)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+func init() {
+	registerBackend("sqlite", sqliteBackend{})
+}
+
+// The SQLite backend, the database is the file of --file and its tables
+// are on the main schema
+type sqliteBackend struct{}
+
+// Length or precision of the declared type i.e varchar(20) or decimal(10,2)
+var sqliteTypmod = regexp.MustCompile(`\(\s*(\d+)\s*(,\s*(\d+)\s*)?\)`)
+
+// Open the database file, it has to exist with its tables. The writers of
+// the tables wait on each other for the lock of the file
+func (sqliteBackend) open() (*sql.DB, error) {
+	if IsStringEmpty(cmdOptions.File) {
+		return nil, fmt.Errorf("the database file is not set, give it with --file")
+	}
+	return openSqlite(fmt.Sprintf("file:%s?mode=rw&_foreign_keys=0&_busy_timeout=60000", cmdOptions.File))
+}
+
+func (sqliteBackend) versionQuery() string {
+	return "SELECT sqlite_version()"
+}
+
+func (sqliteBackend) defaultSchema(db *sql.DB) (string, error) {
+	return "main", nil
+}
+
+// Tables of the schema, the internal sqlite_ ones are left out
+func (b sqliteBackend) tables(db *sql.DB, schema string) ([]DBTables, error) {
+	if IsStringEmpty(schema) {
+		schema = "main"
+	}
+	rows, err := db.Query(fmt.Sprintf(`SELECT name FROM %s.sqlite_master
+WHERE type = 'table' AND name NOT LIKE 'sqlite\_%%' ESCAPE '\' ORDER BY name`, b.quote(schema)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var tables []DBTables
+	for rows.Next() {
+		t := DBTables{Schema: schema}
+		if err := rows.Scan(&t.Table); err != nil {
+			return nil, err
+		}
+		tables = append(tables, t)
+	}
+	return tables, rows.Err()
+}
+
+// Columns of the table, the generated columns are left out. The INTEGER
+// PRIMARY KEY is the rowid of the table, so its left to the database the
+// same as an AUTOINCREMENT one
+func (b sqliteBackend) columns(db *sql.DB, t DBTables) ([]DBColumns, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA %s.table_xinfo(%s)", b.quote(t.Schema), b.quote(t.Table)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	tab := GenerateTableName(t.Table, t.Schema)
+	var columns []DBColumns
+	var rowid string
+	keys := 0
+	for rows.Next() {
+		var cid, notNull, pk, hidden int
+		var name, declared string
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &name, &declared, &notNull, &dflt, &pk, &hidden); err != nil {
+			return nil, err
+		}
+		if pk > 0 {
+			keys++
+			if strings.EqualFold(strings.TrimSpace(declared), "integer") {
+				rowid = name
+			}
+		}
+		if hidden != 0 {
+			continue
+		}
+		c := DBColumns{Column: name, IsNullable: notNull == 0 && pk == 0, Sequence: dflt.String}
+		c.Datatype = sqliteDatatype(tab, name, strings.ToLower(strings.TrimSpace(declared)))
+		c.MaxLength = declaredLength(c.Datatype)
+		columns = append(columns, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i := range columns {
+		if keys == 1 && columns[i].Column == rowid {
+			columns[i].Sequence = autoIncrement
+		}
+	}
+	return columns, nil
+}
+
+// The postgres data type the column of the declared SQLite type is generated
+// as. The well known names are kept, the rest go by the affinity rules of
+// SQLite. The booleans are 0 or 1 with an int_range rule unless the column
+// has a rule of its own
+func sqliteDatatype(tab, column, declared string) string {
+	typmod := sqliteTypmod.FindStringSubmatch(declared)
+	name := strings.TrimSpace(sqliteTypmod.ReplaceAllString(declared, ""))
+	switch name {
+	case "boolean", "bool":
+		if columnRule(tab, column) == nil {
+			min, max := int64(0), int64(1)
+			columnRules[ruleKey(tab, column)] = &ColumnRule{Column: column, Min: &min, Max: &max}
+		}
+		return "smallint"
+	case "tinyint", "smallint", "int2":
+		return "smallint"
+	case "bigint", "int8", "unsigned big int":
+		return "bigint"
+	case "real", "float", "float4":
+		return "real"
+	case "double", "double precision", "float8":
+		return "double precision"
+	case "decimal", "numeric":
+		if len(typmod) > 0 && typmod[3] != "" {
+			return fmt.Sprintf("numeric(%s,%s)", typmod[1], typmod[3])
+		} else if len(typmod) > 0 {
+			return fmt.Sprintf("numeric(%s,0)", typmod[1])
+		}
+		return "numeric"
+	case "char", "character", "nchar", "native character":
+		if len(typmod) > 0 {
+			return fmt.Sprintf("character(%s)", typmod[1])
+		}
+		return "character(1)"
+	case "varchar", "character varying", "nvarchar", "varying character":
+		if len(typmod) > 0 {
+			return fmt.Sprintf("character varying(%s)", typmod[1])
+		}
+		return "text"
+	case "date":
+		return "date"
+	case "datetime", "timestamp":
+		return "timestamp without time zone"
+	case "time":
+		return "time without time zone"
+	case "uuid":
+		return "uuid"
+	case "json":
+		return "json"
+	}
+	switch {
+	case strings.Contains(name, "int"):
+		return "integer"
+	case name == "", strings.Contains(name, "char"), strings.Contains(name, "clob"), strings.Contains(name, "text"),
+		strings.Contains(name, "blob"):
+		return "text"
+	case strings.Contains(name, "real"), strings.Contains(name, "floa"), strings.Contains(name, "doub"):
+		return "double precision"
+	}
+	return "numeric"
+}
+
+// Columns of the unique indexes of one column, the primary key included
+// unless its the rowid. The partial indexes are left out
+func (b sqliteBackend) singleColumnKeys(db *sql.DB, t DBTables) ([]string, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA %s.index_list(%s)", b.quote(t.Schema), b.quote(t.Table)))
+	if err != nil {
+		return nil, err
+	}
+	var indexes []string
+	for rows.Next() {
+		var seq, unique, partial int
+		var name, origin string
+		if err := rows.Scan(&seq, &name, &unique, &origin, &partial); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if unique == 1 && partial == 0 {
+			indexes = append(indexes, name)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, index := range indexes {
+		var columns []string
+		rows, err := db.Query(fmt.Sprintf("PRAGMA %s.index_info(%s)", b.quote(t.Schema), b.quote(index)))
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var seqno, cid int
+			var name sql.NullString // NULL on an expression
+			if err := rows.Scan(&seqno, &cid, &name); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			columns = append(columns, name.String)
+		}
+		rows.Close()
+		if len(columns) == 1 && columns[0] != "" {
+			keys = append(keys, columns[0])
+		}
+	}
+	return keys, nil
+}
+
+func (sqliteBackend) quote(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
+
+func (sqliteBackend) insertDefaults(tab string) string {
+	return fmt.Sprintf("INSERT INTO %s DEFAULT VALUES", backendTableName(tab))
+}
+
+// The bundled SQLite allows 32766 bind parameters, the older ones only 999
+func (sqliteBackend) maxParams() int {
+	return 32766
+}
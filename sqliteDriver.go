@@ -0,0 +1,13 @@
+// +build cgo
+
+package main
+
+import (
+	"database/sql"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Open the database file with the sqlite driver, it needs cgo
+func openSqlite(dsn string) (*sql.DB, error) {
+	return sql.Open("sqlite3", dsn)
+}
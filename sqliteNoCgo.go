@@ -0,0 +1,15 @@
+// +build !cgo
+
+package main
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// The sqlite driver needs cgo, the builds without it i.e the cross compiled
+// ones of build.sh can't open the database file
+func openSqlite(dsn string) (*sql.DB, error) {
+	return nil, fmt.Errorf("this build of %s has no sqlite support, the sqlite driver needs cgo, "+
+		"build it with CGO_ENABLED=1 and a C compiler", programName)
+}
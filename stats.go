@@ -0,0 +1,127 @@
+package main
+
+import (
+	"math"
+	"strconv"
+)
+
+// What the generators were configured to produce for a column
+type columnExpectation struct {
+	NullFrac    float64
+	MaxDistinct int  // 0 when the distinct values are not bounded
+	HasRange    bool // numeric range of the values
+	Min, Max    float64
+}
+
+// Rows that ANALYZE samples with the default statistics target
+const analyzeSampleRows = 30000
+
+// Analyze the loaded tables and compare the statistics of their columns
+// with what the generators were configured to produce
+func VerifyStats(tables []TableCollection) {
+	Info("Verifying the statistics of the mocked tables")
+	var checked, mismatches int
+	bar := StartProgressBar("Verifying the statistics of the tables", len(tables))
+	for _, t := range tables {
+		tab := GenerateTableName(t.Table, t.Schema)
+		if StringContains(tab, skippedTab) {
+			bar.Add(1)
+			continue
+		}
+		AnalyzeTable(tab)
+		stats := make(map[string]DBColumnStats)
+		for _, s := range GetColumnStats(t.Schema, t.Table) {
+			stats[s.Attname] = s
+		}
+		for _, c := range t.Columns {
+			s, ok := stats[c.Column]
+			if !ok {
+				Debugf("No statistics for the column %s of table %s", c.Column, tab)
+				continue
+			}
+			checked++
+			mismatches += verifyColumnStats(tab, c, s)
+		}
+		bar.Add(1)
+	}
+
+	if mismatches > 0 {
+		Warnf("Found %d discrepancies on the statistics of %d columns, the statistics include "+
+			"the rows that existed before the load and the rows changed when fixing the constraints", mismatches, checked)
+	} else {
+		Infof("Statistics of the %d columns match what the generators were configured to produce", checked)
+	}
+}
+
+// Compare the statistics of the column to the expectation, returns the
+// number of discrepancies found
+func verifyColumnStats(tab string, c DBColumns, s DBColumnStats) int {
+	e := columnExpectations(tab, c)
+	var mismatches int
+
+	// The null fraction is sampled, so allow the sampling error
+	n := math.Min(float64(rowsToMock(tab)), analyzeSampleRows)
+	tolerance := 3*math.Sqrt(e.NullFrac*(1-e.NullFrac)/n) + 0.01
+	if math.Abs(s.NullFrac-e.NullFrac) > tolerance {
+		Warnf("Column %s of table %s has %.2f%% NULLs, expected %.2f%%",
+			c.Column, tab, s.NullFrac*100, e.NullFrac*100)
+		mismatches++
+	}
+
+	// Negative n_distinct is the fraction of the rows of the table
+	distinct := s.NDistinct
+	if distinct < 0 {
+		distinct = -distinct * s.Reltuples
+	}
+	if e.MaxDistinct > 0 && math.Round(distinct) > float64(e.MaxDistinct) {
+		Warnf("Column %s of table %s has about %.0f distinct values, expected at most %d",
+			c.Column, tab, distinct, e.MaxDistinct)
+		mismatches++
+	}
+
+	if e.HasRange {
+		min, max, ok := GetColumnRange(tab, c.Column)
+		if ok && (min < e.Min || max > e.Max) {
+			Warnf("Column %s of table %s has the values within [%v, %v], expected within [%v, %v]",
+				c.Column, tab, min, max, e.Min, e.Max)
+			mismatches++
+		}
+	}
+	return mismatches
+}
+
+// The expectation of the column based on its rule, partition bound and NULLs setting
+func columnExpectations(tab string, c DBColumns) columnExpectation {
+	e := columnExpectation{NullFrac: nullProbability(tab, c)}
+	numeric := StringHasPrefix(c.Datatype, intKeywords) || StringHasPrefix(c.Datatype, floatKeywords) ||
+		StringHasPrefix(c.Datatype, []string{"numeric"})
+	if isArray, _ := isDataTypeAnArray(c.Datatype); isArray {
+		numeric = false
+	}
+
+	if rule := columnRule(tab, c.Column); rule != nil && rule.histogram != nil {
+		h := rule.histogram
+		if len(h.Values) > 0 {
+			e.MaxDistinct = len(h.Values)
+		} else if numeric {
+			e.HasRange, e.Min, e.Max = true, h.Buckets[0][0], h.Buckets[0][1]
+			for _, b := range h.Buckets {
+				e.Min, e.Max = math.Min(e.Min, b[0]), math.Max(e.Max, b[1])
+			}
+		}
+		return e
+	}
+
+	if b := partitionKeyBound(tab, c.Column); b != nil {
+		if b.Strategy == "list" {
+			e.MaxDistinct = len(b.Values)
+			return e
+		}
+		from, errFrom := strconv.ParseFloat(b.From, 64)
+		to, errTo := strconv.ParseFloat(b.To, 64)
+		if numeric && errFrom == nil && errTo == nil {
+			e.HasRange, e.Min, e.Max = true, from, to
+		}
+	}
+	return e
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// A column whose values add up to a target on each group of rows
+type sumRule struct {
+	Column   int   // index of the column on the row
+	GroupBy  []int // index of the columns of the group key
+	Target   int64 // target in the units of the smallest step of the column
+	Decimals int
+}
+
+// The sum rules of the table, the rows of these tables are all built before
+// they are loaded so the groups can be adjusted
+func sumRules(t TableCollection, tab string) ([]sumRule, error) {
+	var rules []sumRule
+	for i, c := range t.Columns {
+		rule := columnRule(tab, c.Column)
+		if rule == nil || rule.SumTarget == nil {
+			continue
+		}
+		decimals, ok := numericDecimals(c.Datatype)
+		if !ok {
+			return nil, fmt.Errorf("sum_target of the column %s is only supported on the integer and "+
+				"numeric columns, got %s", c.Column, c.Datatype)
+		}
+		s := sumRule{Column: i, Decimals: decimals,
+			Target: int64(math.Round(*rule.SumTarget * math.Pow10(decimals)))}
+		for _, g := range rule.GroupBy {
+			j := -1
+			for k, gc := range t.Columns {
+				if gc.Column == g {
+					j = k
+				}
+			}
+			if j < 0 {
+				return nil, fmt.Errorf("group_by column %s of the column %s is not a generated column of table %s",
+					g, c.Column, tab)
+			}
+			s.GroupBy = append(s.GroupBy, j)
+		}
+		rules = append(rules, s)
+	}
+	return rules, nil
+}
+
+// Decimals the integer and numeric columns can store, false for the rest
+func numericDecimals(dt string) (int, bool) {
+	switch {
+	case strings.HasSuffix(dt, "[]"):
+	case StringHasPrefix(dt, intKeywords):
+		return 0, true
+	case dt == "real" || dt == "double precision" || dt == "numeric":
+		return 3, true // the precision of the random values
+	case strings.HasPrefix(dt, "numeric("):
+		if !strings.Contains(dt, ",") {
+			return 0, true
+		}
+		_, decimals, err := FloatPrecision(dt)
+		return decimals, err == nil
+	}
+	return 0, false
+}
+
+// Adjust the rows so the column of each sum rule adds up to its target on
+// every group. The values are scaled to the target keeping their proportions
+// and the rounding left over goes to the last row of the group
+func adjustSums(rules []sumRule, rows [][]string) {
+	for _, s := range rules {
+		groups := make(map[string][]int)
+		var order []string
+		for i, data := range rows {
+			var key []string
+			for _, g := range s.GroupBy {
+				key = append(key, data[g])
+			}
+			k := strings.Join(key, "\x00")
+			if _, ok := groups[k]; !ok {
+				order = append(order, k)
+			}
+			groups[k] = append(groups[k], i)
+		}
+
+		scale := math.Pow10(s.Decimals)
+		for _, k := range order {
+			members := groups[k]
+			values := make([]float64, len(members))
+			var total float64
+			for i, m := range members {
+				v, err := strconv.ParseFloat(rows[m][s.Column], 64)
+				if err != nil {
+					v = 0 // NULLs count as zero and are replaced
+				}
+				values[i] = math.Abs(v) // the parts have the sign of the target
+				total += values[i]
+			}
+			var sum int64
+			for i, m := range members {
+				var units int64
+				switch {
+				case i == len(members)-1:
+					units = s.Target - sum
+				case total == 0:
+					units = s.Target / int64(len(members))
+				default:
+					units = int64(math.Round(values[i] / total * float64(s.Target)))
+				}
+				sum += units
+				rows[m][s.Column] = strconv.FormatFloat(float64(units)/scale, 'f', s.Decimals, 64)
+			}
+		}
+	}
+}
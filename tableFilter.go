@@ -0,0 +1,75 @@
+package main
+
+import (
+	"regexp"
+)
+
+// The patterns of --include and --exclude, matched against the
+// schema.table names of the tables to mock
+var includePatterns, excludePatterns []*regexp.Regexp
+
+// Compile the patterns of --include and --exclude, an invalid pattern
+// is reported before anything touches the database
+func LoadTableFilters() {
+	for _, f := range []struct {
+		flag     string
+		values   []string
+		patterns *[]*regexp.Regexp
+	}{{"include", cmdOptions.Include, &includePatterns}, {"exclude", cmdOptions.Exclude, &excludePatterns}} {
+		for _, value := range f.values {
+			p, err := regexp.Compile(value)
+			if err != nil {
+				Fatalf("Argument Error: invalid --%s pattern \"%s\", err: %v", f.flag, value, err)
+			}
+			*f.patterns = append(*f.patterns, p)
+		}
+	}
+}
+
+// Keep the tables whose schema.table matches one of the --include patterns,
+// all of them when there is none, and that match none of the --exclude
+// patterns. The exclude wins when a table matches both
+func filterTables(tables []DBTables) []DBTables {
+	if len(includePatterns) == 0 && len(excludePatterns) == 0 {
+		return tables
+	}
+	matched := make(map[*regexp.Regexp]int)
+	var kept []DBTables
+	for _, t := range tables {
+		name := t.Schema + "." + t.Table
+		included := len(includePatterns) == 0
+		for _, p := range includePatterns {
+			if p.MatchString(name) {
+				matched[p]++
+				included = true
+			}
+		}
+		excluded := false
+		for _, p := range excludePatterns {
+			if p.MatchString(name) {
+				matched[p]++
+				excluded = true
+			}
+		}
+		if !included || excluded {
+			Debugf("Table %s is filtered out by the --include / --exclude patterns", name)
+			continue
+		}
+		kept = append(kept, t)
+	}
+	for _, f := range []struct {
+		flag     string
+		patterns []*regexp.Regexp
+	}{{"include", includePatterns}, {"exclude", excludePatterns}} {
+		for _, p := range f.patterns {
+			if matched[p] == 0 {
+				Warnf("The --%s pattern \"%s\" matches none of the %d tables", f.flag, p, len(tables))
+				continue
+			}
+			Infof("The --%s pattern \"%s\" matches %d tables", f.flag, p, matched[p])
+		}
+	}
+	Infof("Mocking %d of the %d tables, %d are filtered out by the --include / --exclude patterns",
+		len(kept), len(tables), len(tables)-len(kept))
+	return kept
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"strings"
+)
+
+// Order the tables so the tables they refer to, by their foreign keys or
+// the references rules of their columns, are loaded before them, otherwise
+// the order is kept. The tables that refer to each other are reported and
+// keep their order, their keys are fixed once all the tables are loaded
+func orderByDependencies(tables []TableCollection) []TableCollection {
+	position := make(map[string]int)
+	for i, t := range tables {
+		position[GenerateTableName(t.Table, t.Schema)] = i
+	}
+	return orderByEdges(tables, planEdges(tables, position))
+}
+
+// Order the tables on the edges from the tables to the tables they refer
+// to, the edges to the tables that are not mocked are left out
+func orderByEdges(tables []TableCollection, edges []planEdge) []TableCollection {
+	position := make(map[string]bool)
+	for _, t := range tables {
+		position[GenerateTableName(t.Table, t.Schema)] = true
+	}
+	deps := make(map[string][]string)
+	for _, e := range edges {
+		if _, ok := position[e.Reftable]; !ok || StringContains(e.Reftable, deps[e.Table]) {
+			continue
+		}
+		if e.Reftable == e.Table {
+			Warnf("Table %s refers to itself, the keys of %s are fixed after the load", e.Table, e.Column)
+			continue
+		}
+		deps[e.Table] = append(deps[e.Table], e.Reftable)
+	}
+
+	var ordered []TableCollection
+	placed := make(map[string]bool)
+	ready := func(tab string) bool {
+		for _, d := range deps[tab] {
+			if !placed[d] {
+				return false
+			}
+		}
+		return true
+	}
+	for len(ordered) < len(tables) {
+		next := ""
+		for _, t := range tables {
+			tab := GenerateTableName(t.Table, t.Schema)
+			if !placed[tab] && ready(tab) {
+				next = tab
+				break
+			}
+		}
+		cycle := []string{next}
+		if IsStringEmpty(next) {
+			cycle = dependencyCycle(tables, deps, placed)
+			Warnf("Tables %s refer to each other, they are loaded in their original order",
+				strings.Join(cycle, ", "))
+		}
+		for _, t := range tables {
+			if tab := GenerateTableName(t.Table, t.Schema); StringContains(tab, cycle) {
+				ordered = append(ordered, t)
+				placed[tab] = true
+			}
+		}
+	}
+	return ordered
+}
+
+// The tables of the first cycle among the tables not yet placed, all of them
+// wait on another table. Following the dependencies from any of them ends in
+// a cycle, whose tables are the ones that also lead back to where it starts
+func dependencyCycle(tables []TableCollection, deps map[string][]string, placed map[string]bool) []string {
+	waiting := func(tab string) string {
+		for _, d := range deps[tab] {
+			if !placed[d] {
+				return d
+			}
+		}
+		return ""
+	}
+	start := ""
+	for _, t := range tables {
+		if tab := GenerateTableName(t.Table, t.Schema); !placed[tab] {
+			start = tab
+			break
+		}
+	}
+	seen := make(map[string]bool)
+	for !seen[start] {
+		seen[start] = true
+		start = waiting(start)
+	}
+
+	// The tables that reach the start and are reached from it
+	reaches := func(from, to string) bool {
+		visited := map[string]bool{from: true}
+		stack := []string{from}
+		for len(stack) > 0 {
+			tab := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, d := range deps[tab] {
+				if d == to {
+					return true
+				}
+				if !placed[d] && !visited[d] {
+					visited[d] = true
+					stack = append(stack, d)
+				}
+			}
+		}
+		return false
+	}
+	var cycle []string
+	for _, t := range tables {
+		tab := GenerateTableName(t.Table, t.Schema)
+		if !placed[tab] && (tab == start || reaches(start, tab) && reaches(tab, start)) {
+			cycle = append(cycle, tab)
+		}
+	}
+	return cycle
+}
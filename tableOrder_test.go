@@ -0,0 +1,64 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Tables of the public schema by their names
+func testTables(names ...string) []TableCollection {
+	var tables []TableCollection
+	for _, n := range names {
+		tables = append(tables, TableCollection{DBTables: DBTables{Schema: "public", Table: n}})
+	}
+	return tables
+}
+
+// Edge from the table to the table it refers to
+func testEdge(tab, reftab string) planEdge {
+	return planEdge{GenerateTableName(tab, "public"), "id", GenerateTableName(reftab, "public"), "id", "test"}
+}
+
+func tableNames(tables []TableCollection) []string {
+	var names []string
+	for _, t := range tables {
+		names = append(names, t.Table)
+	}
+	return names
+}
+
+func TestOrderByEdges(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		tables []string
+		edges  []planEdge
+		want   []string
+	}{
+		{"chain", []string{"a", "b", "c"}, []planEdge{testEdge("a", "b"), testEdge("b", "c")}, []string{"c", "b", "a"}},
+		{"no edges keep the order", []string{"a", "b", "c"}, nil, []string{"a", "b", "c"}},
+		{"table not mocked", []string{"a", "b"}, []planEdge{testEdge("a", "x")}, []string{"a", "b"}},
+		{"self reference", []string{"a", "b"}, []planEdge{testEdge("a", "a"), testEdge("a", "b")}, []string{"b", "a"}},
+		{"cycle keeps its order", []string{"a", "b", "c"},
+			[]planEdge{testEdge("a", "b"), testEdge("b", "a"), testEdge("a", "c")}, []string{"c", "a", "b"}},
+	} {
+		got := tableNames(orderByEdges(testTables(tc.tables...), tc.edges))
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: orderByEdges = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestDependencyCycle(t *testing.T) {
+	tables := testTables("a", "b", "c", "d")
+	deps := map[string][]string{
+		GenerateTableName("a", "public"): {GenerateTableName("b", "public")},
+		GenerateTableName("b", "public"): {GenerateTableName("c", "public")},
+		GenerateTableName("c", "public"): {GenerateTableName("b", "public")},
+		GenerateTableName("d", "public"): {GenerateTableName("a", "public")},
+	}
+	got := dependencyCycle(tables, deps, map[string]bool{})
+	want := []string{GenerateTableName("b", "public"), GenerateTableName("c", "public")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dependencyCycle = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-pg/pg/v10"
+)
+
+// The transaction the rows of a table are loaded in with --transactional,
+// the rows show up once the whole table is loaded and a failure leaves
+// the table as it was before the load
+type tableTransaction struct {
+	*pg.Tx
+	conn dbConnection
+	tab  string
+}
+
+// Open the transaction of the table on the connection of the writer
+func beginTableTransaction(w *copyWriter) error {
+	tx, err := w.db.Begin()
+	if err != nil {
+		w.db.Close()
+		return fmt.Errorf("starting the transaction of table %s: %v", w.tab, err)
+	}
+	Debugf("Loading the table %s in a transaction", w.tab)
+	if cmdOptions.Truncate {
+		exec := func(stmt string) error {
+			_, err := tx.Exec(stmt)
+			return err
+		}
+		if err := truncateTable(exec, w.tab, true); err != nil {
+			tx.Rollback()
+			w.db.Close()
+			return err
+		}
+	}
+	w.db = &tableTransaction{Tx: tx, conn: w.db, tab: w.tab}
+	return nil
+}
+
+// Commit the rows of the table and close the connection
+func (t *tableTransaction) Close() error {
+	defer t.conn.Close()
+	if err := t.Commit(); err != nil {
+		return fmt.Errorf("committing the transaction: %v", err)
+	}
+	return nil
+}
+
+// Already within the transaction, the function runs on it
+func (t *tableTransaction) RunInTransaction(ctx context.Context, fn func(*pg.Tx) error) error {
+	return fn(t.Tx)
+}
+
+// Roll back the rows of the table and close the connection
+func (t *tableTransaction) rollback() {
+	defer t.conn.Close()
+	if err := t.Rollback(); err != nil {
+		Errorf("Error when rolling back the transaction of table %s, err: %v", t.tab, err)
+		return
+	}
+	Debugf("Rolled back the rows of table %s", t.tab)
+}
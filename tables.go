@@ -74,13 +74,11 @@ func createTable(n int) {
 // Mock provided tables
 func MockTables() {
 	Infof("Starting mocking of table: %s", cmdOptions.Tab.FakeTablesRows)
-	whereClause := generateWhereClause()
-	tableList := dbExtractTables(whereClause)
-	MockTable(tableList)
+	MockTable(activeBackend.tables("", strings.Split(cmdOptions.Tab.FakeTablesRows, ",")))
 }
 
 // Generate the where clause from the table list provided
-func generateWhereClause() string {
+func generateWhereClause(t []string) string {
 	Debug("Generating the where condition for the table list")
 
 	// where condition syntax
@@ -88,7 +86,6 @@ func generateWhereClause() string {
 
 	// Loop and generate the where condition
 	var w []string
-	t := strings.Split(cmdOptions.Tab.FakeTablesRows, ",")
 	for _, table := range t {
 
 		// if there is no schema then add in public the default schema
@@ -107,5 +104,19 @@ func generateWhereClause() string {
 		// generate the in clause
 		w = append(w, fmt.Sprintf("'%s.%s'", s[0], s[1]))
 	}
+
+	// The partitioned tables have no rows of their own, they are loaded
+	// through the partitions under them
+	if GreenplumOrPostgres == "postgres" {
+		whereClause = `AND ((n.nspname || '.' || c.relname) IN (%[1]s)
+       OR EXISTS (WITH RECURSIVE ancestors AS (
+                    SELECT inhparent FROM pg_catalog.pg_inherits WHERE inhrelid = c.oid
+                    UNION
+                    SELECT i.inhparent FROM pg_catalog.pg_inherits i JOIN ancestors a ON i.inhrelid = a.inhparent)
+                  SELECT 1 FROM ancestors a
+                         JOIN pg_catalog.pg_class p ON p.oid = a.inhparent
+                         JOIN pg_catalog.pg_namespace pn ON pn.oid = p.relnamespace
+                  WHERE p.relkind = 'p' AND (pn.nspname || '.' || p.relname) IN (%[1]s)))`
+	}
 	return fmt.Sprintf(whereClause, strings.Join(w, ","))
 }
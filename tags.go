@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Tags of the tags generator, unless the rule has its own vocabulary
+var tagVocabulary = []string{"new", "featured", "popular", "sale", "clearance", "limited-edition", "bestseller",
+	"eco-friendly", "handmade", "imported", "organic", "vegan", "gluten-free", "premium", "budget", "gift",
+	"seasonal", "summer", "winter", "spring", "autumn", "holiday", "outdoor", "indoor", "kids", "men", "women",
+	"unisex", "sports", "fitness", "travel", "home", "kitchen", "garden", "office", "tech", "gaming", "music",
+	"books", "art", "design", "vintage", "retro", "minimal", "luxury", "urgent", "bug", "feature", "question",
+	"documentation", "backend", "frontend", "security", "performance", "wontfix", "duplicate", "help-wanted",
+	"good-first-issue", "news", "opinion", "tutorial", "review", "announcement", "how-to", "video", "podcast"}
+
+func init() {
+	registerGenerator("tags",
+		"Labels from a tag vocabulary without duplicates within the value, an array for the array columns "+
+			"else comma separated (min_items: <n>, max_items: <n>, vocabulary: [<tag>, ...])",
+		buildTags)
+}
+
+// Validate the options of the tags rule
+func validateTagsRule(c *ColumnRule) error {
+	vocabulary := tagVocabulary
+	if len(c.Vocabulary) > 0 {
+		vocabulary = c.Vocabulary
+	}
+	if c.MinItems == 0 && c.MaxItems == 0 {
+		c.MinItems, c.MaxItems = 1, 5
+	}
+	if c.MinItems < 0 || c.MaxItems < c.MinItems {
+		return fmt.Errorf("tags min_items %d and max_items %d should be 0 <= min_items <= max_items",
+			c.MinItems, c.MaxItems)
+	}
+	if c.MaxItems > len(vocabulary) {
+		return fmt.Errorf("tags max_items %d is more than the %d tags of the vocabulary", c.MaxItems, len(vocabulary))
+	}
+	seen := make(map[string]bool)
+	for _, t := range c.Vocabulary {
+		if seen[t] {
+			return fmt.Errorf("tag \"%s\" is repeated on the vocabulary", t)
+		}
+		seen[t] = true
+	}
+	return nil
+}
+
+// Tags generator, the tags are picked without replacement so a value
+// never has the same tag twice
+func buildTags(ctx *generatorContext) (interface{}, error) {
+	vocabulary := tagVocabulary
+	if len(ctx.Rule.Vocabulary) > 0 {
+		vocabulary = ctx.Rule.Vocabulary
+	}
+	n := RandomInt(ctx.Rule.MinItems, ctx.Rule.MaxItems+1)
+	var tags []string
+	for _, i := range r.Perm(len(vocabulary))[:n] {
+		tags = append(tags, vocabulary[i])
+	}
+
+	if isItArray, _ := isDataTypeAnArray(ctx.Column.Datatype); isItArray {
+		for i, t := range tags {
+			tags[i] = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(t) + `"`
+		}
+		return fmt.Sprintf("{%s}", strings.Join(tags, ",")), nil
+	}
+	return fitText(ctx.Column.Datatype, strings.Join(tags, ","))
+}
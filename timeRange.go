@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Zone the timestamptz values are written in, set by --time-zone
+var outputZone *time.Location
+
+func init() {
+	registerGenerator("time_range",
+		"Dates and timestamps between \"from\" and \"to\" (yyyy-mm-dd or yyyy-mm-dd hh:mm:ss, both included), "+
+			"the timestamptz ones are in the --time-zone, set by the from or to when there is no generator",
+		buildTimeRange)
+}
+
+// Load the zone of the timestamptz values, the values are left to the zone
+// of the database session when its not set
+func LoadOutputZone() {
+	if IsStringEmpty(cmdOptions.TimeZone) {
+		return
+	}
+	zone, err := time.LoadLocation(cmdOptions.TimeZone)
+	if err != nil {
+		Fatalf("Argument Error: unknown --time-zone \"%s\": %v", cmdOptions.TimeZone, err)
+	}
+	outputZone = zone
+}
+
+// Validate the window of the time_range rule, a date without a time as the
+// to includes the whole day. The window is the wall time, it's placed on the
+// zone when the value is built
+func validateTimeRangeRule(c *ColumnRule) error {
+	now := referenceTime()
+	c.from = time.Date(now.Year()+fromYear, now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	c.to = time.Date(now.Year()+toYear, now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	for _, d := range []struct {
+		value    string
+		date     *time.Time
+		endOfDay bool
+	}{{c.From, &c.from, false}, {c.To, &c.to, true}} {
+		if IsStringEmpty(d.value) {
+			continue
+		}
+		t, err := time.Parse("2006-01-02 15:04:05", d.value)
+		if err != nil {
+			if t, err = time.Parse("2006-01-02", d.value); err != nil {
+				return fmt.Errorf("invalid time \"%s\", expected yyyy-mm-dd or yyyy-mm-dd hh:mm:ss", d.value)
+			}
+			if d.endOfDay {
+				t = t.Add(24*time.Hour - time.Microsecond)
+			}
+		}
+		*d.date = t
+	}
+	if c.to.Before(c.from) {
+		return fmt.Errorf("time_range from %s is after to %s", c.from.Format("2006-01-02 15:04:05"),
+			c.to.Format("2006-01-02 15:04:05"))
+	}
+	return nil
+}
+
+// Time range generator, a uniform wall time of the window to the microsecond
+// written as the COPY literal of the data type. The dates are picked by day,
+// the timestamps get the digits of their precision and the timestamptz ones
+// the offset of the --time-zone (UTC when its not set)
+func buildTimeRange(ctx *generatorContext) (interface{}, error) {
+	dt := ctx.Column.Datatype
+	rule := ctx.Rule
+	if strings.HasSuffix(dt, "[]") {
+		return "", fmt.Errorf("time_range generator doesn't support the arrays, got %s", dt)
+	}
+	switch {
+	case dt == "date":
+		days := int64(rule.to.Sub(rule.from.Truncate(24*time.Hour)) / (24 * time.Hour))
+		return rule.from.AddDate(0, 0, int(r.Int63n(days+1))).Format("2006-01-02"), nil
+	case strings.HasPrefix(dt, "timestamp"):
+		wall := rule.from.Add(time.Duration(r.Int63n(int64(rule.to.Sub(rule.from)/time.Microsecond)+1)) *
+			time.Microsecond)
+		layout := "2006-01-02 15:04:05"
+		if decimals := timestampPrecision(dt); decimals > 0 {
+			layout += "." + strings.Repeat("0", decimals)
+		}
+		if !strings.HasSuffix(dt, "with time zone") {
+			return wall.Truncate(precisionUnit(dt)).Format(layout), nil
+		}
+		zone := outputZone
+		if zone == nil {
+			zone = time.UTC
+		}
+		instant := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(),
+			wall.Nanosecond(), zone)
+		return instant.Truncate(precisionUnit(dt)).Format(layout + "-07:00"), nil
+	}
+	return "", fmt.Errorf("time_range generator only supports the date and timestamp columns, got %s", dt)
+}
+
+// Fractional digits of the seconds of the timestamp, 6 unless it has a precision
+func timestampPrecision(dt string) int {
+	if strings.HasPrefix(dt, "timestamp(") {
+		return findTimeStampDecimal(dt)
+	}
+	return 6
+}
+
+// Smallest step of the timestamp on its precision
+func precisionUnit(dt string) time.Duration {
+	unit := time.Second
+	for i := timestampPrecision(dt); i > 0; i-- {
+		unit /= 10
+	}
+	return unit
+}
+
+// Add the offset of the --time-zone to the timestamptz value, the wall time
+// is read on the zone. Without the zone the value is left to the session
+func withOutputZone(value string) string {
+	if outputZone == nil || len(value) < 19 {
+		return value
+	}
+	t, err := time.ParseInLocation("2006-01-02 15:04:05", value[:19], outputZone)
+	if err != nil {
+		return value
+	}
+	return value + t.Format("-07:00")
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildTimeRangeOneDay(t *testing.T) {
+	rule := &ColumnRule{From: "2024-02-29", To: "2024-02-29"}
+	if err := validateTimeRangeRule(rule); err != nil {
+		t.Fatalf("validateTimeRangeRule: %v", err)
+	}
+	defer func(zone *time.Location) { outputZone = zone }(outputZone)
+	for _, tc := range []struct {
+		dt, layout string
+		zone       *time.Location
+	}{
+		{"date", "2006-01-02", nil},
+		{"timestamp without time zone", "2006-01-02 15:04:05.000000", nil},
+		{"timestamp(3) without time zone", "2006-01-02 15:04:05.000", nil},
+		{"timestamp(0) without time zone", "2006-01-02 15:04:05", nil},
+		{"timestamp with time zone", "2006-01-02 15:04:05.000000-07:00", nil},
+		{"timestamp(2) with time zone", "2006-01-02 15:04:05.00-07:00", time.FixedZone("IST", 5*3600+1800)},
+	} {
+		outputZone = tc.zone
+		ctx := &generatorContext{Table: "public.t", Column: DBColumns{Column: "c", Datatype: tc.dt}, Rule: rule}
+		for i := 0; i < 200; i++ {
+			v, err := buildTimeRange(ctx)
+			if err != nil {
+				t.Fatalf("buildTimeRange(%s): %v", tc.dt, err)
+			}
+			value := v.(string)
+			parsed, err := time.Parse(tc.layout, value)
+			if err != nil || parsed.Format(tc.layout) != value {
+				t.Fatalf("buildTimeRange(%s) = %q, want the layout %s", tc.dt, value, tc.layout)
+			}
+			if !strings.HasPrefix(value, "2024-02-29") {
+				t.Fatalf("buildTimeRange(%s) = %q, want it on 2024-02-29", tc.dt, value)
+			}
+			if tc.zone != nil && !strings.HasSuffix(value, "+05:30") {
+				t.Fatalf("buildTimeRange(%s) = %q, want the offset of the zone", tc.dt, value)
+			}
+			if strings.HasSuffix(tc.dt, "with time zone") && tc.zone == nil && !strings.HasSuffix(value, "+00:00") {
+				t.Fatalf("buildTimeRange(%s) = %q, want UTC without a zone", tc.dt, value)
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+// Rows the tables had before the load, queried once per table
+var existingRows = make(map[string]int)
+
+// Rows still missing to reach the target, the table is skipped when
+// it already has as many rows as the target
+func topUpRows(tab string, target int) int {
+	existing, ok := existingRows[tab]
+	if !ok {
+		existing = TotalRows(tab)
+		existingRows[tab] = existing
+		Debugf("Table %s has %d rows, the target of --top-up-to is %d", tab, existing, target)
+	}
+	if existing >= target {
+		return 0
+	}
+	return target - existing
+}
+
+// Report the rows added to each table to reach the target, the skipped
+// and failed tables are already reported
+func topUpReport(tables []TableCollection) {
+	if cmdOptions.TopUpTo <= 0 {
+		return
+	}
+	for _, t := range tables {
+		tab := GenerateTableName(t.Table, t.Schema)
+		if StringContains(tab, skippedTab) || StringContains(tab, failedTab) {
+			continue
+		}
+		added := rowsToMock(tab)
+		if added == 0 {
+			Infof("Table %s already had %d rows, no rows added", tab, existingRows[tab])
+			continue
+		}
+		Infof("Table %s topped up from %d to %d rows, %d rows added", tab, existingRows[tab],
+			existingRows[tab]+added, added)
+	}
+}
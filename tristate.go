@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+)
+
+func init() {
+	registerGenerator("tristate",
+		"Nullable boolean with the true / false / NULL split of the weights (weights: [<true>, <false>, <null>])",
+		buildTriState)
+}
+
+// Validate the weights of the tri-state rule
+func validateTriStateRule(c *ColumnRule) error {
+	if len(c.Weights) != 3 {
+		return fmt.Errorf("tristate generator needs the 3 weights of true, false and NULL, got %d", len(c.Weights))
+	}
+	for _, w := range c.Weights {
+		if w < 0 {
+			return fmt.Errorf("tristate weights cannot be negative, got %v", w)
+		}
+	}
+	if c.Weights[0]+c.Weights[1] <= 0 {
+		return fmt.Errorf("tristate weights of true and false cannot both be 0")
+	}
+	return nil
+}
+
+// Fraction of NULLs of the tri-state rule, the NULLs are injected along
+// with the rest of the NULLs so the NOT NULL columns never get them
+func (c *ColumnRule) triStateNullFraction() float64 {
+	return c.Weights[2] / (c.Weights[0] + c.Weights[1] + c.Weights[2])
+}
+
+// Tri-state generator, the NULLs are already taken care of so its only
+// the split between true and false
+func buildTriState(ctx *generatorContext) (interface{}, error) {
+	w := ctx.Rule.Weights
+	return r.Float64()*(w[0]+w[1]) < w[0], nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+)
+
+// Empty the table before its loaded, so the runs against the same database
+// end with the rows of the last run. The TRUNCATE falls back to a DELETE
+// when its refused i.e the table is referenced by the foreign keys of the
+// tables that are not mocked. Within a transaction the failed TRUNCATE is
+// rolled back to a savepoint, else it would abort the whole transaction
+func truncateTable(exec func(stmt string) error, tab string, inTransaction bool) error {
+	name := activeBackend.tableName(tab)
+	Debugf("Removing the existing rows of table %s", tab)
+	if inTransaction {
+		if err := exec("SAVEPOINT mock_truncate"); err != nil {
+			return fmt.Errorf("truncating the table: %v", err)
+		}
+	}
+	err := exec(fmt.Sprintf("TRUNCATE TABLE %s", name))
+	if err == nil {
+		return nil
+	}
+	Debugf("Unable to truncate the table %s, deleting its rows instead, err: %v", tab, err)
+	if inTransaction {
+		if err := exec("ROLLBACK TO SAVEPOINT mock_truncate"); err != nil {
+			return fmt.Errorf("truncating the table: %v", err)
+		}
+	}
+	if err := exec(fmt.Sprintf("DELETE FROM %s", name)); err != nil {
+		return fmt.Errorf("deleting the existing rows of the table: %v", err)
+	}
+	return nil
+}
+
+// Run the statement on the database of the run, within the single
+// transaction when there is one
+func executeStatement(stmt string) error {
+	return activeBackend.exec(stmt)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Run truncateTable on a fake database that records the statements and
+// rejects those starting with one of the prefixes
+func fakeTruncate(inTransaction bool, reject ...string) ([]string, error) {
+	var stmts []string
+	exec := func(stmt string) error {
+		stmts = append(stmts, stmt)
+		for _, r := range reject {
+			if strings.HasPrefix(stmt, r) {
+				return errors.New("rejected")
+			}
+		}
+		return nil
+	}
+	err := truncateTable(exec, `"public"."orders"`, inTransaction)
+	return stmts, err
+}
+
+func TestTruncateTable(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		inTransaction bool
+		reject        []string
+		want          []string
+		fails         bool
+	}{
+		{"truncated", false, nil,
+			[]string{`TRUNCATE TABLE "public"."orders"`}, false},
+		{"truncated in the transaction", true, nil,
+			[]string{"SAVEPOINT mock_truncate", `TRUNCATE TABLE "public"."orders"`}, false},
+		{"deleted", false, []string{"TRUNCATE"},
+			[]string{`TRUNCATE TABLE "public"."orders"`, `DELETE FROM "public"."orders"`}, false},
+		{"deleted in the transaction", true, []string{"TRUNCATE"},
+			[]string{"SAVEPOINT mock_truncate", `TRUNCATE TABLE "public"."orders"`,
+				"ROLLBACK TO SAVEPOINT mock_truncate", `DELETE FROM "public"."orders"`}, false},
+		{"delete rejected", false, []string{"TRUNCATE", "DELETE"},
+			[]string{`TRUNCATE TABLE "public"."orders"`, `DELETE FROM "public"."orders"`}, true},
+		{"savepoint rejected", true, []string{"SAVEPOINT"},
+			[]string{"SAVEPOINT mock_truncate"}, true},
+		{"rollback rejected", true, []string{"TRUNCATE", "ROLLBACK"},
+			[]string{"SAVEPOINT mock_truncate", `TRUNCATE TABLE "public"."orders"`,
+				"ROLLBACK TO SAVEPOINT mock_truncate"}, true},
+	} {
+		stmts, err := fakeTruncate(tc.inTransaction, tc.reject...)
+		if !reflect.DeepEqual(stmts, tc.want) {
+			t.Errorf("%s: ran %q, want %q", tc.name, stmts, tc.want)
+		}
+		if (err != nil) != tc.fails {
+			t.Errorf("%s: err = %v, want an error %v", tc.name, err, tc.fails)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// Data types that replace the detected data type of the columns, the
+// generation sees the override instead of the type on the catalog
+var typeOverrides = make(map[string]string)
+
+// Read the type overrides file, each line is "<schema>.<table>.<column> <data type>"
+// i.e "public.orders.amount numeric(10,2)", the empty lines and the lines
+// starting with # are skipped
+func LoadTypeOverrides() {
+	Infof("Loading the data type overrides from the file: %s", cmdOptions.ColumnsFromFile)
+	file, err := os.Open(cmdOptions.ColumnsFromFile)
+	if err != nil {
+		Fatalf("Error reading the data type overrides file %s, err: %v", cmdOptions.ColumnsFromFile, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if IsStringEmpty(line) || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			Fatalf("Invalid line %d of the data type overrides file %s, expected \"<schema>.<table>.<column> <data type>\"",
+				n, cmdOptions.ColumnsFromFile)
+		}
+		tab, column, err := parseReference(fields[0])
+		if err != nil {
+			Fatalf("Invalid line %d of the data type overrides file %s, err: %v", n, cmdOptions.ColumnsFromFile, err)
+		}
+		typeOverrides[ruleKey(tab, column)] = strings.Join(fields[1:], " ")
+	}
+	if err := scanner.Err(); err != nil {
+		Fatalf("Error reading the data type overrides file %s, err: %v", cmdOptions.ColumnsFromFile, err)
+	}
+	Debugf("Loaded the data type overrides of %d columns", len(typeOverrides))
+}
+
+// Replace the detected data types of the columns by their overrides
+func applyTypeOverrides(tab string, columns []DBColumns) {
+	for i, c := range columns {
+		if dt, ok := typeOverrides[ruleKey(tab, c.Column)]; ok {
+			Debugf("Column %s of table %s is generated as %s instead of %s", c.Column, tab, dt, c.Datatype)
+			columns[i].Datatype = dt
+			columns[i].MaxLength = declaredLength(dt)
+		}
+	}
+}
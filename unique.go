@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"math/bits"
+	"strings"
+	"sync"
+)
+
+var (
+	// Largest values of the integer data types, the unique values are within 1 and them
+	uniqueIntMax = map[string]uint64{"smallint": 1<<15 - 1, "integer": 1<<31 - 1, "bigint": 1<<63 - 1, "oid": 1<<32 - 1}
+
+	// Counters of the columns using the unique generator
+	uniqueCounters = make(map[string]uint64)
+	uniqueMutex    sync.Mutex
+)
+
+func init() {
+	registerGenerator("unique",
+		"Unique values from a per column counter mixed with --seed, identical across the runs with the same seed "+
+			"(start: <n> to skip the first n values)",
+		buildUnique)
+}
+
+// Next value of the counter of the column, the counter is shared by the
+// workers loading the table
+func nextUniqueCounter(key string) uint64 {
+	uniqueMutex.Lock()
+	defer uniqueMutex.Unlock()
+	n := uniqueCounters[key]
+	uniqueCounters[key] = n + 1
+	return n
+}
+
+// Mix the value, its a bijection on the 64 bit values so different
+// inputs never collide (the splitmix64 finalizer)
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// Permute n within [0, size), its the affine map n*a+b mod size with the
+// multiplier a coprime to the size, so the values of n below size never collide
+func permute(n, size, seed uint64) uint64 {
+	a := mix64(seed)%size | 1
+	for gcd(a, size) != 1 {
+		a += 2
+	}
+	b := mix64(seed+1) % size
+	hi, lo := bits.Mul64(n%size, a)
+	v := bits.Rem64(hi, lo, size)
+	s, carry := bits.Add64(v, b, 0)
+	if carry != 0 || s >= size {
+		s -= size
+	}
+	return s
+}
+
+// Greatest common divisor
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// Unique generator
+func buildUnique(ctx *generatorContext) (interface{}, error) {
+	key := ruleKey(ctx.Table, ctx.Column.Column)
+	n := nextUniqueCounter(key) + uint64(ctx.Rule.Start)
+	seed := uint64(cmdOptions.Seed) ^ mix64(uint64(len(key)))
+	for _, c := range key {
+		seed = mix64(seed ^ uint64(c))
+	}
+	dt := ctx.Column.Datatype
+
+	switch {
+	case StringHasPrefix(dt, intKeywords):
+		max := uniqueIntMax[strings.Fields(dt)[0]]
+		if n >= max {
+			return "", fmt.Errorf("all the %d unique values of the data type %s are used", max, dt)
+		}
+		return permute(n, max, seed) + 1, nil
+	case strings.HasPrefix(dt, "uuid"):
+		var u uuid.UUID
+		hi, lo := mix64(^seed), mix64(n^seed)
+		for i := 0; i < 8; i++ {
+			u[i], u[8+i] = byte(hi>>(56-8*i)), byte(lo>>(56-8*i))
+		}
+		return u.String(), nil
+	case isTextDatatype(dt):
+		value := fmt.Sprintf("%016x", mix64(n^seed))
+		if strings.HasPrefix(dt, "character") {
+			l, err := CharLen(dt)
+			if err != nil {
+				return "", err
+			}
+			if l < len(value) {
+				return "", fmt.Errorf("the column of length %d is too short for the unique values of length %d", l, len(value))
+			}
+		}
+		return value, nil
+	}
+	return "", fmt.Errorf("data type %s is not supported, use an integer, uuid or text column", dt)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Attempts at a value of the unique column not generated before
+const uniqueValueTries = 100
+
+// Values already generated for the column, shared by its workers
+type seenValues struct {
+	mutex  sync.Mutex
+	values map[string]struct{}
+}
+
+// The columns of a single column unique key whose values are kept distinct
+// across the run, by rule key
+var uniqueColumns = make(map[string]*seenValues)
+
+// Mark the columns of the single column keys, the duplicates of their random
+// values would only be found when the unique index is recreated. The counters
+// and the unique generator never repeat, so they're left alone
+func markUniqueColumns(tab string, columns []DBColumns) {
+	keys := activeBackend.singleColumnKeys(tab)
+	for _, c := range columns {
+		if !StringContains(c.Column, keys) || isSequentialKey(tab, c.Column) || isLeftToSequence(tab, c) {
+			continue
+		}
+		if rule := columnRule(tab, c.Column); rule != nil && rule.generatorName() == "unique" {
+			continue
+		}
+		Debugf("The values of the key column %s of table %s are kept unique", c.Column, tab)
+		uniqueColumns[ruleKey(tab, c.Column)] = &seenValues{values: make(map[string]struct{})}
+	}
+}
+
+// Is the column kept unique across the run
+func isUniqueColumn(tab, column string) bool {
+	_, ok := uniqueColumns[ruleKey(tab, column)]
+	return ok
+}
+
+// Build the value of the unique column, the values seen before on any batch
+// or worker of the run are generated again. Its an error when the column runs
+// out of new values
+func buildUniqueColumnData(tab string, c DBColumns, row *rowContext,
+	build func(string, DBColumns, *rowContext) (interface{}, error)) (interface{}, error) {
+	seen := uniqueColumns[ruleKey(tab, c.Column)]
+	for tries := 0; tries < uniqueValueTries; tries++ {
+		value, err := build(tab, c, row)
+		if err != nil {
+			return value, err
+		}
+		key := fmt.Sprintf("%v", value)
+		seen.mutex.Lock()
+		_, dup := seen.values[key]
+		if !dup {
+			seen.values[key] = struct{}{}
+		}
+		seen.mutex.Unlock()
+		if !dup {
+			return value, nil
+		}
+	}
+	seen.mutex.Lock()
+	defer seen.mutex.Unlock()
+	return "", fmt.Errorf("no new unique value after %d tries, the values of the column look exhausted "+
+		"after %d distinct values", uniqueValueTries, len(seen.values))
+}
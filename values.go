@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func init() {
+	registerGenerator("values",
+		"Values picked from a list, inline (values: [<value>, ...]) or a file of one value per line "+
+			"(values_file: <path>), uniformly or by the weights of the values (weights: [<weight>, ...]), "+
+			"set by the values when there is no generator",
+		buildValues)
+}
+
+// Validate the values rule and read its values file
+func validateValuesRule(c *ColumnRule) error {
+	if len(c.Values) > 0 && !IsStringEmpty(c.ValuesFile) {
+		return fmt.Errorf("values and values_file cannot be used together, choose one")
+	}
+	if !IsStringEmpty(c.ValuesFile) {
+		values, err := readValuesFile(c.ValuesFile)
+		if err != nil {
+			return err
+		}
+		c.Values = values
+	}
+	if len(c.Values) == 0 {
+		return fmt.Errorf("values generator needs the values or the values_file")
+	}
+	if len(c.Weights) == 0 {
+		return nil
+	}
+	if len(c.Weights) != len(c.Values) {
+		return fmt.Errorf("values generator needs a weight for each of the %d values, got %d",
+			len(c.Values), len(c.Weights))
+	}
+	for _, w := range c.Weights {
+		if w < 0 {
+			return fmt.Errorf("weights of the values cannot be negative, got %v", w)
+		}
+	}
+	c.cumulative = CumulativeWeights(c.Weights)
+	if c.cumulative[len(c.cumulative)-1] <= 0 {
+		return fmt.Errorf("weights of the values cannot all be 0")
+	}
+	return nil
+}
+
+// Read the values file, one value per line and the empty lines are skipped
+func readValuesFile(filename string) ([]string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading values file: %v", err)
+	}
+	defer file.Close()
+
+	var values []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if v := strings.TrimRight(scanner.Text(), "\r"); !IsStringEmpty(strings.TrimSpace(v)) {
+			values = append(values, v)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading values file %s: %v", filename, err)
+	}
+	return values, nil
+}
+
+// Values generator, the NULLs are injected along with the rest of the NULLs
+func buildValues(ctx *generatorContext) (interface{}, error) {
+	if ctx.Rule.cumulative != nil {
+		return ctx.Rule.Values[RandomWeightedIndex(ctx.Rule.cumulative)], nil
+	}
+	return ctx.Rule.Values[r.Intn(len(ctx.Rule.Values))], nil
+}
+
+// The values without their repeats, in the order they first appear
+func distinctStrings(values []string) []string {
+	var distinct []string
+	seen := make(map[string]bool)
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			distinct = append(distinct, v)
+		}
+	}
+	return distinct
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"strings"
+)
+
+// Orphaned keys of each foreign key shown on the report
+const orphanedKeySamples = 5
+
+// Count the rows of each removed foreign key that has no referenced row,
+// before the constraints are restored. Its a report of what the fix of the
+// foreign keys has to repair, so the bugs of the generators show up early
+func VerifyForeignKeys() {
+	fks := savedConstraints["FOREIGN"]
+	Infof("Verifying the %d foreign keys for the orphaned rows before restoring them", len(fks))
+	var violated int
+	for _, con := range fks {
+		fk := getForeignKeyColumns(con)
+		reftab := qualifiedTableName(fk.Reftable)
+		columns := constraintColumns(fk.Column)
+		refcolumns := constraintColumns(fk.Refcolumn)
+		if len(columns) != len(refcolumns) {
+			Warnf("Unable to verify the foreign key %s of table %s, the columns don't match the referenced columns",
+				strings.TrimSpace(con.column), fk.Table)
+			continue
+		}
+		total := CountOrphanedRows(fk.Table, reftab, columns, refcolumns)
+		if total == 0 {
+			Debugf("Foreign key %s(%s) -> %s(%s) has no orphaned rows", fk.Table, fk.Column, reftab, fk.Refcolumn)
+			continue
+		}
+		violated++
+		Warnf("Foreign key %s(%s) -> %s(%s) has %d orphaned rows, i.e the keys %s", fk.Table, fk.Column, reftab,
+			fk.Refcolumn, total, strings.Join(GetOrphanedKeys(fk.Table, reftab, columns, refcolumns, orphanedKeySamples), ", "))
+	}
+	if violated == 0 {
+		Infof("All the %d foreign keys have their referenced rows", len(fks))
+		return
+	}
+	Warnf("%d of the %d foreign keys have orphaned rows, they are fixed when the constraints are restored",
+		violated, len(fks))
+}
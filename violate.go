@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Name of the column of the side table that labels the violation of the row
+const violationLabelColumn = "mock_violation"
+
+// Generate rows that deliberately violate the constraint picked with
+// --violate-constraint, the rows go to a side table next to the table
+// so the table itself stays valid
+func ViolateConstraint(tables []TableCollection) {
+	tab, name, err := parseReference(cmdOptions.ViolateConstraint)
+	if err != nil {
+		Fatalf("Argument Error: --violate-constraint %v", err)
+	}
+	var t *TableCollection
+	for i := range tables {
+		if GenerateTableName(tables[i].Table, tables[i].Schema) == tab {
+			t = &tables[i]
+		}
+	}
+	if t == nil {
+		Fatalf("Table %s of the constraint %s is not one of the mocked tables", tab, name)
+	}
+	contype, key := findConstraint(tab, name)
+	sideTab := GenerateTableName(fmt.Sprintf("%s_%s", t.Table, violationLabelColumn), t.Schema)
+	label := fmt.Sprintf("violates %s of %s", name, tab)
+	Warnf("NEGATIVE TEST DATA: generating %d rows that violate the constraint %s of table %s into the table %s",
+		cmdOptions.ViolationRows, name, tab, sideTab)
+
+	// Build the valid rows first and then break the constraint columns
+	var rows [][]string
+	for i := 0; i < cmdOptions.ViolationRows; i++ {
+		data, err := buildRow(*t, tab)
+		if err != nil {
+			Fatalf("Error when building the violating rows of table %s: %v", tab, err)
+		}
+		rows = append(rows, data)
+	}
+	switch contype {
+	case "p", "u":
+		duplicateKeys(*t, tab, key, rows)
+	case "f":
+		danglingReferences(*t, key, rows)
+	default:
+		Fatalf("Only the primary key, unique and foreign key constraints can be violated, %s is a check constraint", name)
+	}
+
+	// The side table has the columns of the table without its constraints
+	_, err = ExecuteDB(fmt.Sprintf("DROP TABLE IF EXISTS %[1]s; CREATE TABLE %[1]s (LIKE %[2]s); "+
+		"ALTER TABLE %[1]s ADD COLUMN %[3]s text", sideTab, tab, violationLabelColumn))
+	if err != nil {
+		Fatalf("Error when creating the side table %s of the violating rows, err: %v", sideTab, err)
+	}
+	var col []string
+	for _, c := range t.Columns {
+		col = append(col, c.Column)
+	}
+	w := newCopyWriter(sideTab, append(col, violationLabelColumn))
+	for _, data := range rows {
+		if err := w.Write(append(data, label)); err != nil {
+			w.Close()
+			Fatalf("Error when loading the violating rows to the table %s: %v", sideTab, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		Fatalf("Error when loading the violating rows to the table %s: %v", sideTab, err)
+	}
+	Warnf("NEGATIVE TEST DATA: loaded %d rows labeled \"%s\" in the column %s of the table %s, "+
+		"copy them to %s to test the error handling", len(rows), label, violationLabelColumn, sideTab, tab)
+}
+
+// The type and the definition of the constraint of the table
+func findConstraint(tab, name string) (string, string) {
+	for _, contype := range constraints {
+		for _, con := range GetPGConstraintDDL(contype) {
+			if con.Tablename == tab && con.Constraintname == name {
+				return contype, con.Constraintkey
+			}
+		}
+	}
+	Fatalf("Constraint %s not found on table %s", name, tab)
+	return "", ""
+}
+
+// Split the column list of a constraint i.e (a, "B")
+func constraintColumns(cols string) []string {
+	var columns []string
+	for _, c := range strings.Split(TrimPrefixNSuffix(strings.TrimSpace(cols), "(", ")"), ",") {
+		columns = append(columns, strings.Trim(strings.TrimSpace(c), `"`))
+	}
+	return columns
+}
+
+// Index of the column on the table
+func columnIndex(t TableCollection, column string) int {
+	for i, c := range t.Columns {
+		if c.Column == column {
+			return i
+		}
+	}
+	Fatalf("Column %s of the constraint is not one of the generated columns of table %s", column, t.Table)
+	return -1
+}
+
+// Give all the rows the key of an existing row, or of the first row when
+// the table is empty
+func duplicateKeys(t TableCollection, tab, key string, rows [][]string) {
+	cols, err := ColExtractor(key, `\(([^\[\]]*)\)`)
+	if err != nil {
+		Fatalf("Unable to extract the columns of the constraint: %v", err)
+	}
+	columns := constraintColumns(cols)
+	var values []string
+	if sample := GetKeySample(tab, columns, 1); len(sample) > 0 {
+		if err := json.Unmarshal([]byte(sample[0]), &values); err != nil {
+			Fatalf("Error when reading the key %s of table %s, err: %v", sample[0], tab, err)
+		}
+	} else {
+		for _, c := range columns {
+			values = append(values, rows[0][columnIndex(t, c)])
+		}
+	}
+	for _, data := range rows {
+		for i, c := range columns {
+			data[columnIndex(t, c)] = values[i]
+		}
+	}
+}
+
+// Point the foreign key of the rows to keys that doesn't exist on the
+// referenced table
+func danglingReferences(t TableCollection, key string, rows [][]string) {
+	fk := getForeignKeyColumns(constraint{GenerateTableName(t.Table, t.Schema), key})
+	columns := constraintColumns(fk.Column)
+	refcolumns := constraintColumns(fk.Refcolumn)
+	existing := make(map[string]bool)
+	for _, k := range GetReferencedKeys(qualifiedTableName(fk.Reftable), refcolumns[0]) {
+		existing[k] = true
+	}
+	for _, data := range rows {
+		for j, c := range columns {
+			i := columnIndex(t, c)
+			for tries := 0; ; tries++ {
+				v, err := BuildData(t.Columns[i].Datatype)
+				if err != nil {
+					Fatalf("Error when building the foreign key column %s: %v", c, err)
+				}
+				data[i] = fmt.Sprintf("%v", v)
+				// Its enough for the first column to miss the referenced keys
+				if j > 0 || !existing[data[i]] {
+					break
+				}
+				if tries > maxLoop*100 {
+					Fatalf("Unable to generate a value of the column %s that is not on the referenced table", c)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"github.com/go-pg/pg/v10"
+	"sync"
+	"time"
+)
+
+// Connections opened ahead of the loading, the COPY of the first tables
+// takes them instead of opening new ones
+var warmConnections chan *pg.DB
+
+// Open and validate a connection for each worker before the loading
+// starts, so the authentication or TLS errors fail the run right away
+// and the workers don't all connect at once
+func WarmupConnections() {
+	n := maxConnections()
+	Infof("Warming up %d database connections", n)
+	start := time.Now()
+	warmConnections = make(chan *pg.DB, n)
+	errs := make(chan error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			db := openDB()
+			if err := db.Ping(context.Background()); err != nil {
+				db.Close()
+				errs <- err
+				return
+			}
+			warmConnections <- db
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	if err, failed := <-errs; failed {
+		Fatalf("Error when warming up the database connections, err: %v", err)
+	}
+	Infof("Warmed up %d database connections in %v", n, time.Since(start).Round(time.Millisecond))
+}
+
+// A warmed up connection if there are any left, else a new connection
+func takeConnection() dbConnection {
+	select {
+	case db := <-warmConnections:
+		return db
+	default:
+		return ConnectDB()
+	}
+}
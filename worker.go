@@ -1,9 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/go-pg/pg/v10"
+	"github.com/schollz/progressbar/v3"
+	"math"
 	"strings"
+	"sync"
+	"time"
 )
 
 type TableCollection struct {
@@ -12,34 +17,117 @@ type TableCollection struct {
 }
 
 var (
-	skippedTab []string
-	delimiter  = "$"
-	oneColumnTable []string
-	progressBarMsg = "Mocking Table %s"
+	skippedTab       []string
+	skipReasons      = make(map[string]string) // of the tables skipped for other than their data types
+	unsupportedTypes = make(map[string]string) // of the rest of the skipped tables
+	delimiter        = "$"
+	oneColumnTable   []string
+	progressBarMsg   = "Mocking Table %s"
+	rowsPerTable     = make(map[string]int)
+	jitteredRows     = make(map[string]int)
+
+	// The tables are loaded concurrently with --parallel
+	tableListMutex sync.Mutex
+	rowsMutex      sync.Mutex
 )
 
 func MockTable(tables []DBTables) {
+	// Keep the tables picked by the --include and --exclude patterns
+	tables = filterTables(tables)
+
+	// Pick the level of the inheritance hierarchies to load
+	tables = applyInheritance(tables)
+
+	// The row counts given for tables that are not mocked
+	checkRowsPerTable(tables)
+
+	// On interactive mode let the user pick the tables and the rows
+	if cmdOptions.Interactive && len(tables) > 0 {
+		tables = InteractiveTableSelector(tables)
+	}
+
+	// The report covers the loads, not the runs that only print the tables
+	if !IsStringEmpty(cmdOptions.Report) && !cmdOptions.Explain && !cmdOptions.DryRun &&
+		IsStringEmpty(cmdOptions.ProbeTypes) {
+		startReport()
+		defer WriteReport()
+	}
+
 	// Check if there is any rows on the table list, if yes then start
 	// the loading process
 	totalTables := len(tables)
 	if totalTables > 0 {
 		Debugf("Total number of tables to mock: %d", totalTables)
-		tableMocker(tables)
-		if !cmdOptions.IgnoreConstraint {
-			FixConstraints()
+		if cmdOptions.Explain {
+			ExplainPlan(columnExtractor(tables))
+			return
+		}
+		if cmdOptions.DryRun {
+			DryRun(columnExtractor(tables))
+			return
+		}
+		if !IsStringEmpty(cmdOptions.ProbeTypes) {
+			ProbeTypes(columnExtractor(tables))
+			return
+		}
+		if cmdOptions.SingleTransaction {
+			BeginSingleTransaction()
+		}
+		ctx := interruptContext()
+		columns := tableMocker(ctx, tables)
+
+		// The single transaction has all or nothing, the rest of the
+		// interrupted runs still restore the constraints they removed
+		if ctx.Err() != nil && cmdOptions.SingleTransaction {
+			rollbackSingleTransaction("the run was interrupted")
+			return
+		}
+		if cmdOptions.VerifyForeignKeys && ctx.Err() == nil {
+			VerifyForeignKeys()
+		}
+		if !cmdOptions.IgnoreConstraint && !isFileOutput() {
+			activeBackend.restoreConstraints()
+		}
+		topUpReport(columns)
+		if cmdOptions.OverrideSequences && !isFileOutput() {
+			SyncSequences()
+		}
+		if !IsStringEmpty(cmdOptions.Manifest) && ctx.Err() != nil {
+			WriteManifest()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if cmdOptions.VerifyStats && len(columns) > 0 {
+			VerifyStats(columns)
+		}
+		if !IsStringEmpty(cmdOptions.ViolateConstraint) && len(columns) > 0 {
+			ViolateConstraint(columns)
+		}
+		if !IsStringEmpty(cmdOptions.ExportKeys) && len(columns) > 0 {
+			ExportKeySnapshot(columns)
+		}
+		if !IsStringEmpty(cmdOptions.Manifest) {
+			WriteManifest()
+		}
+		CommitSingleTransaction()
+		if !IsStringEmpty(cmdOptions.DocOutput) && len(columns) > 0 {
+			WriteDataDictionary(columns)
 		}
 	} else { // no tables found, explain that to the user and exit
 		Warn("No table available to mock the data, closing the program")
 	}
 }
 
-// Extract the column & Start the table mocking process
-func tableMocker(tables []DBTables) {
+// Extract the column & Start the table mocking process, returns the tables
+// that are mocked along with their columns
+func tableMocker(ctx context.Context, tables []DBTables) []TableCollection {
 	Info("Beginning the mocking process for the tables")
 
 	// Before beginning the process, recheck with the user
-	// they still want to continue
-	if !cmdOptions.DontPrompt {
+	// they still want to continue, the interactive selection is
+	// already a confirmation
+	if !cmdOptions.DontPrompt && !cmdOptions.Interactive && !isFileOutput() {
 		_ = YesOrNoConfirmation()
 	}
 
@@ -47,13 +135,27 @@ func tableMocker(tables []DBTables) {
 	// and its data types
 	columns := columnExtractor(tables)
 
+	// The NULLs on the generated data should match the existing data
+	if cmdOptions.NullabilityFromSample {
+		SampleNullFractions(columns)
+	}
+
+	// The tables without a key cannot use the key snapshot
+	if !IsStringEmpty(cmdOptions.ExportKeys) || !IsStringEmpty(cmdOptions.ImportKeys) {
+		checkSnapshotKeys(columns)
+	}
+
+	// The keys should have enough distinct values for the rows
+	checkUniqueCardinality(columns)
+
 	// If there is some tables in the list, then go through the
 	// next step, else print warning for the users
 	if len(columns) > 0 {
-		BackupConstraintsAndStartDataLoading(columns)
+		BackupConstraintsAndStartDataLoading(ctx, columns)
 	} else { // no tables
 		Warn("No columns available to mock the data, closing the program")
 	}
+	return columns
 }
 
 // Extract the column and its datatypes of the table
@@ -67,55 +169,100 @@ func columnExtractor(tables []DBTables) []TableCollection {
 
 	for _, t := range tables {
 		var tempColumns []DBColumns
-		if GreenplumOrPostgres == "postgres" {
-			columns = columnExtractorPostgres(fmt.Sprintf("\"%s\"", t.Schema), t.Table)
-		} else {
-			columns = columnExtractorGPDB(fmt.Sprintf("\"%s\"", t.Schema), t.Table)
+		columns = activeBackend.columns(t)
+
+		// The data types forced by the --columns-from-file
+		applyTypeOverrides(GenerateTableName(t.Table, t.Schema), columns)
+
+		// The columns left to their defaults, when its all of them the rows
+		// are inserted with only the default values
+		if selected := selectColumns(GenerateTableName(t.Table, t.Schema), columns); len(selected) < len(columns) {
+			if len(selected) == 0 {
+				tableListMutex.Lock()
+				oneColumnTable = append(oneColumnTable, GenerateTableName(t.Table, t.Schema))
+				tableListMutex.Unlock()
+			}
+			columns = selected
 		}
 
+		// The bounds of the integer rules fit the data types of the columns
+		checkIntRanges(GenerateTableName(t.Table, t.Schema), columns)
+
+		// The single column integer keys are filled with a counter
+		markSequentialKeys(GenerateTableName(t.Table, t.Schema), columns)
+
+		// The rest of the single column keys are kept unique across the run
+		markUniqueColumns(GenerateTableName(t.Table, t.Schema), columns)
+
 		// There are instance where the table can have one column and data type serial
 		// then lets save them for later loading via a different method
 		// take a look at the issue: https://github.com/pivotal-gss/mock-data/issues/29
-		if len(columns) == 1 {
+		if len(columns) == 1 && !cmdOptions.OverrideSequences {
 			checkIfOneColumnIsASerialDatatype(t, columns)
 		}
 
+		// The sequences of the columns generated with --override-sequences
+		recordOverriddenSequences(GenerateTableName(t.Table, t.Schema), columns)
+
 		// Loops through the columns and make a collection of tables
 		// & column, we ignore sequence since they are auto injected also
 		for _, c := range columns {
-			if !isItSerialDatatype(c) {
+			if !isLeftToSequence(GenerateTableName(t.Table, t.Schema), c) {
 				tempColumns = append(tempColumns, c)
 			}
 		}
 
+		// The citus tables are checked for how their rows spread on the shards
+		if GreenplumOrPostgres == "postgres" {
+			checkCitusTable(GenerateTableName(t.Table, t.Schema), tempColumns)
+		}
+
 		// ignore the table, that doesn't have columns
 		if len(tempColumns) > 0 {
 			collection = append(collection, TableCollection{t, tempColumns})
 		}
 		bar.Add(1)
 	}
-	return collection
+
+	// The foreign keys pick the keys of the tables they refer to
+	if cmdOptions.RespectFK {
+		respectForeignKeys(collection)
+	}
+
+	// The referenced tables are loaded before the tables referring to them
+	return orderByDependencies(collection)
 }
 
 // Backup and start the loading process
-func BackupConstraintsAndStartDataLoading(tables []TableCollection) {
-	// Backup the DDL first
-	BackupDDL()
+func BackupConstraintsAndStartDataLoading(ctx context.Context, tables []TableCollection) {
+	// Backup the DDL first, the constraints are left untouched
+	// when the data is written to files
+	if !isFileOutput() {
+		activeBackend.backupConstraints()
+	}
 	// Loop through the tables, splits the tables in schema
 	// & table and start loading
 	totalTables := len(tables)
 	Infof("Total numbers of tables to mock: %d", totalTables)
 	for _, t := range tables {
-		// Remove Constraints
-		table := GenerateTableName(t.Table, t.Schema)
-		RemoveConstraints(table)
-
-		// Start the committing data to the table
-		CommitData(t)
+		mockedTables = append(mockedTables, GenerateTableName(t.Table, t.Schema))
+	}
+	mockedTables = append(mockedTables, oneColumnTable...)
+	if cmdOptions.ConnectionPoolWarmup && !isFileOutput() {
+		WarmupConnections()
 	}
+	start := time.Now()
+	if cmdOptions.Parallel > 1 {
+		loadTablesInParallel(ctx, tables)
+	} else {
+		for _, t := range tables {
+			loadTable(ctx, t, nil)
+		}
+	}
+	rateLimitReport(time.Since(start))
 
 	// Now load the one column serial data type table
-	addDataIfItsASerialDatatype()
+	addDataIfItsASerialDatatype(ctx)
 
 	// If the program skipped the tables lets the users know
 	skipTablesWarning()
@@ -123,65 +270,334 @@ func BackupConstraintsAndStartDataLoading(tables []TableCollection) {
 	Infof("Completed loading mock data to %d tables", totalTables)
 }
 
-// Start Committing data to the database
-func CommitData(t TableCollection) {
+// Remove the constraints of the table and load it, the progress goes to
+// the shared bar if given else to a bar of the table. Once the run is
+// interrupted the tables are skipped untouched
+func loadTable(ctx context.Context, t TableCollection, shared *progressbar.ProgressBar) {
+	table := GenerateTableName(t.Table, t.Schema)
+	if ctx.Err() != nil {
+		skipTable(table, "the run was interrupted before it started")
+		if shared != nil {
+			shared.Add(rowsToMock(table))
+		}
+		return
+	}
+
+	// Remove Constraints
+	if !isFileOutput() {
+		activeBackend.removeConstraints(table)
+	}
+
+	// The references need the keys of the tables they refer to
+	if cmdOptions.RespectFK {
+		if reftab, ok := tableWithoutKeys(t); ok {
+			Warnf("Skipping the table %s since the table %s it refers to has no rows", table, reftab)
+			skipTable(table, fmt.Sprintf("the table %s it refers to has no rows", reftab))
+			if shared != nil {
+				shared.Add(rowsToMock(table))
+			}
+			return
+		}
+	}
+
+	// Run the before statements of the table from the rules file,
+	// the table is not loaded if they failed
+	if !isFileOutput() && !runTableHooks(table, "before") {
+		Warnf("Skipping the table %s since its before statements failed", table)
+		failTable(table, "its before statements failed")
+		if shared != nil {
+			shared.Add(rowsToMock(table))
+		}
+		return
+	}
+
+	// Remove the rows of the previous runs, with --transactional its
+	// done within the transaction of the table
+	if cmdOptions.Truncate && !cmdOptions.Transactional {
+		if err := truncateTable(executeStatement, table, cmdOptions.SingleTransaction); err != nil {
+			tableLoadFailed(table, err)
+			if shared != nil {
+				shared.Add(rowsToMock(table))
+			}
+			return
+		}
+	}
+
+	// Start the committing data to the table, the after statements
+	// are not run on the tables that failed
+	if !CommitData(ctx, t, shared) {
+		return
+	}
+
+	if !isFileOutput() && !runTableHooks(table, "after") {
+		failTable(table, "its after statements failed")
+	}
+}
+
+// Start Committing data to the database, the progress goes to the shared
+// bar if given else to a bar of the table. False if the table failed with
+// --on-error continue
+func CommitData(ctx context.Context, t TableCollection, shared *progressbar.ProgressBar) bool {
 	// Start committing data
 	tab := GenerateTableName(t.Table, t.Schema)
 	msg := fmt.Sprintf(progressBarMsg, tab)
-	bar := StartProgressBar(msg, cmdOptions.Rows)
-	Debugf("Building and loading mock data to the table %s", tab)
+	rows := rowsToMock(tab)
+	if rows == 0 {
+		Debugf("Table %s already has the rows of the --top-up-to target, skipping it", tab)
+		return true
+	}
+	bar := shared
+	if bar == nil {
+		bar = StartProgressBar(msg, rows)
+	}
+	Debugf("Building and loading %d rows of mock data to the table %s", rows, tab)
 
-	// Open db connection
-	db := ConnectDB()
-	defer db.Close()
+	// Column info
+	var col []string
+	for _, c := range t.Columns {
+		col = append(col, c.Column)
+	}
 
-	// Name the for loop to break when we encounter error
-DataTypePickerLoop:
-	// Loop through the row count and start loading the data
-	for i := 0; i < cmdOptions.Rows; i++ {
-		var data []string
-		var col []string
+	// Build the first row before opening the destination, so the tables
+	// with unsupported data types are skipped untouched
+	first, err := buildRow(t, tab)
+	if err != nil {
+		if strings.Contains(fmt.Sprint(err), "unsupported datatypes found") {
+			Debugf("Table %s skipped, since the %v", tab, err)
+			addSkippedTable(tab, err.Error())
+			bar.Add(rows)
+			return true
+		}
+		tableLoadFailed(tab, fmt.Errorf("building data: %v", err))
+		bar.Add(rows)
+		return false
+	}
+
+	// The columns with a sum target are adjusted per group, so all the rows
+	// are built before any of them is loaded
+	prebuilt := [][]string{first}
+	sums, err := sumRules(t, tab)
+	if err != nil {
+		Fatalf("Error in the sum_target rules of table %s: %v", tab, err)
+	}
+	if len(sums) > 0 {
+		Debugf("Building all the %d rows of the table %s to adjust the sums", rows, tab)
+		for len(prebuilt) < rows {
+			data, err := buildRow(t, tab)
+			if err != nil {
+				tableLoadFailed(tab, fmt.Errorf("building data: %v", err))
+				bar.Add(rows)
+				return false
+			}
+			prebuilt = append(prebuilt, data)
+		}
+		adjustSums(sums, prebuilt)
+	}
 
-		// Column info
-		for _, c := range t.Columns {
-			d, err := BuildData(c.Datatype)
+	// Split the rows between the workers, each worker has its own
+	// destination i.e its own database connection
+	workers := tableConcurrency(rows)
+	if workers > 1 {
+		Infof("Loading the table %s using %d concurrent workers", tab, workers)
+	}
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		count := rows / workers
+		if i < rows%workers {
+			count++
+		}
+		initial := prebuilt
+		if len(initial) > count {
+			initial = initial[:count]
+		}
+		prebuilt = prebuilt[len(initial):]
+		wg.Add(1)
+		go func(count int, initial [][]string) {
+			defer wg.Done()
+			acquireConnection()
+			defer releaseConnection()
+			errs <- loadRows(ctx, t, tab, col, count, initial, bar, sharedRateLimiter())
+		}(count, initial)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil && ctx.Err() != nil && cmdOptions.Transactional {
+			Warnf("Rolled back the table %s since the run was interrupted", tab)
+			failTable(tab, "the run was interrupted, its rows are rolled back")
+			return false
+		}
+		if err != nil {
+			tableLoadFailed(tab, err)
+			return false
+		}
+	}
+	return true
+}
+
+// Number of workers loading the table, the parquet file can only have
+// a single writer and the transaction of the table a single connection.
+// Its never more than the connections of --max-connections
+func tableConcurrency(rows int) int {
+	workers := cmdOptions.MaxConcurrencyPerTable
+	if isFileOutput() || cmdOptions.Transactional || workers < 1 {
+		workers = 1
+	}
+	if max := maxConnections(); workers > max {
+		workers = max
+	}
+	if workers > rows {
+		workers = rows
+	}
+	return workers
+}
+
+// Build and write the rows to a destination of their own, the initial
+// rows if given are written first and they are part of the count. With
+// --transactional an interrupt of the run rolls back the rows, else the
+// table is finished. The rows of the database are counted on the report
+// as their batches are flushed, so a failed table has the rows it kept,
+// the rows of the files and of --transactional once they're all written
+func loadRows(ctx context.Context, t TableCollection, tab string, col []string, count int, initial [][]string,
+	bar *progressbar.ProgressBar, limiter *rateLimiter) error {
+	w, err := newRowWriter(t, tab, col)
+	if err != nil {
+		return fmt.Errorf("opening the destination of the data: %v", err)
+	}
+	batched, isBatched := w.(batchedWriter)
+	if isBatched {
+		batched.Flushed(func(rows int) {
+			bar.Add(rows)
+			if !cmdOptions.Transactional {
+				reportWrittenRows(tab, rows)
+			}
+		})
+	}
+	for i := 0; i < count; i++ {
+		if cmdOptions.Transactional && ctx.Err() != nil {
+			abortWriter(w)
+			return ctx.Err()
+		}
+		var data []string
+		if i < len(initial) {
+			data = initial[i]
+		} else {
+			data, err = buildRow(t, tab)
 			if err != nil {
-				if strings.HasPrefix(fmt.Sprint(err), "unsupported datatypes found") {
-					Debugf("Table %s skipped, since the column %s, had unknown data type %s: %v",
-						tab, c.Column, c.Datatype, err)
-					skippedTab = append(skippedTab, tab)
-					bar.Add(cmdOptions.Rows)
-					break DataTypePickerLoop
-				} else {
-					Fatalf("Error when building data for table %s: %v", tab, err)
-				}
+				abortWriter(w)
+				return fmt.Errorf("building data: %v", err)
 			}
-			col = append(col, c.Column)
-			data = append(data, fmt.Sprintf("%v", d))
 		}
+		limiter.wait()
+		err = w.Write(data)
+		if err != nil {
+			abortWriter(w)
+			return fmt.Errorf("writing the data: %v", err)
+		}
+		recordSample(tab, data)
+		if !isBatched {
+			bar.Add(1)
+		}
+	}
+	if err = w.Close(); err != nil {
+		return fmt.Errorf("completing the data: %v", err)
+	}
+	if !isBatched || cmdOptions.Transactional {
+		reportWrittenRows(tab, count)
+	}
+	return nil
+}
 
-		// Copy the data to the table
-		CopyData(tab, col, data, db)
-		bar.Add(1)
+// Build a row of the table, the columns derived from the rest of the row are built last
+func buildRow(t TableCollection, tab string) ([]string, error) {
+	data := make([]string, len(t.Columns))
+	row := newRowContext()
+	for _, rowScoped := range []bool{false, true} {
+		for j, c := range t.Columns {
+			if isRowScoped(tab, c.Column) != rowScoped {
+				continue
+			}
+			if isNullValue(tab, c) {
+				data[j], row.Values[c.Column] = nullValue, nullValue
+				continue
+			}
+			if isNullFallback(tab, c) {
+				data[j], row.Values[c.Column] = nullValue, nullValue
+				continue
+			}
+			d, err := buildColumnData(tab, c, row)
+			if err != nil && c.IsNullable && strings.Contains(fmt.Sprint(err), "unsupported datatypes found") {
+				addNullFallback(tab, c)
+				data[j], row.Values[c.Column] = nullValue, nullValue
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("column %s with data type %s: %v", c.Column, c.Datatype, err)
+			}
+			data[j] = fmt.Sprintf("%v", d)
+			row.Values[c.Column] = data[j]
+		}
 	}
+	return data, nil
 }
 
-// Copy the data to the database table
-func CopyData(tab string, col []string, data []string, db *pg.DB) {
+// Copy the rows to the database table with a single COPY
+func CopyData(tab string, col []string, rows [][]string, db pg.DBI) error {
 	// Copy Statement and start loading
-	copyStatment := fmt.Sprintf(`COPY %s("%s") FROM STDIN WITH CSV DELIMITER '%s' QUOTE e'\x01'`,
+	copyStatment := fmt.Sprintf(`COPY %s("%s") FROM STDIN WITH CSV DELIMITER '%s' QUOTE e'\x01' NULL E'\\N'`,
 		tab, strings.Join(col, "\",\""), delimiter)
-	_, err := db.CopyFrom(strings.NewReader(strings.Join(data, delimiter)), copyStatment)
+	var data strings.Builder
+	for _, row := range rows {
+		data.WriteString(copyRow(row) + "\n")
+	}
+	_, err := db.CopyFrom(strings.NewReader(data.String()), copyStatment)
 
 	// Handle Error
 	if err != nil {
 		Debugf("Table: %s", tab)
 		Debugf("Copy Statement: %s", copyStatment)
-		Debugf("Data: %s", strings.Join(data, delimiter))
-		Fatalf("Error during committing data: %v", err)
+		if len(rows) == 1 {
+			Debugf("Data: %s", copyRow(rows[0]))
+		} else {
+			Debugf("Data: batch of %d rows", len(rows))
+		}
+		return fmt.Errorf("committing data: %v%s", err, rowSecurityHint(err))
 	}
+	return nil
 }
 
+// Check the delimiter can be written in the DELIMITER '<delimiter>' of the
+// COPY and read back by the CSV parser of the database
+func validateDelimiter(d string) error {
+	switch {
+	case len(d) != 1:
+		return fmt.Errorf("should be a single one byte character")
+	case strings.ContainsAny(d, "\x00\x01\\N.'"):
+		return fmt.Errorf("cannot be the quote (\\x01), a single quote, a NUL, a dot or a character of the NULL \\N")
+	case strings.TrimSpace(d) == "":
+		return fmt.Errorf("cannot be a whitespace or a newline")
+	}
+	return nil
+}
+
+// Format the row for the COPY, the NULLs are written as \N and the values
+// that could be misread by the CSV parser i.e the ones with the delimiter,
+// quote character, newlines, \N or \. are quoted
+func copyRow(data []string) string {
+	values := make([]string, len(data))
+	for i, d := range data {
+		switch {
+		case d == nullValue:
+			values[i] = `\N`
+		case d == `\N`, d == `\.`, strings.ContainsAny(d, delimiter+"\x01\n\r"):
+			values[i] = "\x01" + strings.Replace(d, "\x01", "\x01\x01", -1) + "\x01"
+		default:
+			values[i] = d
+		}
+	}
+	return strings.Join(values, delimiter)
+}
 
 // Check its a serial datatype
 func checkIfOneColumnIsASerialDatatype(t DBTables, c []DBColumns) {
@@ -191,49 +607,134 @@ func checkIfOneColumnIsASerialDatatype(t DBTables, c []DBColumns) {
 
 	// If they are save them for later use
 	if isItSerialDatatype(column) {
+		tableListMutex.Lock()
 		oneColumnTable = append(oneColumnTable, tab)
+		tableListMutex.Unlock()
 	}
 }
 
 // Insert data to the table if its only a single column with serial data type
-func addDataIfItsASerialDatatype() {
+func addDataIfItsASerialDatatype(ctx context.Context) {
+	// There is no data to write to files, the database generates it
+	if isFileOutput() && len(oneColumnTable) > 0 {
+		Warnf("These tables are skipped since they only have a serial column or columns left to their defaults "+
+			"whose data is generated by the database: %s", strings.Join(oneColumnTable, ","))
+		for _, t := range oneColumnTable {
+			skipTable(t, "its data is generated by the database, there is nothing to write to the files")
+		}
+		return
+	}
 	for _, t := range oneColumnTable {
+		if ctx.Err() != nil {
+			skipTable(t, "the run was interrupted before it started")
+			continue
+		}
+		if cmdOptions.Truncate {
+			if err := truncateTable(executeStatement, t, cmdOptions.SingleTransaction); err != nil {
+				tableLoadFailed(t, err)
+				continue
+			}
+		}
 		var total = 0
+		rows := rowsToMock(t)
 		// Start the progress bar
-		bar := StartProgressBar(fmt.Sprintf(progressBarMsg, t), cmdOptions.Rows)
+		bar := StartProgressBar(fmt.Sprintf(progressBarMsg, t), rows)
 		Debugf("Loading data for one column serial data type table %s", t)
 
 		// Start loading
-		for total < cmdOptions.Rows {
-			query := "INSERT INTO %s default values;"
-			query = fmt.Sprintf(query, t)
-			_, err := ExecuteDB(query)
-			if err != nil {
-				Fatalf("Error when loading the serial datatype for table %s, err: %v", t, err)
+		for total < rows {
+			if err := activeBackend.exec(activeBackend.insertDefaults(t)); err != nil {
+				tableLoadFailed(t, fmt.Errorf("inserting the serial: %v", err))
+				bar.Add(rows - total)
+				break
 			}
 			total++
 			bar.Add(1)
 		}
+		reportWrittenRows(t, total)
 	}
 }
 
-// Is it serial data type
+// Is it serial data type, or the auto increment column of the backend
 func isItSerialDatatype(c DBColumns) bool {
-	if strings.HasPrefix(c.Sequence, "nextval") {
+	if strings.HasPrefix(c.Sequence, "nextval") || c.Sequence == autoIncrement {
 		return true
 	}
 	return false
 }
 
+// Total rows to be mocked for the table, unless the table has its own
+// row count we use the global row count. With --top-up-to the count is
+// the target and only the rows missing from the table are mocked
+func rowsToMock(tab string) int {
+	rowsMutex.Lock()
+	defer rowsMutex.Unlock()
+	rows := cmdOptions.Rows
+	if cmdOptions.TopUpTo > 0 {
+		rows = cmdOptions.TopUpTo
+	}
+	if n, ok := rowsPerTable[tab]; ok {
+		rows = n
+	}
+	rows = jitterRows(tab, rows)
+	if limit, ok := uniqueRowLimits[tab]; ok && rows > limit {
+		rows = limit
+	}
+	if cmdOptions.TopUpTo > 0 {
+		rows = topUpRows(tab, rows)
+	}
+	return rows
+}
+
+// Vary the row count of the table randomly by the jitter fraction around
+// the target, the count is picked once per table and reused
+func jitterRows(tab string, rows int) int {
+	if cmdOptions.RowsJitter <= 0 {
+		return rows
+	}
+	if n, ok := jitteredRows[tab]; ok {
+		return n
+	}
+	n := rows + int(math.Round(float64(rows)*cmdOptions.RowsJitter*(2*r.Float64()-1)))
+	if n < 1 {
+		n = 1
+	}
+	Debugf("Row count of table %s with the jitter is %d", tab, n)
+	jitteredRows[tab] = n
+	return n
+}
+
 // Generate table name
 func GenerateTableName(tab, schema string) string {
 	return fmt.Sprintf("\"%s\".\"%s\"", schema, tab)
 }
 
+// Keep the table skipped for its unsupported data types
+func addSkippedTable(tab, unsupported string) {
+	tableListMutex.Lock()
+	defer tableListMutex.Unlock()
+	skippedTab = append(skippedTab, tab)
+	unsupportedTypes[tab] = unsupported
+}
+
+// Keep the table skipped for the reason, its already reported
+func skipTable(tab, reason string) {
+	tableListMutex.Lock()
+	defer tableListMutex.Unlock()
+	skippedTab = append(skippedTab, tab)
+	skipReasons[tab] = reason
+}
+
 // Throw warning if there is skipped tables
 func skipTablesWarning() {
-	if len(skippedTab) > 0 {
+	var unsupported []string
+	for _, tab := range skippedTab {
+		if _, ok := skipReasons[tab]; !ok {
+			unsupported = append(unsupported, tab)
+		}
+	}
+	if len(unsupported) > 0 {
 		Warnf("These tables are skipped since these data types are not supported by %s: %s",
-			programName, strings.Join(skippedTab, ","))
+			programName, strings.Join(unsupported, ","))
 	}
 }
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Parse the line of copyRow the way the COPY of CsvOptions reads it, the
+// quote and the escape are \x01 and an unquoted \N is the NULL
+func parseCopyRow(t *testing.T, line string) []string {
+	var values []string
+	var value strings.Builder
+	quoted, inQuotes := false, false
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case inQuotes && c == '\x01' && i+1 < len(line) && line[i+1] == '\x01':
+			value.WriteByte(c)
+			i++
+		case c == '\x01':
+			inQuotes, quoted = !inQuotes, true
+		case !inQuotes && strings.HasPrefix(line[i:], delimiter):
+			values = append(values, copyValue(value.String(), quoted))
+			value.Reset()
+			quoted = false
+			i += len(delimiter) - 1
+		case !inQuotes && (c == '\n' || c == '\r'):
+			t.Fatalf("unquoted newline in the COPY line %q", line)
+		default:
+			value.WriteByte(c)
+		}
+	}
+	if inQuotes {
+		t.Fatalf("unterminated quote in the COPY line %q", line)
+	}
+	return append(values, copyValue(value.String(), quoted))
+}
+
+func copyValue(value string, quoted bool) string {
+	if !quoted && value == `\N` {
+		return nullValue
+	}
+	return value
+}
+
+// Check the row goes through copyRow and comes back as it was
+func assertCopyRoundTrip(t *testing.T, data []string) string {
+	line := copyRow(data)
+	if got := parseCopyRow(t, line); !reflect.DeepEqual(got, data) {
+		t.Errorf("copyRow(%q) = %q, read back as %q", data, line, got)
+	}
+	return line
+}
+
+func TestBuildUuid(t *testing.T) {
+	v4 := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	defer func(seed int64) { cmdOptions.Seed = seed }(cmdOptions.Seed)
+	for _, seed := range []int64{0, 42} {
+		cmdOptions.Seed = seed
+		for i := 0; i < 100; i++ {
+			v, err := BuildData("uuid")
+			if err != nil {
+				t.Fatalf("BuildData(uuid): %v", err)
+			}
+			value := v.(string)
+			if !v4.MatchString(value) {
+				t.Fatalf("BuildData(uuid) = %q, want a version 4 UUID", value)
+			}
+			if line := assertCopyRoundTrip(t, []string{"1", value}); line != "1"+delimiter+value {
+				t.Errorf("copyRow quoted the uuid %q: %q", value, line)
+			}
+		}
+	}
+}
+
+func TestBuildJson(t *testing.T) {
+	for _, dt := range []string{"json", "jsonb"} {
+		for i := 0; i < 20; i++ {
+			v, err := BuildData(dt)
+			if err != nil {
+				t.Fatalf("BuildData(%s): %v", dt, err)
+			}
+			value := v.(string)
+			if !json.Valid([]byte(value)) {
+				t.Fatalf("BuildData(%s) = %q is not valid json", dt, value)
+			}
+			assertCopyRoundTrip(t, []string{"1", value, "x"})
+
+			// The delimiter and the quote byte inside the document
+			value = strings.Replace(value, "{", `{"price": "5$ or 6`+delimiter+`", "mark": "`+"\x01\x01x\x01"+`", `, 1)
+			line := assertCopyRoundTrip(t, []string{"1", value, "x"})
+			if !strings.Contains(line, delimiter+"\x01{") {
+				t.Errorf("copyRow did not quote the json with the delimiter: %q", line)
+			}
+		}
+	}
+}
+
+func TestValidateDelimiter(t *testing.T) {
+	for _, d := range []string{"$", ",", "|", ";", "~", "#"} {
+		if err := validateDelimiter(d); err != nil {
+			t.Errorf("validateDelimiter(%q) = %v, want it accepted", d, err)
+		}
+	}
+	for _, d := range []string{"", "$$", "é", "'", "\x01", "\x00", `\`, "N", ".", " ", "\t", "\n", "\r"} {
+		if err := validateDelimiter(d); err == nil {
+			t.Errorf("validateDelimiter(%q) accepted it, want an error", d)
+		}
+	}
+}
+
+func TestCopyRow(t *testing.T) {
+	defer func(d string) { delimiter = d }(delimiter)
+	for _, d := range []string{"$", ",", "|"} {
+		delimiter = d
+		for _, data := range [][]string{
+			{"1", "plain", "x"},
+			{"1", "costs 5$", "a,b|c"},
+			{"1", "the \x01quote\x01", "\x01"},
+			{"1", "two\nlines", "cr\r"},
+			{"1", nullValue, `\N`, `\.`, ""},
+			{`\`, "", "\\"},
+		} {
+			assertCopyRoundTrip(t, data)
+		}
+	}
+	delimiter = "$"
+	if got, want := copyRow([]string{"1", nullValue, "a$b", "c\x01d"}), "1$\\N$\x01a$b\x01$\x01c\x01\x01d\x01"; got != want {
+		t.Errorf("copyRow = %q, want %q", got, want)
+	}
+}
+
+func TestBuildBytea(t *testing.T) {
+	hexFormat := regexp.MustCompile(`^\\x([0-9a-f]{2})+$`)
+	defer func(length int) { cmdOptions.ByteaLength = length }(cmdOptions.ByteaLength)
+	cmdOptions.ByteaLength = 16
+	for i := 0; i < 100; i++ {
+		v, err := BuildData("bytea")
+		if err != nil {
+			t.Fatalf("BuildData(bytea): %v", err)
+		}
+		value := v.(string)
+		if !hexFormat.MatchString(value) || len(value) > 2+2*cmdOptions.ByteaLength {
+			t.Fatalf("BuildData(bytea) = %q, want \\x and 1 to %d hex bytes", value, cmdOptions.ByteaLength)
+		}
+		if line := assertCopyRoundTrip(t, []string{"1", value}); line != "1"+delimiter+value {
+			t.Errorf("copyRow quoted the bytea %q: %q", value, line)
+		}
+	}
+}
+
+func TestTableConcurrency(t *testing.T) {
+	defer func(o Command) { cmdOptions = o }(cmdOptions)
+	for _, c := range []struct {
+		parallel, perTable, max, rows, want int
+	}{
+		{1, 1, 0, 100, 1},
+		{1, 8, 0, 100, 8},
+		{4, 8, 0, 100, 8},
+		{4, 8, 6, 100, 6},
+		{4, 8, 6, 3, 3},
+		{4, 2, 6, 100, 2},
+	} {
+		cmdOptions.Parallel, cmdOptions.MaxConcurrencyPerTable, cmdOptions.MaxConnections =
+			c.parallel, c.perTable, c.max
+		if got := tableConcurrency(c.rows); got != c.want {
+			t.Errorf("tableConcurrency(%d) with --parallel %d --max-concurrency-per-table %d --max-connections %d = %d, want %d",
+				c.rows, c.parallel, c.perTable, c.max, got, c.want)
+		}
+	}
+}
+
+// Build the rows on a database/sql backend, which has no postgres catalog
+// for the enum labels or the citus tables to be read from
+func testSQLBackend(t *testing.T) {
+	b := activeBackend
+	activeBackend = sqlBackend{}
+	t.Cleanup(func() { activeBackend = b })
+}
+
+func TestBuildRowNullFallback(t *testing.T) {
+	log := testLog(t)
+	defer func(p int) { cmdOptions.NullPercent = p }(cmdOptions.NullPercent)
+	cmdOptions.NullPercent = 0
+	testSQLBackend(t)
+	tab := GenerateTableName("visibility", "public")
+	defer delete(nullFallbacks, ruleKey(tab, "odd"))
+	table := TableCollection{DBTables{Schema: "public", Table: "visibility"}, []DBColumns{
+		{Column: "id", Datatype: "integer"},
+		{Column: "odd", Datatype: "mystery", IsNullable: true},
+		{Column: "note", Datatype: "text"},
+	}}
+	for i := 0; i < 10; i++ {
+		data, err := buildRow(table, tab)
+		if err != nil {
+			t.Fatalf("buildRow: %v", err)
+		}
+		if data[0] == nullValue || data[1] != nullValue || data[2] == nullValue {
+			t.Fatalf("buildRow = %q, want the unsupported column NULL and the rest generated", data)
+		}
+		assertCopyRoundTrip(t, data)
+	}
+	if n := strings.Count(log.String(), "Data type mystery of the column odd"); n != 1 {
+		t.Errorf("warned %d times about the unsupported column, want once:\n%s", n, log)
+	}
+
+	// The NOT NULL columns can't fall back to NULLs
+	table.Columns[1].IsNullable = false
+	table.Table = "visibility_strict"
+	if _, err := buildRow(table, GenerateTableName(table.Table, table.Schema)); err == nil ||
+		!strings.Contains(err.Error(), "column odd with data type mystery") {
+		t.Errorf("buildRow of the NOT NULL unsupported column = %v, want an error naming the column", err)
+	}
+}
+
+func TestBuildRowSnapshot(t *testing.T) {
+	defer func(p int) { cmdOptions.NullPercent = p }(cmdOptions.NullPercent)
+	cmdOptions.NullPercent = 0
+	testSQLBackend(t)
+	snapshot := regexp.MustCompile(`^(\d+):(\d+):$`)
+	tab := GenerateTableName("snapshots", "public")
+	table := TableCollection{DBTables{Schema: "public", Table: "snapshots"}, []DBColumns{
+		{Column: "id", Datatype: "integer"},
+		{Column: "snap", Datatype: "pg_snapshot"},
+		{Column: "txid", Datatype: "txid_snapshot"},
+	}}
+	for i := 0; i < 100; i++ {
+		data, err := buildRow(table, tab)
+		if err != nil {
+			t.Fatalf("buildRow: %v", err)
+		}
+		for _, v := range data[1:] {
+			m := snapshot.FindStringSubmatch(v)
+			if m == nil {
+				t.Fatalf("buildRow = %q, snapshot %q is not xmin:xmax:", data, v)
+			}
+			xmin, _ := strconv.ParseInt(m[1], 10, 64)
+			xmax, _ := strconv.ParseInt(m[2], 10, 64)
+			if xmin > xmax {
+				t.Fatalf("snapshot %q has xmin past xmax", v)
+			}
+		}
+		assertCopyRoundTrip(t, data)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+func init() {
+	registerGenerator("zoned_time",
+		"Timestamps of the time zone (zone: <name> i.e Europe/Berlin) between the dates \"from: <yyyy-mm-dd>\" "+
+			"and \"to: <yyyy-mm-dd>\" (default the last year), the local times skipped by the daylight saving "+
+			"change are never generated and the repeated ones are always the first",
+		buildZonedTime)
+}
+
+// Validate the options of the zoned time rule
+func validateZonedTimeRule(c *ColumnRule) error {
+	if IsStringEmpty(c.Zone) {
+		return fmt.Errorf("zoned_time generator needs the zone i.e Europe/Berlin")
+	}
+	zone, err := time.LoadLocation(c.Zone)
+	if err != nil {
+		return fmt.Errorf("unknown zone \"%s\": %v", c.Zone, err)
+	}
+	c.zone = zone
+	now := referenceTime()
+	c.from = time.Date(now.Year()-1, now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	c.to = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	for _, d := range []struct {
+		value string
+		date  *time.Time
+	}{{c.From, &c.from}, {c.To, &c.to}} {
+		if IsStringEmpty(d.value) {
+			continue
+		}
+		if *d.date, err = time.Parse("2006-01-02", d.value); err != nil {
+			return fmt.Errorf("invalid date \"%s\", expected yyyy-mm-dd", d.value)
+		}
+	}
+	if !c.to.After(c.from) {
+		return fmt.Errorf("zoned_time from %s should be before to %s", c.from.Format("2006-01-02"),
+			c.to.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// Zoned time generator, a random local time of the window is resolved to
+// the instants of the zone it happens at. The ones in the gap of the spring
+// forward don't happen at all and are picked again, the ones in the overlap
+// of the fall back happen twice and the earlier one is taken
+func buildZonedTime(ctx *generatorContext) (interface{}, error) {
+	dt := ctx.Column.Datatype
+	if !strings.HasPrefix(dt, "timestamp") || strings.HasSuffix(dt, "[]") {
+		return "", fmt.Errorf("zoned_time generator only supports the timestamp columns, got %s", dt)
+	}
+	rule := ctx.Rule
+	window := rule.to.Sub(rule.from)
+	for tries := 0; tries < maxLoop; tries++ {
+		local := rule.from.Add(time.Duration(r.Int63n(int64(window/time.Second))) * time.Second)
+		instant, ok := resolveLocalTime(local, rule.zone)
+		if !ok {
+			continue
+		}
+		if strings.HasPrefix(dt, "timestamp with time zone") {
+			return instant.Format("2006-01-02 15:04:05-07:00"), nil
+		}
+		return instant.Format("2006-01-02 15:04:05"), nil
+	}
+	return "", fmt.Errorf("unable to generate a local time of the zone %s", rule.Zone)
+}
+
+// The earliest instant the local time (given as UTC) happens at on the zone,
+// false if the zone skips it. The offsets around it are the candidates
+func resolveLocalTime(local time.Time, zone *time.Location) (time.Time, bool) {
+	var earliest time.Time
+	found := false
+	for _, around := range []time.Duration{-24 * time.Hour, 0, 24 * time.Hour} {
+		_, offset := local.Add(around).In(zone).Zone()
+		instant := local.Add(-time.Duration(offset) * time.Second).In(zone)
+		y, m, d := instant.Date()
+		wall := time.Date(y, m, d, instant.Hour(), instant.Minute(), instant.Second(), 0, time.UTC)
+		if !wall.Equal(local) {
+			continue
+		}
+		if !found || instant.Before(earliest) {
+			earliest, found = instant, true
+		}
+	}
+	return earliest, found
+}